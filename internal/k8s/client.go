@@ -1,22 +1,77 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
-// GetKubernetesClient returns a Kubernetes dynamic client with in-cluster auth priority
-// Priority order:
-// 1. In-cluster config (service account token)
-// 2. KUBECONFIG environment variable
-// 3. ~/.kube/config file
+// ConnectionConfig describes how to reach the Kubernetes API server.
+// The zero value loads the kubeconfig from KUBECONFIG or ~/.kube/config.
+type ConnectionConfig struct {
+	// InCluster uses the pod's service account token. It is an explicit
+	// opt-in and cannot be combined with any other setting.
+	InCluster bool
+
+	Host                 string
+	Token                string
+	ClusterCACertificate string
+	ClientCertificate    string
+	ClientKey            string
+	Insecure             bool
+	TLSServerName        string
+	ProxyURL             string
+
+	// ConfigPaths are kubeconfig files merged in order. When empty, the
+	// KUBECONFIG environment variable and ~/.kube/config are used.
+	ConfigPaths           []string
+	ConfigContext         string
+	ConfigContextAuthInfo string
+	ConfigContextCluster  string
+
+	Exec *ExecConfig
+
+	// QPS and Burst configure client-side rate limiting. Zero values use
+	// DefaultQPS and DefaultBurst.
+	QPS   float32
+	Burst int
+}
+
+// Client-side rate limits used when QPS/Burst are not configured. Higher than
+// client-go's defaults (5/10) so that refreshing hundreds of actions is not
+// throttled on the client, while still bounded to protect the apiserver.
+const (
+	DefaultQPS   float32 = 20
+	DefaultBurst int     = 40
+)
+
+// ExecConfig configures a client-go credential plugin, e.g. `aws eks get-token`
+type ExecConfig struct {
+	APIVersion string
+	Command    string
+	Args       []string
+	Env        map[string]string
+}
+
+// GetKubernetesClient returns a Kubernetes dynamic client using the default
+// connection config (KUBECONFIG environment variable, then ~/.kube/config).
 func GetKubernetesClient() (dynamic.Interface, error) {
-	config, err := getKubernetesConfig()
+	return NewDynamicClient(&ConnectionConfig{})
+}
+
+// NewDynamicClient returns a Kubernetes dynamic client for the given connection config
+func NewDynamicClient(cfg *ConnectionConfig) (dynamic.Interface, error) {
+	config, err := RESTConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
 	}
@@ -29,34 +84,168 @@ func GetKubernetesClient() (dynamic.Interface, error) {
 	return client, nil
 }
 
-// getKubernetesConfig returns the Kubernetes REST config with in-cluster priority
-func getKubernetesConfig() (*rest.Config, error) {
-	// Priority 1: Try in-cluster config (service account token)
-	config, err := rest.InClusterConfig()
-	if err == nil {
-		return config, nil
+// GetDiscoveryClient returns a Kubernetes discovery client using the default
+// connection config (KUBECONFIG environment variable, then ~/.kube/config).
+func GetDiscoveryClient() (discovery.DiscoveryInterface, error) {
+	return NewDiscoveryClient(&ConnectionConfig{})
+}
+
+// NewDiscoveryClient returns a Kubernetes discovery client for the given connection config
+func NewDiscoveryClient(cfg *ConnectionConfig) (discovery.DiscoveryInterface, error) {
+	config, err := RESTConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
+	}
+
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes discovery client: %w", err)
+	}
+
+	return client, nil
+}
+
+// inClusterConfig loads the in-cluster config. Replaced in tests, which never
+// run in a pod.
+var inClusterConfig = rest.InClusterConfig
+
+// RESTConfig builds the REST config for the given connection config.
+//
+// In-cluster config is only used when InCluster is set. Otherwise the
+// kubeconfig files (ConfigPaths, or KUBECONFIG / ~/.kube/config) are loaded
+// and the explicit settings (host, token, certificates, exec, ...) are layered
+// on top, so a fully explicit configuration works without any kubeconfig file.
+func RESTConfig(cfg *ConnectionConfig) (*rest.Config, error) {
+	if cfg == nil {
+		cfg = &ConnectionConfig{}
 	}
 
-	// Priority 2: Try KUBECONFIG environment variable
-	kubeconfigEnv := os.Getenv("KUBECONFIG")
-	if kubeconfigEnv != "" {
-		config, err := clientcmd.BuildConfigFromFlags("", kubeconfigEnv)
-		if err == nil {
-			return config, nil
+	if cfg.InCluster {
+		if cfg.hasExplicitSettings() {
+			return nil, fmt.Errorf("in_cluster cannot be combined with host, token, certificates, config_path(s), config_context or exec")
 		}
+		config, err := inClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load in-cluster config: %w", err)
+		}
+		cfg.applyRateLimits(config)
+		return config, nil
 	}
 
-	// Priority 3: Try default kubeconfig path (~/.kube/config)
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		kubeconfigPath := filepath.Join(homeDir, ".kube", "config")
-		if _, err := os.Stat(kubeconfigPath); err == nil {
-			config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-			if err == nil {
-				return config, nil
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(cfg.ConfigPaths) > 0 {
+		paths := make([]string, 0, len(cfg.ConfigPaths))
+		for _, p := range cfg.ConfigPaths {
+			expanded, err := expandPath(p)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := os.Stat(expanded); err != nil {
+				return nil, fmt.Errorf("kubeconfig %q: %w", p, err)
 			}
+			paths = append(paths, expanded)
 		}
+		loadingRules = &clientcmd.ClientConfigLoadingRules{Precedence: paths}
 	}
 
-	return nil, fmt.Errorf("unable to load kubernetes config: tried in-cluster, KUBECONFIG env, and ~/.kube/config")
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: cfg.ConfigContext,
+		Context: clientcmdapi.Context{
+			AuthInfo: cfg.ConfigContextAuthInfo,
+			Cluster:  cfg.ConfigContextCluster,
+		},
+		ClusterInfo: clientcmdapi.Cluster{
+			Server:                cfg.Host,
+			InsecureSkipTLSVerify: cfg.Insecure,
+			TLSServerName:         cfg.TLSServerName,
+			ProxyURL:              cfg.ProxyURL,
+		},
+		AuthInfo: clientcmdapi.AuthInfo{
+			Token: cfg.Token,
+		},
+	}
+	if cfg.ClusterCACertificate != "" {
+		overrides.ClusterInfo.CertificateAuthorityData = []byte(cfg.ClusterCACertificate)
+	}
+	if cfg.ClientCertificate != "" {
+		overrides.AuthInfo.ClientCertificateData = []byte(cfg.ClientCertificate)
+	}
+	if cfg.ClientKey != "" {
+		overrides.AuthInfo.ClientKeyData = []byte(cfg.ClientKey)
+	}
+	if cfg.Exec != nil {
+		exec := &clientcmdapi.ExecConfig{
+			APIVersion:      cfg.Exec.APIVersion,
+			Command:         cfg.Exec.Command,
+			Args:            cfg.Exec.Args,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+		for name, value := range cfg.Exec.Env {
+			exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: name, Value: value})
+		}
+		overrides.AuthInfo.Exec = exec
+	}
+
+	// Load the kubeconfig files and build the config from them directly. The
+	// deferred loading client config would fall back to in-cluster config
+	// when nothing is configured, which must stay opt-in.
+	rawConfig, err := loadingRules.Load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubernetes config: %w", err)
+	}
+	config, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, overrides.CurrentContext, overrides, loadingRules).ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, fmt.Errorf("unable to load kubernetes config: no kubeconfig found (KUBECONFIG, ~/.kube/config) " +
+				"and no host configured. Configure the provider's kubernetes block, or set in_cluster = true " +
+				"when running inside a pod")
+		}
+		return nil, fmt.Errorf("unable to load kubernetes config: %w", err)
+	}
+	cfg.applyRateLimits(config)
+	return config, nil
+}
+
+// applyRateLimits sets QPS and Burst on the REST config, falling back to the defaults
+func (c *ConnectionConfig) applyRateLimits(config *rest.Config) {
+	config.QPS = DefaultQPS
+	if c.QPS > 0 {
+		config.QPS = c.QPS
+	}
+	config.Burst = DefaultBurst
+	if c.Burst > 0 {
+		config.Burst = c.Burst
+	}
+}
+
+// hasExplicitSettings reports whether any setting other than InCluster is set
+func (c *ConnectionConfig) hasExplicitSettings() bool {
+	return c.Host != "" || c.Token != "" || c.ClusterCACertificate != "" ||
+		c.ClientCertificate != "" || c.ClientKey != "" || c.Insecure || c.TLSServerName != "" ||
+		c.ProxyURL != "" || len(c.ConfigPaths) > 0 || c.ConfigContext != "" ||
+		c.ConfigContextAuthInfo != "" || c.ConfigContextCluster != "" || c.Exec != nil
+}
+
+// expandPath expands a leading ~ to the user's home directory
+func expandPath(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to expand %q: %w", p, err)
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~")), nil
+}
+
+// KubeSystemUID returns the UID of the kube-system namespace. It is stable for
+// the lifetime of a cluster and unique across clusters, which makes it a
+// reasonable cluster identifier when none is configured.
+func KubeSystemUID(ctx context.Context, client dynamic.Interface) (string, error) {
+	namespaceGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	ns, err := client.Resource(namespaceGVR).Get(ctx, "kube-system", metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to read kube-system namespace: %w", err)
+	}
+	return string(ns.GetUID()), nil
 }
@@ -0,0 +1,479 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+users:
+- name: dev-user
+  user:
+    token: dev-token
+- name: prod-user
+  user:
+    token: prod-token
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev-user
+- name: prod
+  context:
+    cluster: prod
+    user: prod-user
+`
+
+// isolateKubeconfig points KUBECONFIG and HOME at an empty temp directory so
+// tests never pick up the developer's real kubeconfig.
+func isolateKubeconfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("KUBECONFIG", filepath.Join(dir, "missing"))
+	return dir
+}
+
+func writeKubeconfig(t *testing.T, dir string) string {
+	t.Helper()
+	p := filepath.Join(dir, "config")
+	if err := os.WriteFile(p, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("write kubeconfig: %v", err)
+	}
+	return p
+}
+
+func TestRESTConfig_ExplicitHostAndToken(t *testing.T) {
+	isolateKubeconfig(t)
+
+	config, err := RESTConfig(&ConnectionConfig{
+		Host:     "https://api.example.com",
+		Token:    "secret",
+		Insecure: true,
+		ProxyURL: "http://proxy.example.com:3128",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://api.example.com" {
+		t.Errorf("Host = %q", config.Host)
+	}
+	if config.BearerToken != "secret" {
+		t.Errorf("BearerToken = %q", config.BearerToken)
+	}
+	if !config.Insecure {
+		t.Error("expected Insecure to be set")
+	}
+	if config.Proxy == nil {
+		t.Error("expected Proxy to be set from proxy_url")
+	}
+	if config.QPS != DefaultQPS || config.Burst != DefaultBurst {
+		t.Errorf("QPS/Burst = %v/%v, want defaults", config.QPS, config.Burst)
+	}
+}
+
+func TestRESTConfig_RateLimits(t *testing.T) {
+	isolateKubeconfig(t)
+
+	config, err := RESTConfig(&ConnectionConfig{Host: "https://api.example.com", QPS: 50, Burst: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.QPS != 50 || config.Burst != 100 {
+		t.Errorf("QPS/Burst = %v/%v, want 50/100", config.QPS, config.Burst)
+	}
+}
+
+func TestRESTConfig_ConfigPathAndContext(t *testing.T) {
+	dir := isolateKubeconfig(t)
+	p := writeKubeconfig(t, dir)
+
+	config, err := RESTConfig(&ConnectionConfig{ConfigPaths: []string{p}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://dev.example.com" || config.BearerToken != "dev-token" {
+		t.Errorf("expected current-context dev, got host=%q token=%q", config.Host, config.BearerToken)
+	}
+
+	config, err = RESTConfig(&ConnectionConfig{ConfigPaths: []string{p}, ConfigContext: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://prod.example.com" || config.BearerToken != "prod-token" {
+		t.Errorf("expected context prod, got host=%q token=%q", config.Host, config.BearerToken)
+	}
+}
+
+func TestRESTConfig_ExplicitSettingsOverrideKubeconfig(t *testing.T) {
+	dir := isolateKubeconfig(t)
+	p := writeKubeconfig(t, dir)
+
+	config, err := RESTConfig(&ConnectionConfig{
+		ConfigPaths: []string{p},
+		Host:        "https://override.example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://override.example.com" {
+		t.Errorf("Host = %q, want override", config.Host)
+	}
+	if config.BearerToken != "dev-token" {
+		t.Errorf("BearerToken = %q, want token from kubeconfig", config.BearerToken)
+	}
+}
+
+func TestRESTConfig_ExpandsHomeInConfigPath(t *testing.T) {
+	dir := isolateKubeconfig(t)
+	writeKubeconfig(t, dir)
+
+	config, err := RESTConfig(&ConnectionConfig{ConfigPaths: []string{"~/config"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://dev.example.com" {
+		t.Errorf("Host = %q", config.Host)
+	}
+}
+
+func TestRESTConfig_MissingConfigPath(t *testing.T) {
+	dir := isolateKubeconfig(t)
+
+	_, err := RESTConfig(&ConnectionConfig{ConfigPaths: []string{filepath.Join(dir, "nope")}})
+	if err == nil {
+		t.Fatal("expected error for missing config_path")
+	}
+}
+
+func TestRESTConfig_NoConfigSuggestsInCluster(t *testing.T) {
+	isolateKubeconfig(t)
+
+	_, err := RESTConfig(&ConnectionConfig{})
+	if err == nil {
+		t.Fatal("expected error without any kubeconfig")
+	}
+	if !strings.Contains(err.Error(), "in_cluster = true") {
+		t.Errorf("expected error to mention in_cluster, got: %v", err)
+	}
+}
+
+// stubInClusterConfig replaces the in-cluster config with one that works
+// outside a pod, and returns how often it was loaded
+func stubInClusterConfig(t *testing.T) *int {
+	t.Helper()
+	calls := 0
+	previous := inClusterConfig
+	inClusterConfig = func() (*rest.Config, error) {
+		calls++
+		return &rest.Config{Host: "https://10.0.0.1:443"}, nil
+	}
+	t.Cleanup(func() { inClusterConfig = previous })
+	return &calls
+}
+
+func TestRESTConfig_InClusterIsOptIn(t *testing.T) {
+	isolateKubeconfig(t)
+	calls := stubInClusterConfig(t)
+	// Simulate a pod environment: without in_cluster the service account
+	// must not be used, even though KUBERNETES_SERVICE_HOST is set.
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+
+	_, err := RESTConfig(&ConnectionConfig{})
+	if err == nil || !strings.Contains(err.Error(), "no kubeconfig found") {
+		t.Errorf("expected kubeconfig error, got: %v", err)
+	}
+	if *calls != 0 {
+		t.Errorf("expected in-cluster config not to be loaded, loaded %d times", *calls)
+	}
+
+	config, err := RESTConfig(&ConnectionConfig{InCluster: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *calls != 1 || config.Host != "https://10.0.0.1:443" {
+		t.Errorf("expected the in-cluster config with in_cluster = true, got %s after %d loads", config.Host, *calls)
+	}
+}
+
+func TestRESTConfig_InClusterConflicts(t *testing.T) {
+	_, err := RESTConfig(&ConnectionConfig{InCluster: true, Host: "https://api.example.com"})
+	if err == nil || !strings.Contains(err.Error(), "in_cluster") {
+		t.Errorf("expected in_cluster conflict error, got: %v", err)
+	}
+}
+
+func TestRESTConfig_Exec(t *testing.T) {
+	isolateKubeconfig(t)
+
+	config, err := RESTConfig(&ConnectionConfig{
+		Host: "https://api.example.com",
+		Exec: &ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "aws",
+			Args:       []string{"eks", "get-token"},
+			Env:        map[string]string{"AWS_PROFILE": "prod"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.ExecProvider == nil {
+		t.Fatal("expected ExecProvider to be set")
+	}
+	if config.ExecProvider.Command != "aws" || len(config.ExecProvider.Args) != 2 {
+		t.Errorf("unexpected exec provider: %+v", config.ExecProvider)
+	}
+	if len(config.ExecProvider.Env) != 1 || config.ExecProvider.Env[0].Name != "AWS_PROFILE" {
+		t.Errorf("unexpected exec env: %+v", config.ExecProvider.Env)
+	}
+}
+
+var (
+	testExecAttrTypes = map[string]attr.Type{
+		"api_version": types.StringType,
+		"command":     types.StringType,
+		"args":        types.ListType{ElemType: types.StringType},
+		"env":         types.MapType{ElemType: types.StringType},
+	}
+	testKubernetesAttrTypes = map[string]attr.Type{
+		"in_cluster":               types.BoolType,
+		"host":                     types.StringType,
+		"token":                    types.StringType,
+		"cluster_ca_certificate":   types.StringType,
+		"client_certificate":       types.StringType,
+		"client_key":               types.StringType,
+		"insecure":                 types.BoolType,
+		"tls_server_name":          types.StringType,
+		"proxy_url":                types.StringType,
+		"config_path":              types.StringType,
+		"config_paths":             types.ListType{ElemType: types.StringType},
+		"config_context":           types.StringType,
+		"config_context_auth_info": types.StringType,
+		"config_context_cluster":   types.StringType,
+		"exec":                     types.ObjectType{AttrTypes: testExecAttrTypes},
+		"qps":                      types.Float64Type,
+		"burst":                    types.Int64Type,
+	}
+)
+
+// testKubernetesObject builds a kubernetes block value with every attribute
+// null except the ones in values.
+func testKubernetesObject(t *testing.T, values map[string]attr.Value) types.Object {
+	t.Helper()
+	attrs := make(map[string]attr.Value, len(testKubernetesAttrTypes))
+	for name, typ := range testKubernetesAttrTypes {
+		switch tt := typ.(type) {
+		case types.ListType:
+			attrs[name] = types.ListNull(tt.ElemType)
+		case types.ObjectType:
+			attrs[name] = types.ObjectNull(tt.AttrTypes)
+		default:
+			switch typ {
+			case types.BoolType:
+				attrs[name] = types.BoolNull()
+			case types.Float64Type:
+				attrs[name] = types.Float64Null()
+			case types.Int64Type:
+				attrs[name] = types.Int64Null()
+			default:
+				attrs[name] = types.StringNull()
+			}
+		}
+	}
+	for name, v := range values {
+		attrs[name] = v
+	}
+	obj, diags := types.ObjectValue(testKubernetesAttrTypes, attrs)
+	if diags.HasError() {
+		t.Fatalf("build kubernetes object: %v", diags)
+	}
+	return obj
+}
+
+func TestGetConnectionConfig_NullBlock(t *testing.T) {
+	cfg, err := GetConnectionConfig(context.Background(), &ProviderModel{
+		Kubernetes: types.ObjectNull(testKubernetesAttrTypes),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.InCluster || cfg.hasExplicitSettings() {
+		t.Errorf("expected default config, got %+v", cfg)
+	}
+}
+
+func TestGetConnectionConfig_AllSettings(t *testing.T) {
+	paths, _ := types.ListValueFrom(context.Background(), types.StringType, []string{"/a", "/b"})
+	args, _ := types.ListValueFrom(context.Background(), types.StringType, []string{"eks", "get-token"})
+	env, _ := types.MapValueFrom(context.Background(), types.StringType, map[string]string{"AWS_PROFILE": "prod"})
+	exec, diags := types.ObjectValue(testExecAttrTypes, map[string]attr.Value{
+		"api_version": types.StringValue("client.authentication.k8s.io/v1beta1"),
+		"command":     types.StringValue("aws"),
+		"args":        args,
+		"env":         env,
+	})
+	if diags.HasError() {
+		t.Fatalf("build exec object: %v", diags)
+	}
+
+	cfg, err := GetConnectionConfig(context.Background(), &ProviderModel{
+		Kubernetes: testKubernetesObject(t, map[string]attr.Value{
+			"host":           types.StringValue("https://api.example.com"),
+			"token":          types.StringValue("secret"),
+			"insecure":       types.BoolValue(true),
+			"config_paths":   paths,
+			"config_context": types.StringValue("prod"),
+			"exec":           exec,
+			"qps":            types.Float64Value(50),
+			"burst":          types.Int64Value(100),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Host != "https://api.example.com" || cfg.Token != "secret" || !cfg.Insecure {
+		t.Errorf("unexpected connection settings: %+v", cfg)
+	}
+	if len(cfg.ConfigPaths) != 2 || cfg.ConfigContext != "prod" {
+		t.Errorf("unexpected kubeconfig settings: %+v", cfg)
+	}
+	if cfg.QPS != 50 || cfg.Burst != 100 {
+		t.Errorf("QPS/Burst = %v/%v, want 50/100", cfg.QPS, cfg.Burst)
+	}
+	if cfg.Exec == nil || cfg.Exec.Command != "aws" || len(cfg.Exec.Args) != 2 || cfg.Exec.Env["AWS_PROFILE"] != "prod" {
+		t.Errorf("unexpected exec settings: %+v", cfg.Exec)
+	}
+}
+
+func TestGetConnectionConfig_ConfigPath(t *testing.T) {
+	cfg, err := GetConnectionConfig(context.Background(), &ProviderModel{
+		Kubernetes: testKubernetesObject(t, map[string]attr.Value{
+			"config_path": types.StringValue("/tmp/kubeconfig"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.ConfigPaths) != 1 || cfg.ConfigPaths[0] != "/tmp/kubeconfig" {
+		t.Errorf("ConfigPaths = %v", cfg.ConfigPaths)
+	}
+}
+
+func TestGetConnectionConfig_Errors(t *testing.T) {
+	paths, _ := types.ListValueFrom(context.Background(), types.StringType, []string{"/a"})
+
+	tests := []struct {
+		name   string
+		values map[string]attr.Value
+		want   string
+	}{
+		{
+			name: "in_cluster with host",
+			values: map[string]attr.Value{
+				"in_cluster": types.BoolValue(true),
+				"host":       types.StringValue("https://api.example.com"),
+			},
+			want: "in_cluster",
+		},
+		{
+			name: "in_cluster with config_path",
+			values: map[string]attr.Value{
+				"in_cluster":  types.BoolValue(true),
+				"config_path": types.StringValue("/a"),
+			},
+			want: "in_cluster",
+		},
+		{
+			name: "config_path and config_paths",
+			values: map[string]attr.Value{
+				"config_path":  types.StringValue("/a"),
+				"config_paths": paths,
+			},
+			want: "config_paths",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetConnectionConfig(context.Background(), &ProviderModel{
+				Kubernetes: testKubernetesObject(t, tt.values),
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGetConnectionConfig_Unknown(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubernetes func(t *testing.T) types.Object
+	}{
+		{
+			name: "unknown block",
+			kubernetes: func(t *testing.T) types.Object {
+				return types.ObjectUnknown(testKubernetesAttrTypes)
+			},
+		},
+		{
+			name: "unknown host",
+			kubernetes: func(t *testing.T) types.Object {
+				return testKubernetesObject(t, map[string]attr.Value{
+					"host":  types.StringUnknown(),
+					"token": types.StringValue("secret"),
+				})
+			},
+		},
+		{
+			name: "unknown exec",
+			kubernetes: func(t *testing.T) types.Object {
+				return testKubernetesObject(t, map[string]attr.Value{
+					"host": types.StringValue("https://api.example.com"),
+					"exec": types.ObjectUnknown(testExecAttrTypes),
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetConnectionConfig(context.Background(), &ProviderModel{Kubernetes: tt.kubernetes(t)})
+			if err == nil || !strings.Contains(err.Error(), "not known yet") {
+				t.Errorf("expected a not known yet error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetConnectionConfig_InClusterOnly(t *testing.T) {
+	cfg, err := GetConnectionConfig(context.Background(), &ProviderModel{
+		Kubernetes: testKubernetesObject(t, map[string]attr.Value{
+			"in_cluster": types.BoolValue(true),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.InCluster {
+		t.Error("expected InCluster to be set")
+	}
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// ProviderModel represents the Facets provider configuration
+// Note: This duplicates the structure from internal/provider to avoid import cycles
+type ProviderModel struct {
+	Kubernetes types.Object `tfsdk:"kubernetes"`
+}
+
+// ProviderKubernetesConfig represents the kubernetes block of the provider
+type ProviderKubernetesConfig struct {
+	InCluster             types.Bool    `tfsdk:"in_cluster"`
+	Host                  types.String  `tfsdk:"host"`
+	Token                 types.String  `tfsdk:"token"`
+	ClusterCACertificate  types.String  `tfsdk:"cluster_ca_certificate"`
+	ClientCertificate     types.String  `tfsdk:"client_certificate"`
+	ClientKey             types.String  `tfsdk:"client_key"`
+	Insecure              types.Bool    `tfsdk:"insecure"`
+	TLSServerName         types.String  `tfsdk:"tls_server_name"`
+	ProxyURL              types.String  `tfsdk:"proxy_url"`
+	ConfigPath            types.String  `tfsdk:"config_path"`
+	ConfigPaths           types.List    `tfsdk:"config_paths"`
+	ConfigContext         types.String  `tfsdk:"config_context"`
+	ConfigContextAuthInfo types.String  `tfsdk:"config_context_auth_info"`
+	ConfigContextCluster  types.String  `tfsdk:"config_context_cluster"`
+	Exec                  types.Object  `tfsdk:"exec"`
+	QPS                   types.Float64 `tfsdk:"qps"`
+	Burst                 types.Int64   `tfsdk:"burst"`
+}
+
+// ProviderKubernetesExecConfig represents the exec block of the kubernetes configuration
+type ProviderKubernetesExecConfig struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Command    types.String `tfsdk:"command"`
+	Args       types.List   `tfsdk:"args"`
+	Env        types.Map    `tfsdk:"env"`
+}
+
+// GetConnectionConfig extracts the Kubernetes connection config from provider data.
+// A missing kubernetes block yields the default config (KUBECONFIG, then ~/.kube/config).
+//
+// Validation rules:
+// 1. in_cluster cannot be combined with any other setting
+// 2. config_path and config_paths are mutually exclusive
+// 3. exec requires api_version and command
+// 4. every attribute must be known
+//
+// This only inspects configuration; it never contacts the cluster.
+func GetConnectionConfig(ctx context.Context, providerModel *ProviderModel) (*ConnectionConfig, error) {
+	if providerModel == nil || providerModel.Kubernetes.IsNull() {
+		return &ConnectionConfig{}, nil
+	}
+	// An unknown attribute would otherwise read as unset and silently fall
+	// back to the kubeconfig, so the whole block must be known
+	if value, err := providerModel.Kubernetes.ToTerraformValue(ctx); err != nil || !value.IsFullyKnown() {
+		return nil, fmt.Errorf("kubernetes configuration is not known yet; it may depend on values " +
+			"that are only available after apply")
+	}
+
+	var kubeConfig ProviderKubernetesConfig
+	diags := providerModel.Kubernetes.As(ctx, &kubeConfig, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed to extract kubernetes configuration: %v", diags.Errors())
+	}
+
+	cfg := &ConnectionConfig{
+		InCluster:             kubeConfig.InCluster.ValueBool(),
+		Host:                  kubeConfig.Host.ValueString(),
+		Token:                 kubeConfig.Token.ValueString(),
+		ClusterCACertificate:  kubeConfig.ClusterCACertificate.ValueString(),
+		ClientCertificate:     kubeConfig.ClientCertificate.ValueString(),
+		ClientKey:             kubeConfig.ClientKey.ValueString(),
+		Insecure:              kubeConfig.Insecure.ValueBool(),
+		TLSServerName:         kubeConfig.TLSServerName.ValueString(),
+		ProxyURL:              kubeConfig.ProxyURL.ValueString(),
+		ConfigContext:         kubeConfig.ConfigContext.ValueString(),
+		ConfigContextAuthInfo: kubeConfig.ConfigContextAuthInfo.ValueString(),
+		ConfigContextCluster:  kubeConfig.ConfigContextCluster.ValueString(),
+		QPS:                   float32(kubeConfig.QPS.ValueFloat64()),
+		Burst:                 int(kubeConfig.Burst.ValueInt64()),
+	}
+
+	if !kubeConfig.ConfigPaths.IsNull() && !kubeConfig.ConfigPaths.IsUnknown() {
+		if kubeConfig.ConfigPath.ValueString() != "" {
+			return nil, fmt.Errorf("config_path and config_paths cannot both be set in the kubernetes block")
+		}
+		diags := kubeConfig.ConfigPaths.ElementsAs(ctx, &cfg.ConfigPaths, false)
+		if diags.HasError() {
+			return nil, fmt.Errorf("failed to extract config_paths: %v", diags.Errors())
+		}
+	} else if kubeConfig.ConfigPath.ValueString() != "" {
+		cfg.ConfigPaths = []string{kubeConfig.ConfigPath.ValueString()}
+	}
+
+	if !kubeConfig.Exec.IsNull() && !kubeConfig.Exec.IsUnknown() {
+		var execConfig ProviderKubernetesExecConfig
+		diags := kubeConfig.Exec.As(ctx, &execConfig, basetypes.ObjectAsOptions{})
+		if diags.HasError() {
+			return nil, fmt.Errorf("failed to extract exec configuration: %v", diags.Errors())
+		}
+		if execConfig.APIVersion.ValueString() == "" || execConfig.Command.ValueString() == "" {
+			return nil, fmt.Errorf("exec requires both api_version and command")
+		}
+
+		cfg.Exec = &ExecConfig{
+			APIVersion: execConfig.APIVersion.ValueString(),
+			Command:    execConfig.Command.ValueString(),
+		}
+		if !execConfig.Args.IsNull() {
+			diags := execConfig.Args.ElementsAs(ctx, &cfg.Exec.Args, false)
+			if diags.HasError() {
+				return nil, fmt.Errorf("failed to extract exec args: %v", diags.Errors())
+			}
+		}
+		if !execConfig.Env.IsNull() {
+			diags := execConfig.Env.ElementsAs(ctx, &cfg.Exec.Env, false)
+			if diags.HasError() {
+				return nil, fmt.Errorf("failed to extract exec env: %v", diags.Errors())
+			}
+		}
+	}
+
+	if cfg.InCluster && cfg.hasExplicitSettings() {
+		return nil, fmt.Errorf("in_cluster = true cannot be combined with other kubernetes settings " +
+			"(host, token, certificates, config_path(s), config_context or exec)")
+	}
+
+	return cfg, nil
+}
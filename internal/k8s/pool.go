@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// ClientPool shares Kubernetes clients between resources of a provider
+// instance. Clients are keyed by the effective connection config, so resources
+// that target the same cluster reuse one REST config, HTTP transport (and its
+// rate limiter and exec credential cache) and discovery cache instead of
+// re-reading kubeconfig on every CRUD call.
+//
+// Clients are built lazily on first use, so creating a pool never touches
+// kubeconfig or the cluster and terraform validate stays credential-free.
+// A ClientPool is safe for concurrent use.
+type ClientPool struct {
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+}
+
+// poolEntry holds the clients built for one connection config
+type poolEntry struct {
+	dynamic   dynamic.Interface
+	discovery discovery.CachedDiscoveryInterface
+}
+
+// NewClientPool returns an empty client pool
+func NewClientPool() *ClientPool {
+	return &ClientPool{entries: make(map[string]*poolEntry)}
+}
+
+// DynamicClient returns the shared dynamic client for the given connection config
+func (p *ClientPool) DynamicClient(cfg *ConnectionConfig) (dynamic.Interface, error) {
+	entry, err := p.entry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return entry.dynamic, nil
+}
+
+// DiscoveryClient returns the shared, memory-cached discovery client for the
+// given connection config. API group and resource lists are fetched once per
+// provider run; call Invalidate on the result to force a refresh.
+func (p *ClientPool) DiscoveryClient(cfg *ConnectionConfig) (discovery.CachedDiscoveryInterface, error) {
+	entry, err := p.entry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return entry.discovery, nil
+}
+
+// entry returns the pool entry for cfg, building it on first use. Failed builds
+// are not cached, so a later call can succeed once the kubeconfig is fixed.
+func (p *ClientPool) entry(cfg *ConnectionConfig) (*poolEntry, error) {
+	if cfg == nil {
+		cfg = &ConnectionConfig{}
+	}
+	key, err := cfg.cacheKey()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.entries[key]; ok {
+		return entry, nil
+	}
+
+	entry, err := newPoolEntry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.entries[key] = entry
+	return entry, nil
+}
+
+// newPoolEntry builds the REST config and the clients sharing one HTTP client.
+// None of these calls contact the apiserver.
+func newPoolEntry(cfg *ConnectionConfig) (*poolEntry, error) {
+	config, err := RESTConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
+	}
+
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes HTTP client: %w", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes discovery client: %w", err)
+	}
+
+	return &poolEntry{
+		dynamic:   dynamicClient,
+		discovery: memory.NewMemCacheClient(discoveryClient),
+	}, nil
+}
+
+// cacheKey identifies the effective connection config. It is a hash so that
+// tokens and keys are not kept around as map keys in plain text.
+func (c *ConnectionConfig) cacheKey() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute client cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestClientPool_ReusesClientsPerConnectionConfig(t *testing.T) {
+	isolateKubeconfig(t)
+	pool := NewClientPool()
+
+	a1, err := pool.DynamicClient(&ConnectionConfig{Host: "https://a.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a2, err := pool.DynamicClient(&ConnectionConfig{Host: "https://a.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := pool.DynamicClient(&ConnectionConfig{Host: "https://b.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a1 != a2 {
+		t.Error("expected the same client for equal connection configs")
+	}
+	if a1 == b {
+		t.Error("expected different clients for different connection configs")
+	}
+
+	// Rate limits are part of the key: they are baked into the transport
+	c, err := pool.DynamicClient(&ConnectionConfig{Host: "https://a.example.com", QPS: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c == a1 {
+		t.Error("expected a different client for a different QPS")
+	}
+}
+
+func TestClientPool_DiscoveryIsShared(t *testing.T) {
+	isolateKubeconfig(t)
+	pool := NewClientPool()
+	cfg := &ConnectionConfig{Host: "https://a.example.com"}
+
+	d1, err := pool.DiscoveryClient(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d2, err := pool.DiscoveryClient(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d1 != d2 {
+		t.Error("expected the same cached discovery client")
+	}
+}
+
+func TestClientPool_ErrorsAreNotCached(t *testing.T) {
+	dir := isolateKubeconfig(t)
+	pool := NewClientPool()
+	cfg := &ConnectionConfig{ConfigPaths: []string{dir + "/config"}}
+
+	if _, err := pool.DynamicClient(cfg); err == nil {
+		t.Fatal("expected error for missing kubeconfig")
+	}
+
+	writeKubeconfig(t, dir)
+	if _, err := pool.DynamicClient(cfg); err != nil {
+		t.Fatalf("expected success once the kubeconfig exists, got: %v", err)
+	}
+}
+
+func TestClientPool_ConcurrentUse(t *testing.T) {
+	isolateKubeconfig(t)
+	pool := NewClientPool()
+
+	const workers = 32
+	var wg sync.WaitGroup
+	results := make([]interface{}, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client, err := pool.DynamicClient(&ConnectionConfig{Host: "https://a.example.com"})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			results[i] = client
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i < workers; i++ {
+		if results[i] != results[0] {
+			t.Fatal("expected every goroutine to receive the same client")
+		}
+	}
+	if len(pool.entries) != 1 {
+		t.Errorf("pool has %d entries, want 1", len(pool.entries))
+	}
+}
@@ -0,0 +1,265 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// ClusterScopeDir is the directory of cluster-scoped objects in a render
+// directory, in place of a namespace
+const ClusterScopeDir = "_cluster"
+
+// NewRenderClient returns a dynamic.Interface that writes objects as YAML
+// files below dir instead of sending them to an apiserver, for clusters whose
+// objects are delivered by GitOps. Each object is stored at
+// <dir>/<namespace>/<resource>/<name>.yaml, e.g.
+// tekton-pipelines/tasks/<name>.yaml; cluster-scoped objects use
+// ClusterScopeDir as the namespace.
+//
+// Create, Update, Delete, Get and List behave like the apiserver's, including
+// AlreadyExists and NotFound errors, so code written against a cluster works
+// unchanged. Other verbs are not supported. Secrets are refused, since their
+// values would be written to the directory in plain text.
+func NewRenderClient(dir string) dynamic.Interface {
+	return &renderClient{dir: dir}
+}
+
+type renderClient struct {
+	dir string
+}
+
+func (c *renderClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &renderResource{dir: c.dir, gvr: gvr}
+}
+
+// renderResource is the dynamic.ResourceInterface of one resource and namespace
+type renderResource struct {
+	dir       string
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func (r *renderResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &renderResource{dir: r.dir, gvr: r.gvr, namespace: namespace}
+}
+
+// resourceDir returns the directory holding the resource's objects
+func (r *renderResource) resourceDir() string {
+	namespace := r.namespace
+	if namespace == "" {
+		namespace = ClusterScopeDir
+	}
+	return filepath.Join(r.dir, namespace, r.gvr.Resource)
+}
+
+// path returns the file of an object, rejecting names that would leave the
+// directory
+func (r *renderResource) path(name string) (string, error) {
+	for _, part := range []string{r.namespace, name} {
+		if strings.ContainsAny(part, `/\`) || part == ".." || part == "." {
+			return "", apierrors.NewBadRequest(fmt.Sprintf("invalid name %q", part))
+		}
+	}
+	if name == "" {
+		return "", apierrors.NewBadRequest("name is required")
+	}
+	return filepath.Join(r.resourceDir(), name+".yaml"), nil
+}
+
+func (r *renderResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	file, err := r.path(obj.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); err == nil {
+		return nil, apierrors.NewAlreadyExists(r.gvr.GroupResource(), obj.GetName())
+	}
+	return r.write(file, obj)
+}
+
+func (r *renderResource) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	file, err := r.path(obj.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), obj.GetName())
+	}
+	return r.write(file, obj)
+}
+
+func (r *renderResource) Delete(ctx context.Context, name string, options metav1.DeleteOptions, subresources ...string) error {
+	file, err := r.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return apierrors.NewNotFound(r.gvr.GroupResource(), name)
+		}
+		return err
+	}
+	// Remove the resource and namespace directories once empty, so a
+	// rendered repository does not accumulate empty directories
+	resourceDir := r.resourceDir()
+	if os.Remove(resourceDir) == nil {
+		_ = os.Remove(filepath.Dir(resourceDir))
+	}
+	return nil
+}
+
+func (r *renderResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	file, err := r.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return r.read(file, name)
+}
+
+func (r *renderResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	entries, err := os.ReadDir(r.resourceDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "List"}}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".yaml"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		obj, err := r.read(filepath.Join(r.resourceDir(), name+".yaml"), name)
+		if err != nil {
+			return nil, err
+		}
+		if selector.Matches(labels.Set(obj.GetLabels())) {
+			list.Items = append(list.Items, *obj)
+		}
+	}
+	return list, nil
+}
+
+// write stores obj in file as YAML, without the fields the apiserver manages
+func (r *renderResource) write(file string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if r.gvr.Group == "" && r.gvr.Resource == "secrets" {
+		return nil, apierrors.NewForbidden(r.gvr.GroupResource(), obj.GetName(),
+			errors.New("Secrets are not rendered, since their values would be written in plain text"))
+	}
+	if obj.GetNamespace() != r.namespace {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("namespace %q of %s does not match %q", obj.GetNamespace(), obj.GetName(), r.namespace))
+	}
+
+	// A JSON round trip copies the object, including map[string]string
+	// values, which a deep copy panics on
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+	}
+	stored := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &stored.Object); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", obj.GetName(), err)
+	}
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields"} {
+		unstructured.RemoveNestedField(stored.Object, "metadata", field)
+	}
+
+	manifest, err := yaml.Marshal(stored.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s as YAML: %w", obj.GetName(), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return nil, err
+	}
+	// Write to a temporary file first, so a failed write never leaves a
+	// truncated manifest for GitOps to pick up
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(manifest); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// read loads the object stored in file
+func (r *renderResource) read(file, name string) (*unstructured.Unstructured, error) {
+	manifest, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+		}
+		return nil, err
+	}
+	data, err := yaml.YAMLToJSON(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return obj, nil
+}
+
+func (r *renderResource) UpdateStatus(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	return nil, r.unsupported("update status")
+}
+
+func (r *renderResource) DeleteCollection(ctx context.Context, options metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return r.unsupported("deletecollection")
+}
+
+func (r *renderResource) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, r.unsupported("watch")
+}
+
+func (r *renderResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return nil, r.unsupported("patch")
+}
+
+func (r *renderResource) Apply(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return nil, r.unsupported("apply")
+}
+
+func (r *renderResource) ApplyStatus(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+	return nil, r.unsupported("apply status")
+}
+
+func (r *renderResource) unsupported(verb string) error {
+	return apierrors.NewMethodNotSupported(r.gvr.GroupResource(), verb)
+}
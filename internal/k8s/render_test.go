@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var renderTaskGVR = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "tasks"}
+
+func renderTask(name string, labels map[string]string, image string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1beta1",
+		"kind":       "Task",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"namespace":       "tekton-pipelines",
+			"resourceVersion": "42",
+		},
+		"spec": map[string]interface{}{
+			"steps": []interface{}{
+				map[string]interface{}{"name": "run", "image": image},
+			},
+		},
+	}}
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestRenderClient_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	tasks := NewRenderClient(dir).Resource(renderTaskGVR).Namespace("tekton-pipelines")
+	file := filepath.Join(dir, "tekton-pipelines", "tasks", "task-1.yaml")
+
+	if _, err := tasks.Create(ctx, renderTask("task-1", nil, "alpine:3.19"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	manifest, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", file, err)
+	}
+	if !strings.Contains(string(manifest), "image: alpine:3.19") {
+		t.Errorf("expected the rendered spec, got:\n%s", manifest)
+	}
+	if strings.Contains(string(manifest), "resourceVersion") {
+		t.Errorf("expected resourceVersion to be stripped, got:\n%s", manifest)
+	}
+
+	if _, err := tasks.Create(ctx, renderTask("task-1", nil, "alpine:3.19"), metav1.CreateOptions{}); !apierrors.IsAlreadyExists(err) {
+		t.Errorf("expected AlreadyExists on a second Create, got %v", err)
+	}
+
+	if _, err := tasks.Update(ctx, renderTask("task-1", nil, "alpine:3.20"), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := tasks.Get(ctx, "task-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	steps, _, _ := unstructured.NestedSlice(got.Object, "spec", "steps")
+	if image := steps[0].(map[string]interface{})["image"]; image != "alpine:3.20" {
+		t.Errorf("expected the updated image, got %v", image)
+	}
+
+	if err := tasks.Delete(ctx, "task-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tekton-pipelines")); !os.IsNotExist(err) {
+		t.Errorf("expected empty directories to be removed, got %v", err)
+	}
+	if _, err := tasks.Get(ctx, "task-1", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected NotFound after Delete, got %v", err)
+	}
+	if err := tasks.Delete(ctx, "task-1", metav1.DeleteOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected NotFound deleting a missing object, got %v", err)
+	}
+	if _, err := tasks.Update(ctx, renderTask("task-1", nil, "alpine:3.20"), metav1.UpdateOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected NotFound updating a missing object, got %v", err)
+	}
+}
+
+func TestRenderClient_ListFiltersByLabelSelector(t *testing.T) {
+	ctx := context.Background()
+	tasks := NewRenderClient(t.TempDir()).Resource(renderTaskGVR).Namespace("tekton-pipelines")
+
+	for name, labels := range map[string]map[string]string{
+		"a": {"team": "payments"},
+		"b": {"team": "search"},
+		"c": {"team": "payments"},
+	} {
+		if _, err := tasks.Create(ctx, renderTask(name, labels, "alpine"), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+	}
+
+	list, err := tasks.List(ctx, metav1.ListOptions{LabelSelector: "team=payments"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	if strings.Join(names, ",") != "a,c" {
+		t.Errorf("expected a,c, got %v", names)
+	}
+
+	empty, err := NewRenderClient(t.TempDir()).Resource(renderTaskGVR).Namespace("other").List(ctx, metav1.ListOptions{})
+	if err != nil || len(empty.Items) != 0 {
+		t.Errorf("expected an empty list for a missing directory, got %v, %v", empty, err)
+	}
+}
+
+func TestRenderClient_Rejects(t *testing.T) {
+	ctx := context.Background()
+	client := NewRenderClient(t.TempDir())
+
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "env", "namespace": "tekton-pipelines"},
+		"stringData": map[string]interface{}{"TOKEN": "s3cr3t"},
+	}}
+	secrets := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}).Namespace("tekton-pipelines")
+	if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); !apierrors.IsForbidden(err) {
+		t.Errorf("expected Secrets to be refused, got %v", err)
+	}
+
+	tasks := client.Resource(renderTaskGVR).Namespace("tekton-pipelines")
+	if _, err := tasks.Create(ctx, renderTask("../escape", nil, "alpine"), metav1.CreateOptions{}); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected names with path separators to be rejected, got %v", err)
+	}
+	if _, err := tasks.Patch(ctx, "task-1", "application/merge-patch+json", []byte("{}"), metav1.PatchOptions{}); !apierrors.IsMethodNotSupported(err) {
+		t.Errorf("expected Patch to be unsupported, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+// Severity decides whether a failing rule blocks the plan or only warns
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Variables available to every expression
+const (
+	VarTask        = "task"
+	VarStepAction  = "step_action"
+	VarLabels      = "labels"
+	VarAnnotations = "annotations"
+	VarActionType  = "action_type"
+)
+
+// costLimit bounds the work a single expression may do, so a rule iterating
+// over large lists cannot stall a plan
+const costLimit = 1000000
+
+// Rule is a CEL expression that must evaluate to true for an action to pass
+type Rule struct {
+	Name       string
+	Expression string
+	Message    string
+	Severity   Severity
+}
+
+// Input is what a rule is evaluated against: the rendered Task and StepAction
+// and the metadata they carry
+type Input struct {
+	Task        map[string]interface{}
+	StepAction  map[string]interface{}
+	Labels      map[string]string
+	Annotations map[string]string
+	// ActionType is "kubernetes" or "aws"
+	ActionType string
+}
+
+// Violation is a rule that evaluated to false, or that could not be evaluated
+type Violation struct {
+	Rule Rule
+	// Err is set when evaluating the rule failed, e.g. a missing field was
+	// accessed. Such rules count as violated.
+	Err error
+}
+
+// Message returns the rule's message, or a default naming the rule
+func (v Violation) Message() string {
+	msg := v.Rule.Message
+	if msg == "" {
+		msg = fmt.Sprintf("Policy %q is not satisfied: %s", v.Rule.Name, v.Rule.Expression)
+	}
+	if v.Err != nil {
+		msg = fmt.Sprintf("%s (evaluation failed: %s)", msg, v.Err)
+	}
+	return msg
+}
+
+type compiledRule struct {
+	rule    Rule
+	program cel.Program
+}
+
+// Evaluator holds compiled rules and is safe for concurrent use
+type Evaluator struct {
+	rules []compiledRule
+}
+
+// newEnv returns the CEL environment shared by all rules
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(VarTask, cel.DynType),
+		cel.Variable(VarStepAction, cel.DynType),
+		cel.Variable(VarLabels, cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable(VarAnnotations, cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable(VarActionType, cel.StringType),
+	)
+}
+
+// CheckExpression compiles expr and checks it returns a bool. Used to reject
+// invalid rules during terraform validate.
+func CheckExpression(expr string) error {
+	env, err := newEnv()
+	if err != nil {
+		return err
+	}
+	_, err = compile(env, expr)
+	return err
+}
+
+func compile(env *cel.Env, expr string) (*cel.Ast, error) {
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must return a bool, got %s", t)
+	}
+	return ast, nil
+}
+
+// Compile compiles rules into an Evaluator. The error names every rule that
+// does not compile.
+func Compile(rules []Rule) (*Evaluator, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	e := &Evaluator{}
+	for _, rule := range rules {
+		ast, err := compile(env, rule.Expression)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("policy %q: %s", rule.Name, err))
+			continue
+		}
+		program, err := env.Program(ast, cel.CostLimit(costLimit))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("policy %q: %s", rule.Name, err))
+			continue
+		}
+		e.rules = append(e.rules, compiledRule{rule: rule, program: program})
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return e, nil
+}
+
+// Evaluate runs every rule against in and returns the violated ones, in rule order
+func (e *Evaluator) Evaluate(in Input) ([]Violation, error) {
+	task, err := normalize(in.Task)
+	if err != nil {
+		return nil, fmt.Errorf("converting Task: %w", err)
+	}
+	stepAction, err := normalize(in.StepAction)
+	if err != nil {
+		return nil, fmt.Errorf("converting StepAction: %w", err)
+	}
+	vars := map[string]interface{}{
+		VarTask:        task,
+		VarStepAction:  stepAction,
+		VarLabels:      nonNil(in.Labels),
+		VarAnnotations: nonNil(in.Annotations),
+		VarActionType:  in.ActionType,
+	}
+
+	var violations []Violation
+	for _, r := range e.rules {
+		out, _, err := r.program.Eval(vars)
+		if err != nil {
+			violations = append(violations, Violation{Rule: r.rule, Err: err})
+			continue
+		}
+		passed, ok := out.Value().(bool)
+		if !ok {
+			violations = append(violations, Violation{Rule: r.rule, Err: fmt.Errorf("expression returned %v, not a bool", out.Value())})
+			continue
+		}
+		if !passed {
+			violations = append(violations, Violation{Rule: r.rule})
+		}
+	}
+	return violations, nil
+}
+
+// normalize converts a rendered object into plain JSON types, so nested typed
+// maps and slices (e.g. map[string]string resources) are visible to CEL
+func normalize(obj map[string]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if obj == nil {
+		return result, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func nonNil(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
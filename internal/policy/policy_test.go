@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func testInput(image string, labels map[string]string) Input {
+	return Input{
+		Task: map[string]interface{}{
+			"kind": "Task",
+			"spec": map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{
+						"name":  "deploy",
+						"image": image,
+						"computeResources": map[string]interface{}{
+							"limits": map[string]string{"memory": "512Mi"},
+						},
+					},
+				},
+			},
+		},
+		StepAction: map[string]interface{}{"kind": "StepAction"},
+		Labels:     labels,
+		ActionType: "kubernetes",
+	}
+}
+
+func TestCheckExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: `task.spec.steps.all(s, !s.image.endsWith(":latest"))`},
+		{expr: `labels["environment_unique_name"] != "production"`},
+		{expr: `action_type == "aws" || step_action.kind == "StepAction"`},
+		{expr: `task.spec.steps.all(s, `, wantErr: "Syntax error"},
+		{expr: `labels["team"]`, wantErr: "must return a bool"},
+		{expr: `unknown_variable == 1`, wantErr: "undeclared reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			err := CheckExpression(tt.expr)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckExpression() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []Rule{
+		{
+			Name:       "no-latest-in-production",
+			Expression: `labels["environment_unique_name"] != "production" || task.spec.steps.all(s, !s.image.endsWith(":latest"))`,
+			Message:    "production actions must pin image tags",
+			Severity:   SeverityError,
+		},
+		{
+			Name:       "memory-limit",
+			Expression: `task.spec.steps.all(s, has(s.computeResources.limits.memory))`,
+			Severity:   SeverityWarning,
+		},
+		{
+			Name:       "run-as-non-root",
+			Expression: `task.spec.steps.all(s, s.securityContext.runAsNonRoot)`,
+			Severity:   SeverityWarning,
+		},
+	}
+	evaluator, err := Compile(rules)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	violations, err := evaluator.Evaluate(testInput("alpine:latest", map[string]string{"environment_unique_name": "production"}))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", violations)
+	}
+	if violations[0].Rule.Name != "no-latest-in-production" || violations[0].Err != nil {
+		t.Errorf("violations[0] = %+v, want no-latest-in-production", violations[0])
+	}
+	if violations[0].Message() != "production actions must pin image tags" {
+		t.Errorf("Message() = %q", violations[0].Message())
+	}
+	if violations[1].Rule.Name != "run-as-non-root" || violations[1].Err == nil {
+		t.Errorf("violations[1] = %+v, want evaluation error for run-as-non-root", violations[1])
+	}
+
+	violations, err = evaluator.Evaluate(testInput("alpine:latest", map[string]string{"environment_unique_name": "staging"}))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(violations) != 1 || violations[0].Rule.Name != "run-as-non-root" {
+		t.Errorf("expected only run-as-non-root to fail outside production, got %+v", violations)
+	}
+}
+
+func TestCompile_ReportsEveryInvalidRule(t *testing.T) {
+	_, err := Compile([]Rule{
+		{Name: "first", Expression: "task.("},
+		{Name: "valid", Expression: "true"},
+		{Name: "second", Expression: "1 + 1"},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, name := range []string{`"first"`, `"second"`} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention policy %s", err, name)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// tektonPipelinesNamespace is the namespace used when neither the resource nor
+// the provider's default_namespace sets one
+const tektonPipelinesNamespace = "tekton-pipelines"
+
+// defaultNamespace returns the provider's default_namespace, falling back to
+// tektonPipelinesNamespace. Safe to call on a nil provider model.
+func (m *FacetsProviderModel) defaultNamespace() string {
+	if m != nil && m.DefaultNamespace.ValueString() != "" {
+		return m.DefaultNamespace.ValueString()
+	}
+	return tektonPipelinesNamespace
+}
+
+// defaultLabels returns the provider's default_labels. Safe to call on a nil provider model.
+func (m *FacetsProviderModel) defaultLabels() types.Map {
+	if m == nil {
+		return types.MapNull(types.StringType)
+	}
+	return m.DefaultLabels
+}
+
+// defaultAnnotations returns the provider's default_annotations. Safe to call on a nil provider model.
+func (m *FacetsProviderModel) defaultAnnotations() types.Map {
+	if m == nil {
+		return types.MapNull(types.StringType)
+	}
+	return m.DefaultAnnotations
+}
+
+// actionMetadataInput holds the configuration that determines the labels and
+// annotations of an action's Task and StepAction
+type actionMetadataInput struct {
+	Name               types.String
+	FacetsResourceName types.String
+	FacetsEnvironment  types.Object
+	FacetsResource     types.Object
+	Labels             types.Map
+	Annotations        types.Map
+	ClusterID          types.String
+	// KubeconfigDelivery is the kubeconfig_delivery of a Kubernetes action,
+	// null for AWS actions
+	KubeconfigDelivery types.String
+	// RBAC is the action's rbac block
+	RBAC types.Object
+	// TaskName is the action's task_name, unknown or null until it is created
+	TaskName      types.String
+	IsCloudAction bool
+}
+
+// buildActionMetadata merges provider defaults, resource labels/annotations
+// and auto-generated metadata. Precedence, highest first:
+// 1. Auto-generated labels and facets.cloud/* annotations
+// 2. Resource labels and annotations
+// 3. Provider default_labels and default_annotations
+//
+// known is false when any input is still unknown (e.g. during plan), in which
+// case the returned metadata is nil.
+func buildActionMetadata(ctx context.Context, in actionMetadataInput, providerData *FacetsProviderModel) (metadata *tekton.ResourceMetadata, known bool, diags diag.Diagnostics) {
+	if in.Name.IsUnknown() || in.FacetsResourceName.IsUnknown() || in.ClusterID.IsUnknown() ||
+		in.FacetsEnvironment.IsUnknown() || in.FacetsResource.IsUnknown() || in.KubeconfigDelivery.IsUnknown() || in.RBAC.IsUnknown() {
+		return nil, false, diags
+	}
+
+	var facetsEnv tekton.FacetsEnvironmentModel
+	diags.Append(in.FacetsEnvironment.As(ctx, &facetsEnv, basetypes.ObjectAsOptions{})...)
+	var facetsRes tekton.FacetsResourceModel
+	diags.Append(in.FacetsResource.As(ctx, &facetsRes, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, false, diags
+	}
+	if facetsEnv.UniqueName.IsUnknown() || facetsRes.Kind.IsUnknown() {
+		return nil, false, diags
+	}
+
+	customLabels, labelsKnown := mergeStringMaps(providerData.defaultLabels(), in.Labels)
+	customAnnotations, annotationsKnown := mergeStringMaps(providerData.defaultAnnotations(), in.Annotations)
+	if !labelsKnown || !annotationsKnown {
+		return nil, false, diags
+	}
+
+	metadata = tekton.NewResourceMetadata(
+		in.Name.ValueString(),
+		in.FacetsResourceName.ValueString(),
+		facetsRes.Kind.ValueString(),
+		facetsEnv.UniqueName.ValueString(),
+		in.ClusterID.ValueString(),
+		in.IsCloudAction,
+		customLabels,
+		customAnnotations,
+	)
+	metadata.RunnerAnnotations = map[string]string{}
+	if in.KubeconfigDelivery.ValueString() == tekton.KubeconfigDeliverySecretWorkspace {
+		metadata.RunnerAnnotations[tekton.AnnotationKubeconfigDelivery] = tekton.KubeconfigDeliverySecretWorkspace
+	}
+	if !in.RBAC.IsNull() {
+		// The ServiceAccount is named after the Task, which keeps its name
+		// when an in-place update changes the inputs it was generated from
+		taskName := in.TaskName.ValueString()
+		if taskName == "" {
+			taskName = tekton.GenerateNames(in.FacetsResourceName.ValueString(), facetsEnv.UniqueName.ValueString(), in.Name.ValueString()).TaskName
+		}
+		metadata.RunnerAnnotations[tekton.AnnotationServiceAccount] = tekton.ServiceAccountName(taskName)
+	}
+	return metadata, true, diags
+}
+
+// mergeStringMaps returns defaults overlaid with overrides. known is false when
+// either map, or any of its values, is unknown.
+func mergeStringMaps(defaults, overrides types.Map) (merged map[string]string, known bool) {
+	merged = make(map[string]string)
+	for _, m := range []types.Map{defaults, overrides} {
+		if m.IsNull() {
+			continue
+		}
+		if m.IsUnknown() {
+			return nil, false
+		}
+		for k, v := range m.Elements() {
+			if v.IsUnknown() {
+				return nil, false
+			}
+			if v.IsNull() {
+				continue
+			}
+			if s, ok := v.(types.String); ok {
+				merged[k] = s.ValueString()
+			}
+		}
+	}
+	return merged, true
+}
+
+// stringMapValue converts a Go map into a Terraform map of strings
+func stringMapValue(ctx context.Context, m map[string]string) (types.Map, diag.Diagnostics) {
+	return types.MapValueFrom(ctx, types.StringType, m)
+}
+
+// effectiveMetadataFromObject returns the labels and annotations actually set
+// on a cluster object, so refresh surfaces out-of-band changes as drift
+func effectiveMetadataFromObject(ctx context.Context, obj *unstructured.Unstructured) (labels, annotations types.Map, diags diag.Diagnostics) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objAnnotations := obj.GetAnnotations()
+	if objAnnotations == nil {
+		objAnnotations = map[string]string{}
+	}
+
+	labels, d := stringMapValue(ctx, objLabels)
+	diags.Append(d...)
+	annotations, d = stringMapValue(ctx, objAnnotations)
+	diags.Append(d...)
+	return labels, annotations, diags
+}
+
+// modifyActionPlan fills in the plan values that depend on the provider
+// configuration rather than the resource's own attributes, so that a change to
+// any of them is visible in the plan:
+//   - cluster_id, resolved by resolveClusterID
+//   - namespace, from default_namespace when the resource does not set one.
+//     A change replaces the resource, like changing namespace itself.
+//   - effective_labels and effective_annotations, the merged metadata applied
+//     to the Task and StepAction
+//
+// It also enforces the provider's step policy (max_step_resources and
+// allowed_image_registries), including for the credential step image, so
+// violations fail the plan.
+func modifyActionPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, providerData *FacetsProviderModel, getClient func() (dynamic.Interface, error), isCloudAction bool) {
+	// Nothing to resolve when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	clusterID, err := resolveClusterID(ctx, providerData, getClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Cluster ID",
+			err.Error(),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("cluster_id"), clusterID)...)
+
+	var configNamespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &configNamespace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if configNamespace.IsNull() && (providerData == nil || !providerData.DefaultNamespace.IsUnknown()) {
+		namespace := providerData.defaultNamespace()
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("namespace"), types.StringValue(namespace))...)
+
+		if !req.State.Raw.IsNull() {
+			var stateNamespace types.String
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("namespace"), &stateNamespace)...)
+			if !stateNamespace.IsNull() && stateNamespace.ValueString() != namespace {
+				resp.RequiresReplace = append(resp.RequiresReplace, path.Root("namespace"))
+			}
+		}
+	}
+
+	if policy, known := providerData.stepPolicy(ctx); known {
+		var steps types.List
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("steps"), &steps)...)
+		resp.Diagnostics.Append(tekton.ValidateStepPolicy(ctx, steps, policy)...)
+
+		var override types.Object
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("credential_step"), &override)...)
+		credentialStep, known, diags := providerData.credentialStep(ctx, override)
+		resp.Diagnostics.Append(diags...)
+		if known {
+			resp.Diagnostics.Append(validateCredentialStepImage(credentialStep, policy)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Render mode writes every object to output_dir, where the env Secret's
+	// values would end up in plain text
+	if providerData.renderMode() {
+		var steps types.List
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("steps"), &steps)...)
+		if hasSensitiveEnv(ctx, steps) {
+			resp.Diagnostics.AddAttributeError(path.Root("steps"), "Sensitive Env Not Supported in Render Mode",
+				"sensitive_env is stored in a Secret, which output_mode \"render\" would write to output_dir in plain text. "+
+					"Deliver the Secret separately, e.g. with a sealed or external secret, and reference it from a step instead.")
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	in := actionMetadataInput{ClusterID: clusterID, IsCloudAction: isCloudAction}
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("name"), &in.Name)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("facets_resource_name"), &in.FacetsResourceName)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("facets_environment"), &in.FacetsEnvironment)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("facets_resource"), &in.FacetsResource)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("labels"), &in.Labels)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("annotations"), &in.Annotations)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("rbac"), &in.RBAC)...)
+	if !req.State.Raw.IsNull() {
+		// task_name is computed without a plan modifier, so it is only known in state
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("task_name"), &in.TaskName)...)
+	}
+	if !isCloudAction {
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("kubeconfig_delivery"), &in.KubeconfigDelivery)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	metadata, known, diags := buildActionMetadata(ctx, in, providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	effectiveLabels := types.MapUnknown(types.StringType)
+	effectiveAnnotations := types.MapUnknown(types.StringType)
+	if known {
+		effectiveLabels, diags = stringMapValue(ctx, metadata.Labels())
+		resp.Diagnostics.Append(diags...)
+		effectiveAnnotations, diags = stringMapValue(ctx, metadata.Annotations())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_labels"), effectiveLabels)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_annotations"), effectiveAnnotations)...)
+}
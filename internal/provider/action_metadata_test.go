@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testStringMap(m map[string]string) types.Map {
+	elements := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+func testMetadataInput(labels, annotations types.Map) actionMetadataInput {
+	return actionMetadataInput{
+		Name:               types.StringValue("my-action"),
+		FacetsResourceName: types.StringValue("my-app"),
+		FacetsEnvironment: types.ObjectValueMust(
+			map[string]attr.Type{"unique_name": types.StringType},
+			map[string]attr.Value{"unique_name": types.StringValue("production")},
+		),
+		FacetsResource: types.ObjectValueMust(
+			map[string]attr.Type{"kind": types.StringType},
+			map[string]attr.Value{"kind": types.StringValue("service")},
+		),
+		Labels:      labels,
+		Annotations: annotations,
+		ClusterID:   types.StringValue("prod-eu"),
+	}
+}
+
+func TestBuildActionMetadata_Precedence(t *testing.T) {
+	providerData := &FacetsProviderModel{
+		DefaultLabels: testStringMap(map[string]string{
+			"team":                   "platform",
+			"cost-center":            "1234",
+			tekton.LabelResourceName: "from-provider",
+		}),
+		DefaultAnnotations: testStringMap(map[string]string{
+			"owner":                      "platform@example.com",
+			tekton.AnnotationDisplayName: "from-provider",
+		}),
+	}
+	in := testMetadataInput(
+		testStringMap(map[string]string{"team": "payments", tekton.LabelClusterID: "from-resource"}),
+		testStringMap(map[string]string{"owner": "payments@example.com"}),
+	)
+
+	metadata, known, diags := buildActionMetadata(context.Background(), in, providerData)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !known {
+		t.Fatal("expected metadata to be known")
+	}
+
+	labels := metadata.Labels()
+	for k, want := range map[string]string{
+		"team":                   "payments",
+		"cost-center":            "1234",
+		tekton.LabelResourceName: "my-app",
+		tekton.LabelClusterID:    "prod-eu",
+	} {
+		if labels[k] != want {
+			t.Errorf("label %s = %q, want %q", k, labels[k], want)
+		}
+	}
+
+	annotations := metadata.Annotations()
+	for k, want := range map[string]string{
+		"owner":                      "payments@example.com",
+		tekton.AnnotationDisplayName: "my-action",
+	} {
+		if annotations[k] != want {
+			t.Errorf("annotation %s = %q, want %q", k, annotations[k], want)
+		}
+	}
+}
+
+func TestBuildActionMetadata_NilProviderData(t *testing.T) {
+	in := testMetadataInput(testStringMap(map[string]string{"team": "payments"}), types.MapNull(types.StringType))
+
+	metadata, known, diags := buildActionMetadata(context.Background(), in, nil)
+	if diags.HasError() || !known {
+		t.Fatalf("buildActionMetadata() known = %v, diags = %v", known, diags)
+	}
+	if got := metadata.Labels()["team"]; got != "payments" {
+		t.Errorf("label team = %q, want payments", got)
+	}
+}
+
+func TestBuildActionMetadata_KubeconfigDelivery(t *testing.T) {
+	in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+	in.KubeconfigDelivery = types.StringValue(tekton.KubeconfigDeliverySecretWorkspace)
+
+	metadata, known, diags := buildActionMetadata(context.Background(), in, nil)
+	if diags.HasError() || !known {
+		t.Fatalf("buildActionMetadata() known = %v, diags = %v", known, diags)
+	}
+	if got := metadata.Annotations()[tekton.AnnotationKubeconfigDelivery]; got != tekton.KubeconfigDeliverySecretWorkspace {
+		t.Errorf("annotation %s = %q, want %q", tekton.AnnotationKubeconfigDelivery, got, tekton.KubeconfigDeliverySecretWorkspace)
+	}
+
+	in.KubeconfigDelivery = types.StringValue(tekton.KubeconfigDeliveryParam)
+	metadata, _, _ = buildActionMetadata(context.Background(), in, nil)
+	if _, found := metadata.Annotations()[tekton.AnnotationKubeconfigDelivery]; found {
+		t.Errorf("annotation %s should not be set for the param delivery", tekton.AnnotationKubeconfigDelivery)
+	}
+}
+
+func TestBuildActionMetadata_Unknown(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           actionMetadataInput
+		providerData *FacetsProviderModel
+	}{
+		{
+			name: "unknown resource labels",
+			in:   testMetadataInput(types.MapUnknown(types.StringType), types.MapNull(types.StringType)),
+		},
+		{
+			name: "unknown label value",
+			in: testMetadataInput(
+				types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringUnknown()}),
+				types.MapNull(types.StringType),
+			),
+		},
+		{
+			name: "unknown cluster ID",
+			in: func() actionMetadataInput {
+				in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+				in.ClusterID = types.StringUnknown()
+				return in
+			}(),
+		},
+		{
+			name: "unknown kubeconfig_delivery",
+			in: func() actionMetadataInput {
+				in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+				in.KubeconfigDelivery = types.StringUnknown()
+				return in
+			}(),
+		},
+		{
+			name:         "unknown provider default_annotations",
+			in:           testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType)),
+			providerData: &FacetsProviderModel{DefaultAnnotations: types.MapUnknown(types.StringType)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, known, diags := buildActionMetadata(context.Background(), tt.in, tt.providerData)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if known || metadata != nil {
+				t.Errorf("buildActionMetadata() = %v, %v; want nil, false", metadata, known)
+			}
+		})
+	}
+}
+
+func TestProviderModelDefaultNamespace(t *testing.T) {
+	var nilModel *FacetsProviderModel
+	if got := nilModel.defaultNamespace(); got != tektonPipelinesNamespace {
+		t.Errorf("defaultNamespace() on nil model = %q, want %q", got, tektonPipelinesNamespace)
+	}
+	if got := (&FacetsProviderModel{}).defaultNamespace(); got != tektonPipelinesNamespace {
+		t.Errorf("defaultNamespace() without default_namespace = %q, want %q", got, tektonPipelinesNamespace)
+	}
+	m := &FacetsProviderModel{DefaultNamespace: types.StringValue("ci")}
+	if got := m.defaultNamespace(); got != "ci" {
+		t.Errorf("defaultNamespace() = %q, want ci", got)
+	}
+}
+
+func TestEffectiveMetadataFromObject(t *testing.T) {
+	task := testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, map[string]string{"team": "payments"})
+
+	labels, annotations, diags := effectiveMetadataFromObject(context.Background(), task)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !labels.Equal(testStringMap(map[string]string{"team": "payments"})) {
+		t.Errorf("labels = %s, want team=payments", labels)
+	}
+	if annotations.IsNull() || len(annotations.Elements()) != 0 {
+		t.Errorf("annotations = %s, want empty map", annotations)
+	}
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// Values of the provider's output_mode
+const (
+	outputModeApply  = "apply"
+	outputModeRender = "render"
+)
+
+// errRenderMode is returned by kubernetesDiscoveryFactory in render mode
+var errRenderMode = errors.New(`output_mode is "render": the provider writes manifests to output_dir and does not connect to a cluster`)
+
+// renderMode reports whether output_mode is "render". Safe to call on a nil
+// provider model.
+func (m *FacetsProviderModel) renderMode() bool {
+	return m != nil && m.OutputMode.ValueString() == outputModeRender
+}
+
+// kubernetesClientFactory returns a clientFactory that connects using the
+// provider's kubernetes block, reusing clients from the provider's pool. The
+// block is resolved on every call rather than in Configure, so values that are
+// unknown during plan (e.g. a host taken from a cluster created in the same
+// run) and terraform validate never fail early. Resolving it only inspects
+// configuration; kubeconfig is read once per connection config by the pool.
+// In render mode the client writes to output_dir instead.
+func kubernetesClientFactory(providerData *FacetsProviderData) func() (dynamic.Interface, error) {
+	return func() (dynamic.Interface, error) {
+		if providerData == nil || providerData.Model == nil {
+			return k8s.GetKubernetesClient()
+		}
+		if providerData.Model.renderMode() {
+			if providerData.Model.OutputDir.ValueString() == "" {
+				return nil, errors.New(`output_dir is required when output_mode is "render"`)
+			}
+			return k8s.NewRenderClient(providerData.Model.OutputDir.ValueString()), nil
+		}
+		cfg, err := k8s.GetConnectionConfig(context.Background(), &k8s.ProviderModel{
+			Kubernetes: providerData.Model.Kubernetes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if providerData.Clients == nil {
+			return k8s.NewDynamicClient(cfg)
+		}
+		return providerData.Clients.DynamicClient(cfg)
+	}
+}
+
+// kubernetesDiscoveryFactory is like kubernetesClientFactory, for the
+// discovery client that shares the pool entry's HTTP client and cache. There
+// is no API to discover in render mode, so it returns errRenderMode.
+func kubernetesDiscoveryFactory(providerData *FacetsProviderData) func() (discovery.DiscoveryInterface, error) {
+	return func() (discovery.DiscoveryInterface, error) {
+		if providerData == nil || providerData.Model == nil {
+			return k8s.GetDiscoveryClient()
+		}
+		if providerData.Model.renderMode() {
+			return nil, errRenderMode
+		}
+		cfg, err := k8s.GetConnectionConfig(context.Background(), &k8s.ProviderModel{
+			Kubernetes: providerData.Model.Kubernetes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if providerData.Clients == nil {
+			return k8s.NewDiscoveryClient(cfg)
+		}
+		return providerData.Clients.DiscoveryClient(cfg)
+	}
+}
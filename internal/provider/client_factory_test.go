@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func renderProviderData(dir string) *FacetsProviderData {
+	return &FacetsProviderData{Model: &FacetsProviderModel{
+		OutputMode: types.StringValue(outputModeRender),
+		OutputDir:  types.StringValue(dir),
+	}}
+}
+
+// TestRenderMode_ActionLifecycleWritesFiles runs createResources and
+// deleteResources against the client render mode provides, and checks they
+// write and remove one file per object instead of calling an apiserver
+func TestRenderMode_ActionLifecycleWritesFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	providerData := renderProviderData(dir)
+	r := &TektonActionKubernetesResource{
+		providerData:     providerData.Model,
+		clientFactory:    kubernetesClientFactory(providerData),
+		discoveryFactory: kubernetesDiscoveryFactory(providerData),
+	}
+
+	_, operations, err := r.getClient()
+	if err != nil {
+		t.Fatalf("getClient: %v", err)
+	}
+	stepAction := testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
+
+	if diags := r.createResources(ctx, operations, stepAction, task, nil, nil); diags.HasError() {
+		t.Fatalf("createResources: %v", diags)
+	}
+	taskFile := filepath.Join(dir, k8sReadTestNamespace, testfake.TaskGVR.Resource, k8sTaskName+".yaml")
+	stepActionFile := filepath.Join(dir, k8sReadTestNamespace, testfake.StepActionGVR.Resource, k8sStepActionName+".yaml")
+	for _, file := range []string{taskFile, stepActionFile} {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("expected %s to be written: %v", file, err)
+		}
+	}
+
+	if diags := r.deleteResources(ctx, operations, k8sReadTestNamespace, k8sTaskName, k8sStepActionName, false, []tekton.RBACObject{}); diags.HasError() {
+		t.Fatalf("deleteResources: %v", diags)
+	}
+	for _, file := range []string{taskFile, stepActionFile} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", file, err)
+		}
+	}
+
+	if _, err := r.discoveryFactory(); !errors.Is(err, errRenderMode) {
+		t.Errorf("expected discovery to fail in render mode, got %v", err)
+	}
+}
+
+func TestRenderMode_RequiresOutputDir(t *testing.T) {
+	if _, err := kubernetesClientFactory(renderProviderData(""))(); err == nil {
+		t.Error("expected an error without output_dir")
+	}
+}
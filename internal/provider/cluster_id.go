@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// clusterIDEnvVar is the legacy source of the cluster_id label, kept as a
+// fallback for configurations that do not set cluster_id on the provider
+const clusterIDEnvVar = "CLUSTER_ID"
+
+// resolveClusterID determines the cluster ID recorded on Tasks and StepActions.
+// Priority order:
+// 1. Provider cluster_id attribute
+// 2. kube-system namespace UID, when derive_cluster_id = true
+// 3. CLUSTER_ID environment variable
+// 4. tekton.DefaultClusterID
+//
+// Returns an unknown value when the provider's cluster_id is not known yet
+// (e.g. it references a resource created in the same run), or when it has to
+// be derived from a cluster whose connection settings are not known yet.
+// getClient is only called when the ID has to be derived from the cluster.
+func resolveClusterID(ctx context.Context, providerData *FacetsProviderModel, getClient func() (dynamic.Interface, error)) (types.String, error) {
+	if providerData != nil {
+		if providerData.ClusterID.IsUnknown() || providerData.DeriveClusterID.IsUnknown() {
+			return types.StringUnknown(), nil
+		}
+		if id := providerData.ClusterID.ValueString(); id != "" {
+			return types.StringValue(id), nil
+		}
+		if providerData.DeriveClusterID.ValueBool() {
+			if !valuesKnown(ctx, providerData.Kubernetes) {
+				return types.StringUnknown(), nil
+			}
+			client, err := getClient()
+			if err != nil {
+				return types.StringNull(), err
+			}
+			uid, err := k8s.KubeSystemUID(ctx, client)
+			if err != nil {
+				return types.StringNull(), fmt.Errorf("derive_cluster_id is enabled: %w", err)
+			}
+			return types.StringValue(uid), nil
+		}
+	}
+
+	if id := os.Getenv(clusterIDEnvVar); id != "" {
+		return types.StringValue(id), nil
+	}
+	return types.StringValue(tekton.DefaultClusterID), nil
+}
+
+// plannedClusterID returns the cluster ID to apply. The value resolved during
+// plan is used as-is; it is only resolved again when it was unknown at plan time.
+func plannedClusterID(ctx context.Context, planned types.String, providerData *FacetsProviderModel, getClient func() (dynamic.Interface, error)) (string, error) {
+	if !planned.IsUnknown() && !planned.IsNull() {
+		return planned.ValueString(), nil
+	}
+	clusterID, err := resolveClusterID(ctx, providerData, getClient)
+	if err != nil {
+		return "", err
+	}
+	if clusterID.IsUnknown() {
+		return "", fmt.Errorf("provider cluster_id is still unknown during apply")
+	}
+	return clusterID.ValueString(), nil
+}
+
+// clusterIDFromTask reads the cluster ID recorded on an existing Task. Used to
+// backfill state written before the cluster_id attribute existed, so that
+// upgrading does not plan a spurious change. Returns null when the Task
+// carries no cluster ID.
+func clusterIDFromTask(task *unstructured.Unstructured) types.String {
+	metadata, err := tekton.MetadataFromObject(task)
+	if err != nil || metadata.ClusterID == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(metadata.ClusterID)
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const testKubeSystemUID = "0b6c2f2e-7d4a-4e59-9a55-1f1f3c2b9d10"
+
+// kubeSystemClient returns a getClient func backed by a fake client holding
+// the kube-system namespace, and a pointer counting how often it was called.
+func kubeSystemClient() (func() (dynamic.Interface, error), *int) {
+	calls := 0
+	c := testfake.NewClient(testfake.Namespace("kube-system", testKubeSystemUID))
+	return func() (dynamic.Interface, error) {
+		calls++
+		return c, nil
+	}, &calls
+}
+
+func TestResolveClusterID(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerData *FacetsProviderModel
+		env          string
+		want         types.String
+		wantCalls    int
+	}{
+		{
+			name:         "provider attribute wins over env",
+			providerData: &FacetsProviderModel{ClusterID: types.StringValue("prod-eu")},
+			env:          "from-env",
+			want:         types.StringValue("prod-eu"),
+		},
+		{
+			name:         "derived from kube-system UID",
+			providerData: &FacetsProviderModel{DeriveClusterID: types.BoolValue(true)},
+			env:          "from-env",
+			want:         types.StringValue(testKubeSystemUID),
+			wantCalls:    1,
+		},
+		{
+			name:         "env fallback",
+			providerData: &FacetsProviderModel{},
+			env:          "from-env",
+			want:         types.StringValue("from-env"),
+		},
+		{
+			name: "default without provider data",
+			want: types.StringValue(tekton.DefaultClusterID),
+		},
+		{
+			name:         "unknown provider attribute",
+			providerData: &FacetsProviderModel{ClusterID: types.StringUnknown()},
+			env:          "from-env",
+			want:         types.StringUnknown(),
+		},
+		{
+			name: "derived from a cluster not known yet",
+			providerData: &FacetsProviderModel{
+				DeriveClusterID: types.BoolValue(true),
+				Kubernetes:      types.ObjectUnknown(map[string]attr.Type{"host": types.StringType}),
+			},
+			env:  "from-env",
+			want: types.StringUnknown(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(clusterIDEnvVar, tt.env)
+			getClient, calls := kubeSystemClient()
+
+			got, err := resolveClusterID(context.Background(), tt.providerData, getClient)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("resolveClusterID() = %s, want %s", got, tt.want)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("getClient called %d times, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResolveClusterID_DeriveErrors(t *testing.T) {
+	providerData := &FacetsProviderModel{DeriveClusterID: types.BoolValue(true)}
+
+	_, err := resolveClusterID(context.Background(), providerData, func() (dynamic.Interface, error) {
+		return nil, errors.New("no kubeconfig")
+	})
+	if err == nil {
+		t.Error("expected error when the client cannot be created")
+	}
+
+	_, err = resolveClusterID(context.Background(), providerData, func() (dynamic.Interface, error) {
+		return testfake.NewClient(), nil
+	})
+	if err == nil {
+		t.Error("expected error when kube-system cannot be read")
+	}
+}
+
+func TestPlannedClusterID(t *testing.T) {
+	t.Setenv(clusterIDEnvVar, "from-env")
+
+	got, err := plannedClusterID(context.Background(), types.StringValue("planned"), nil, nil)
+	if err != nil || got != "planned" {
+		t.Errorf("plannedClusterID() = %q, %v; want planned value", got, err)
+	}
+
+	got, err = plannedClusterID(context.Background(), types.StringUnknown(), nil, nil)
+	if err != nil || got != "from-env" {
+		t.Errorf("plannedClusterID() = %q, %v; want value resolved at apply", got, err)
+	}
+}
+
+func TestClusterIDFromTask(t *testing.T) {
+	metadata := tekton.NewResourceMetadata("my-action", "my-app", "service", "production", "prod/eu", false, nil, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, metadata.Labels())
+	task.SetAnnotations(metadata.Annotations())
+
+	got := clusterIDFromTask(task)
+	if got.ValueString() != "prod/eu" {
+		t.Errorf("clusterIDFromTask() = %s, want exact value from annotation", got)
+	}
+
+	got = clusterIDFromTask(testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, nil))
+	if !got.IsNull() {
+		t.Errorf("clusterIDFromTask() = %s, want null for a Task without identity", got)
+	}
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ConfigValidator = actionNamesValidator{}
+
+// actionNamesValidator rejects steps, params and env vars that collide with the
+// names the provider injects into the rendered Task, duplicate names, and step
+// names that are not valid DNS labels. Runs during `terraform validate`, so it
+// must never touch the cluster.
+type actionNamesValidator struct {
+	reserved tekton.ReservedNames
+}
+
+func (v actionNamesValidator) Description(ctx context.Context) string {
+	return "step, param and env var names must be unique and must not collide with names injected by the provider"
+}
+
+func (v actionNamesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v actionNamesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var steps, params types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("steps"), &steps)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("params"), &params)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(tekton.ValidateActionConfig(ctx, steps, params, v.reserved)...)
+}
+
+var _ resource.ConfigValidator = computeResourcesValidator{}
+
+// computeResourcesValidator checks step requests/limits with
+// resource.ParseQuantity so malformed quantities fail at plan time instead of
+// being rejected by the apiserver during apply.
+type computeResourcesValidator struct{}
+
+func (v computeResourcesValidator) Description(ctx context.Context) string {
+	return "step compute resources must use supported resource names and valid quantities, with requests not exceeding limits"
+}
+
+func (v computeResourcesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v computeResourcesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var steps types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("steps"), &steps)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(tekton.ValidateComputeResources(ctx, steps)...)
+}
+
+var _ provider.ConfigValidator = stepResourcesPolicyValidator{}
+
+// stepResourcesPolicyValidator applies the step resources checks to the
+// provider's default_step_resources and max_step_resources, and rejects
+// defaults that exceed the maximums, since every step relying on them would
+// then fail to plan.
+type stepResourcesPolicyValidator struct{}
+
+func (v stepResourcesPolicyValidator) Description(ctx context.Context) string {
+	return "default_step_resources and max_step_resources must use valid quantities, and defaults must not exceed the maximums"
+}
+
+func (v stepResourcesPolicyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stepResourcesPolicyValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var defaults, maximums types.Object
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default_step_resources"), &defaults)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("max_step_resources"), &maximums)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(tekton.ValidateComputeResourcesObject(ctx, defaults, path.Root("default_step_resources"))...)
+	resp.Diagnostics.Append(tekton.ValidateComputeResourcesObject(ctx, maximums, path.Root("max_step_resources"))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defaultList, known := resourceListFromObject(ctx, defaults)
+	maxList, maxKnown := resourceListFromObject(ctx, maximums)
+	if !known || !maxKnown {
+		return
+	}
+	for _, msg := range tekton.ExceededMaximums(defaultList, maxList) {
+		resp.Diagnostics.AddAttributeError(path.Root("default_step_resources"), "Default Step Resources Exceed Maximum",
+			"default_step_resources "+msg+".")
+	}
+}
+
+var _ provider.ConfigValidator = outputModeValidator{}
+
+// outputModeValidator requires output_dir in render mode and rejects
+// derive_cluster_id there, since deriving it reads the cluster render mode
+// never connects to.
+type outputModeValidator struct{}
+
+func (v outputModeValidator) Description(ctx context.Context) string {
+	return "output_mode \"render\" requires output_dir and conflicts with derive_cluster_id"
+}
+
+func (v outputModeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v outputModeValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var outputMode, outputDir types.String
+	var deriveClusterID types.Bool
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("output_mode"), &outputMode)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("output_dir"), &outputDir)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("derive_cluster_id"), &deriveClusterID)...)
+	if resp.Diagnostics.HasError() || outputMode.ValueString() != outputModeRender {
+		return
+	}
+
+	if outputDir.IsNull() || (!outputDir.IsUnknown() && outputDir.ValueString() == "") {
+		resp.Diagnostics.AddAttributeError(path.Root("output_dir"), "Missing Output Directory",
+			"output_dir is required when output_mode is \"render\".")
+	}
+	if deriveClusterID.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("derive_cluster_id"), "Cluster ID Cannot Be Derived",
+			"derive_cluster_id reads the cluster, which output_mode \"render\" never connects to. Set cluster_id instead.")
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// CredentialStepModel is a resource's credential_step block
+type CredentialStepModel struct {
+	Image           types.String `tfsdk:"image"`
+	ImagePullPolicy types.String `tfsdk:"image_pull_policy"`
+}
+
+// ProviderCredentialStepModel is the provider's credential_step block
+type ProviderCredentialStepModel struct {
+	Image           types.String `tfsdk:"image"`
+	ImagePullPolicy types.String `tfsdk:"image_pull_policy"`
+	RegistryMirror  types.String `tfsdk:"registry_mirror"`
+}
+
+// credentialStepAttrTypes are the attribute types of a resource's credential_step block
+var credentialStepAttrTypes = map[string]attr.Type{
+	"image":             types.StringType,
+	"image_pull_policy": types.StringType,
+}
+
+var (
+	imageReferencePattern = regexp.MustCompile(`^[a-z0-9][A-Za-z0-9._/:@-]*$`)
+	registryMirrorPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:/-]*[a-z0-9]$`)
+)
+
+// credentialStepImageValidators reject image references that are not plain,
+// static references, so the registry allow-list and policies can check them
+func credentialStepImageValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.RegexMatches(imageReferencePattern,
+			"must be a container image reference such as harbor.example.com/facets/actions-base-image:v1.0.0, without whitespace or $(...) substitutions"),
+	}
+}
+
+// imagePullPolicyValidators accept the Kubernetes image pull policies
+func imagePullPolicyValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.OneOf("Always", "IfNotPresent", "Never"),
+	}
+}
+
+// registryMirrorValidators accept a registry host with an optional path prefix
+func registryMirrorValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.RegexMatches(registryMirrorPattern,
+			"must be a registry host with an optional path, such as harbor.example.com/dockerhub, without a scheme or tag"),
+	}
+}
+
+// credentialStepResourceAttribute is the credential_step block shared by the action resources
+func credentialStepResourceAttribute(tools []string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Overrides the provider's credential_step for this action's setup-credentials step. " +
+			"An override image must provide " + strings.Join(tools, ", ") + " on its PATH; the step " +
+			"checks for them first and fails with an error naming the missing tool.",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"image": schema.StringAttribute{
+				Description: "Image of the credential StepAction. Used as is; the provider's registry_mirror is not applied.",
+				Optional:    true,
+				Validators:  credentialStepImageValidators(),
+			},
+			"image_pull_policy": schema.StringAttribute{
+				Description: "Pull policy of the setup-credentials step: Always, IfNotPresent or Never",
+				Optional:    true,
+				Validators:  imagePullPolicyValidators(),
+			},
+		},
+	}
+}
+
+// credentialStep resolves the credential step image and pull policy. Each
+// setting comes from the resource's credential_step (override), then the
+// provider's credential_step, then the default image pulled through the
+// provider's registry_mirror. known is false when any of them is still
+// unknown. Safe to call on a nil provider model.
+func (m *FacetsProviderModel) credentialStep(ctx context.Context, override types.Object) (step tekton.CredentialStep, known bool, diags diag.Diagnostics) {
+	if !valuesKnown(ctx, override) {
+		return step, false, diags
+	}
+	if m != nil && !m.CredentialStep.IsNull() {
+		if !valuesKnown(ctx, m.CredentialStep) {
+			return step, false, diags
+		}
+		var providerStep ProviderCredentialStepModel
+		diags.Append(m.CredentialStep.As(ctx, &providerStep, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return step, false, diags
+		}
+		step.Image = providerStep.Image.ValueString()
+		if step.Image == "" && providerStep.RegistryMirror.ValueString() != "" {
+			step.Image = tekton.MirrorImage(tekton.DefaultCredentialStepImage, providerStep.RegistryMirror.ValueString())
+		}
+		step.ImagePullPolicy = providerStep.ImagePullPolicy.ValueString()
+	}
+
+	if !override.IsNull() {
+		var resourceStep CredentialStepModel
+		diags.Append(override.As(ctx, &resourceStep, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return step, false, diags
+		}
+		if !resourceStep.Image.IsNull() {
+			step.Image = resourceStep.Image.ValueString()
+		}
+		if !resourceStep.ImagePullPolicy.IsNull() {
+			step.ImagePullPolicy = resourceStep.ImagePullPolicy.ValueString()
+		}
+	}
+	return step, true, diags
+}
+
+// validateCredentialStepImage checks the credential step image against the
+// provider's allowed_image_registries, like the images of the action's steps
+func validateCredentialStepImage(step tekton.CredentialStep, policy tekton.StepPolicy) diag.Diagnostics {
+	var diags diag.Diagnostics
+	image := step.ImageOrDefault()
+	if len(policy.AllowedImageRegistries) > 0 && !tekton.ImageAllowed(image, policy.AllowedImageRegistries) {
+		diags.AddAttributeError(path.Root("credential_step").AtName("image"), "Image Registry Not Allowed",
+			fmt.Sprintf("Credential step image %q is not from a registry allowed by the provider's allowed_image_registries (%s). "+
+				"Set credential_step.image or the provider's credential_step.registry_mirror to an allowed registry.",
+				image, strings.Join(policy.AllowedImageRegistries, ", ")))
+	}
+	return diags
+}
+
+// applyCredentialStep returns the credential step used at apply time, after
+// checking it against the step policy. It is normally resolved and checked
+// during plan already; it is checked again here in case it was unknown then.
+func applyCredentialStep(ctx context.Context, override types.Object, providerData *FacetsProviderModel, policy tekton.StepPolicy) (tekton.CredentialStep, diag.Diagnostics) {
+	step, known, diags := providerData.credentialStep(ctx, override)
+	if diags.HasError() {
+		return step, diags
+	}
+	if !known {
+		diags.AddError(
+			"Unable to Resolve Credential Step",
+			"The credential_step image or pull policy is still unknown during apply.",
+		)
+		return step, diags
+	}
+	diags.Append(validateCredentialStepImage(step, policy)...)
+	return step, diags
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testProviderCredentialStep(image, pullPolicy, mirror types.String) types.Object {
+	return types.ObjectValueMust(map[string]attr.Type{
+		"image":             types.StringType,
+		"image_pull_policy": types.StringType,
+		"registry_mirror":   types.StringType,
+	}, map[string]attr.Value{
+		"image":             image,
+		"image_pull_policy": pullPolicy,
+		"registry_mirror":   mirror,
+	})
+}
+
+func testCredentialStepOverride(image, pullPolicy types.String) types.Object {
+	return types.ObjectValueMust(credentialStepAttrTypes, map[string]attr.Value{
+		"image":             image,
+		"image_pull_policy": pullPolicy,
+	})
+}
+
+func TestProviderModelCredentialStep(t *testing.T) {
+	mirrored := &FacetsProviderModel{CredentialStep: testProviderCredentialStep(
+		types.StringNull(), types.StringValue("IfNotPresent"), types.StringValue("harbor.internal/dockerhub"),
+	)}
+	noOverride := types.ObjectNull(credentialStepAttrTypes)
+
+	tests := map[string]struct {
+		providerData *FacetsProviderModel
+		override     types.Object
+		want         tekton.CredentialStep
+	}{
+		"defaults": {
+			providerData: nil,
+			override:     noOverride,
+			want:         tekton.CredentialStep{},
+		},
+		"provider mirror": {
+			providerData: mirrored,
+			override:     noOverride,
+			want:         tekton.CredentialStep{Image: "harbor.internal/dockerhub/facetscloud/actions-base-image:v1.0.0", ImagePullPolicy: "IfNotPresent"},
+		},
+		"provider image wins over mirror": {
+			providerData: &FacetsProviderModel{CredentialStep: testProviderCredentialStep(
+				types.StringValue("harbor.internal/facets/tools:v2"), types.StringNull(), types.StringValue("harbor.internal/dockerhub"),
+			)},
+			override: noOverride,
+			want:     tekton.CredentialStep{Image: "harbor.internal/facets/tools:v2"},
+		},
+		"resource overrides provider": {
+			providerData: mirrored,
+			override:     testCredentialStepOverride(types.StringValue("harbor.internal/team/tools:v3"), types.StringNull()),
+			want:         tekton.CredentialStep{Image: "harbor.internal/team/tools:v3", ImagePullPolicy: "IfNotPresent"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, known, diags := tt.providerData.credentialStep(context.Background(), tt.override)
+			if diags.HasError() || !known {
+				t.Fatalf("credentialStep() known = %v, diags = %v", known, diags)
+			}
+			if got != tt.want {
+				t.Errorf("credentialStep() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	unknown := &FacetsProviderModel{CredentialStep: testProviderCredentialStep(
+		types.StringUnknown(), types.StringNull(), types.StringNull(),
+	)}
+	if _, known, _ := unknown.credentialStep(context.Background(), noOverride); known {
+		t.Error("expected unknown provider image to be unknown")
+	}
+}
+
+func TestValidateCredentialStepImage(t *testing.T) {
+	policy := tekton.StepPolicy{AllowedImageRegistries: []string{"harbor.internal"}}
+
+	if diags := validateCredentialStepImage(tekton.CredentialStep{}, policy); !diags.HasError() {
+		t.Error("expected the default Docker Hub image to be rejected")
+	}
+	step := tekton.CredentialStep{Image: tekton.MirrorImage(tekton.DefaultCredentialStepImage, "harbor.internal/dockerhub")}
+	if diags := validateCredentialStepImage(step, policy); diags.HasError() {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
@@ -0,0 +1,472 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ datasource.DataSource                     = &TektonActionDataSource{}
+	_ datasource.DataSourceWithConfigure        = &TektonActionDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &TektonActionDataSource{}
+)
+
+// Action types reported by the action data sources
+const (
+	actionTypeNameKubernetes = "kubernetes"
+	actionTypeNameAWS        = "aws"
+)
+
+// taskGVR and stepActionGVR are the GVRs of the Tasks and StepActions the
+// action resources create
+var (
+	taskGVR       = k8sschema.GroupVersionResource{Group: tekton.Group, Version: "v1beta1", Resource: "tasks"}
+	stepActionGVR = k8sschema.GroupVersionResource{Group: tekton.Group, Version: "v1beta1", Resource: "stepactions"}
+)
+
+// NewTektonActionDataSource creates a new action data source
+func NewTektonActionDataSource() datasource.DataSource {
+	return &TektonActionDataSource{
+		clientFactory: k8s.GetKubernetesClient,
+	}
+}
+
+// TektonActionDataSource looks up an action managed elsewhere, by its Facets
+// identity or by its Task name
+type TektonActionDataSource struct {
+	providerData *FacetsProviderModel
+	// clientFactory produces a Kubernetes dynamic client, like the resources'
+	// field of the same name. Tests in the same package may override it.
+	clientFactory func() (dynamic.Interface, error)
+}
+
+// TektonActionDataSourceModel represents the data source data model
+type TektonActionDataSourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Namespace             types.String `tfsdk:"namespace"`
+	TaskName              types.String `tfsdk:"task_name"`
+	Name                  types.String `tfsdk:"name"`
+	FacetsResourceName    types.String `tfsdk:"facets_resource_name"`
+	EnvironmentUniqueName types.String `tfsdk:"environment_unique_name"`
+	FacetsResourceKind    types.String `tfsdk:"facets_resource_kind"`
+	ActionType            types.String `tfsdk:"action_type"`
+	StepActionName        types.String `tfsdk:"step_action_name"`
+	ClusterID             types.String `tfsdk:"cluster_id"`
+	Description           types.String `tfsdk:"description"`
+	Labels                types.Map    `tfsdk:"labels"`
+	Annotations           types.Map    `tfsdk:"annotations"`
+	Steps                 types.List   `tfsdk:"steps"`
+	Params                types.List   `tfsdk:"params"`
+}
+
+// actionStepObjectType is the object type of a step of the action data sources.
+// Unlike the resources' steps, sensitive_env lists only names, since the
+// values live in the env Secret.
+var actionStepObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"name":                types.StringType,
+	"image":               types.StringType,
+	"script":              types.StringType,
+	"resources":           tekton.ComputeResourcesObjectType,
+	"env":                 types.ListType{ElemType: tekton.EnvVarObjectType},
+	"sensitive_env_names": types.ListType{ElemType: types.StringType},
+}}
+
+func (d *TektonActionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tekton_action"
+}
+
+func (d *TektonActionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up an action created by facets_tekton_action_kubernetes or facets_tekton_action_aws, " +
+			"e.g. to reference its Task from a pipeline or a schedule managed elsewhere. " +
+			"Find it either by task_name, or by its Facets identity: name, facets_resource_name and environment_unique_name.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier in the format namespace/task_name",
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace of the action. Defaults to the provider's default_namespace, or \"tekton-pipelines\".",
+				Optional:    true,
+				Computed:    true,
+			},
+			"task_name": schema.StringAttribute{
+				Description: "Name of the action's Task. Conflicts with name.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Display name of the action. Requires facets_resource_name and environment_unique_name.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"facets_resource_name": schema.StringAttribute{
+				Description: "Resource name of the action in the Facets blueprint",
+				Optional:    true,
+				Computed:    true,
+			},
+			"environment_unique_name": schema.StringAttribute{
+				Description: "Unique name of the action's Facets environment",
+				Optional:    true,
+				Computed:    true,
+			},
+			"facets_resource_kind": schema.StringAttribute{
+				Description: "Kind of the action's Facets resource",
+				Computed:    true,
+			},
+			"action_type": schema.StringAttribute{
+				Description: "\"kubernetes\" for facets_tekton_action_kubernetes, or \"aws\" for facets_tekton_action_aws",
+				Computed:    true,
+			},
+			"step_action_name": schema.StringAttribute{
+				Description: "Name of the action's credential StepAction",
+				Computed:    true,
+			},
+			"cluster_id": schema.StringAttribute{
+				Description: "Cluster identifier recorded on the Task",
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the Task, or null when it has none",
+				Computed:    true,
+			},
+			"labels": schema.MapAttribute{
+				Description: "All labels of the Task",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"annotations": schema.MapAttribute{
+				Description: "All annotations of the Task",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"steps": schema.ListNestedAttribute{
+				Description: "User-defined steps of the Task, without the setup-credentials step and the env vars the provider injects",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Step name",
+							Computed:    true,
+						},
+						"image": schema.StringAttribute{
+							Description: "Container image of the step",
+							Computed:    true,
+						},
+						"script": schema.StringAttribute{
+							Description: "Script of the step",
+							Computed:    true,
+						},
+						"resources": schema.SingleNestedAttribute{
+							Description: "Compute resources of the step, including provider defaults. Null when it has none.",
+							Computed:    true,
+							Attributes: map[string]schema.Attribute{
+								"requests": schema.MapAttribute{
+									Description: "Requested compute resources",
+									Computed:    true,
+									ElementType: types.StringType,
+								},
+								"limits": schema.MapAttribute{
+									Description: "Compute resource limits",
+									Computed:    true,
+									ElementType: types.StringType,
+								},
+							},
+						},
+						"env": schema.ListNestedAttribute{
+							Description: "Plain environment variables of the step",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Description: "Environment variable name",
+										Computed:    true,
+									},
+									"value": schema.StringAttribute{
+										Description: "Environment variable value",
+										Computed:    true,
+									},
+								},
+							},
+						},
+						"sensitive_env_names": schema.ListAttribute{
+							Description: "Names of the step's sensitive_env variables. Their values are not read.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+			"params": schema.ListNestedAttribute{
+				Description: "User-defined params of the Task, without the params the provider injects",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Parameter name",
+							Computed:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "Parameter type",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ConfigValidators requires either task_name or the full Facets identity
+func (d *TektonActionDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("task_name"),
+			path.MatchRoot("name"),
+		),
+		datasourcevalidator.RequiredTogether(
+			path.MatchRoot("name"),
+			path.MatchRoot("facets_resource_name"),
+			path.MatchRoot("environment_unique_name"),
+		),
+		datasourcevalidator.Conflicting(
+			path.MatchRoot("task_name"),
+			path.MatchRoot("facets_resource_name"),
+		),
+		datasourcevalidator.Conflicting(
+			path.MatchRoot("task_name"),
+			path.MatchRoot("environment_unique_name"),
+		),
+	}
+}
+
+func (d *TektonActionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Clients are created lazily in Read, so terraform validate needs no kubeconfig
+	if req.ProviderData != nil {
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Provider Data Type",
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
+			)
+			return
+		}
+		d.providerData = providerData.Model
+		d.clientFactory = kubernetesClientFactory(providerData)
+	}
+}
+
+func (d *TektonActionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TektonActionDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	factory := d.clientFactory
+	if factory == nil {
+		factory = k8s.GetKubernetesClient
+	}
+	client, err := factory()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			fmt.Sprintf("failed to create Kubernetes client: %s", err.Error()),
+		)
+		return
+	}
+
+	namespace := state.Namespace.ValueString()
+	if namespace == "" {
+		namespace = d.providerData.defaultNamespace()
+	}
+	taskName := state.TaskName.ValueString()
+	if taskName == "" {
+		taskName = tekton.GenerateNames(
+			state.FacetsResourceName.ValueString(),
+			state.EnvironmentUniqueName.ValueString(),
+			state.Name.ValueString(),
+		).TaskName
+	}
+
+	task, err := client.Resource(taskGVR).Namespace(namespace).Get(ctx, taskName, metav1.GetOptions{})
+	if err != nil {
+		detail := fmt.Sprintf("Could not read Task %s/%s: %s", namespace, taskName, err.Error())
+		if apierrors.IsNotFound(err) {
+			detail = fmt.Sprintf("No action with Task %s/%s exists. Check the namespace", namespace, taskName)
+			if state.TaskName.IsNull() {
+				detail += ", and that name, facets_resource_name and environment_unique_name match the action's exactly"
+			}
+			detail += "."
+		}
+		resp.Diagnostics.AddError("Action not found", detail)
+		return
+	}
+
+	model, diags := actionDataSourceModel(ctx, task)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// actionDataSourceModel describes the action a Task belongs to
+func actionDataSourceModel(ctx context.Context, task *unstructured.Unstructured) (TektonActionDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var model TektonActionDataSourceModel
+
+	metadata, err := tekton.MetadataFromObject(task)
+	if err != nil {
+		diags.AddError(
+			"Not a Facets action",
+			fmt.Sprintf("Task %s/%s was not created by facets_tekton_action_kubernetes or facets_tekton_action_aws: %s",
+				task.GetNamespace(), task.GetName(), err.Error()),
+		)
+		return model, diags
+	}
+
+	actionType, reserved := actionTypeNameKubernetes, tekton.KubernetesReservedNames()
+	if metadata.IsCloudAction {
+		actionType, reserved = actionTypeNameAWS, tekton.AWSReservedNames()
+	}
+	parsed := tekton.ParseTask(task, reserved)
+
+	model.ID = types.StringValue(task.GetNamespace() + "/" + task.GetName())
+	model.Namespace = types.StringValue(task.GetNamespace())
+	model.TaskName = types.StringValue(task.GetName())
+	model.Name = types.StringValue(metadata.DisplayName)
+	model.FacetsResourceName = types.StringValue(metadata.ResourceName)
+	model.EnvironmentUniqueName = types.StringValue(metadata.EnvUniqueName)
+	model.FacetsResourceKind = types.StringValue(metadata.ResourceKind)
+	model.ActionType = types.StringValue(actionType)
+	model.StepActionName = types.StringValue(credentialStepActionName(task))
+	model.ClusterID = types.StringValue(metadata.ClusterID)
+	model.Description = types.StringNull()
+	if parsed.Description != "" {
+		model.Description = types.StringValue(parsed.Description)
+	}
+
+	var d diag.Diagnostics
+	model.Labels, d = stringMapValue(ctx, task.GetLabels())
+	diags.Append(d...)
+	model.Annotations, d = stringMapValue(ctx, task.GetAnnotations())
+	diags.Append(d...)
+	model.Steps, d = parsedStepsValue(parsed.Steps)
+	diags.Append(d...)
+	model.Params, d = parsedParamsValue(parsed.Params)
+	diags.Append(d...)
+	return model, diags
+}
+
+// credentialStepActionName returns the StepAction the Task's setup-credentials
+// step references, or the conventional name when it has none
+func credentialStepActionName(task *unstructured.Unstructured) string {
+	steps, _, _ := unstructured.NestedFieldNoCopy(task.Object, "spec", "steps")
+	list, _ := steps.([]interface{})
+	for _, s := range list {
+		step, _ := s.(map[string]interface{})
+		if step["name"] != tekton.SetupCredentialsStepName {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(step, "ref", "name"); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("setup-credentials-%s", task.GetName())
+}
+
+// parsedStepsValue converts parsed steps to the data sources' steps list
+func parsedStepsValue(steps []tekton.ParsedStep) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	elems := make([]attr.Value, 0, len(steps))
+	for _, step := range steps {
+		resources := types.ObjectNull(tekton.ComputeResourcesObjectType.AttrTypes)
+		if len(step.Requests) > 0 || len(step.Limits) > 0 {
+			var d diag.Diagnostics
+			resources, d = types.ObjectValue(tekton.ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+				"requests": optionalStringMap(step.Requests),
+				"limits":   optionalStringMap(step.Limits),
+			})
+			diags.Append(d...)
+		}
+
+		envElems := make([]attr.Value, 0, len(step.Env))
+		for _, env := range step.Env {
+			envElems = append(envElems, types.ObjectValueMust(tekton.EnvVarObjectType.AttrTypes, map[string]attr.Value{
+				"name":  types.StringValue(env.Name),
+				"value": types.StringValue(env.Value),
+			}))
+		}
+		env := types.ListNull(tekton.EnvVarObjectType)
+		if len(envElems) > 0 {
+			env = types.ListValueMust(tekton.EnvVarObjectType, envElems)
+		}
+
+		sensitiveNames := make([]string, 0, len(step.SensitiveEnv))
+		for name := range step.SensitiveEnv {
+			sensitiveNames = append(sensitiveNames, name)
+		}
+		sort.Strings(sensitiveNames)
+		sensitiveElems := make([]attr.Value, len(sensitiveNames))
+		for i, name := range sensitiveNames {
+			sensitiveElems[i] = types.StringValue(name)
+		}
+
+		obj, d := types.ObjectValue(actionStepObjectType.AttrTypes, map[string]attr.Value{
+			"name":                types.StringValue(step.Name),
+			"image":               types.StringValue(step.Image),
+			"script":              types.StringValue(step.Script),
+			"resources":           resources,
+			"env":                 env,
+			"sensitive_env_names": types.ListValueMust(types.StringType, sensitiveElems),
+		})
+		diags.Append(d...)
+		elems = append(elems, obj)
+	}
+	list, d := types.ListValue(actionStepObjectType, elems)
+	diags.Append(d...)
+	return list, diags
+}
+
+// parsedParamsValue converts parsed params to a params list
+func parsedParamsValue(params []tekton.ParsedParam) (types.List, diag.Diagnostics) {
+	elems := make([]attr.Value, 0, len(params))
+	for _, param := range params {
+		elems = append(elems, types.ObjectValueMust(tekton.ParamObjectType.AttrTypes, map[string]attr.Value{
+			"name": types.StringValue(param.Name),
+			"type": types.StringValue(param.Type),
+		}))
+	}
+	return types.ListValue(tekton.ParamObjectType, elems)
+}
+
+// optionalStringMap returns a map value, or null for an empty map
+func optionalStringMap(m map[string]string) types.Map {
+	if len(m) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	elems := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		elems[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ datasource.DataSource              = &TektonActionRunsDataSource{}
+	_ datasource.DataSourceWithConfigure = &TektonActionRunsDataSource{}
+)
+
+// defaultActionRunsLimit is the number of runs returned when limit is unset
+const defaultActionRunsLimit = 10
+
+// NewTektonActionRunsDataSource creates a new action runs data source
+func NewTektonActionRunsDataSource() datasource.DataSource {
+	return &TektonActionRunsDataSource{
+		clientFactory: k8s.GetKubernetesClient,
+	}
+}
+
+// TektonActionRunsDataSource lists the TaskRuns of an action, e.g. to gate a
+// deployment on the outcome of its last run
+type TektonActionRunsDataSource struct {
+	providerData *FacetsProviderModel
+	// clientFactory produces a Kubernetes dynamic client, like the resources'
+	// field of the same name. Tests in the same package may override it.
+	clientFactory func() (dynamic.Interface, error)
+}
+
+// TektonActionRunsDataSourceModel represents the data source data model
+type TektonActionRunsDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Namespace types.String `tfsdk:"namespace"`
+	TaskName  types.String `tfsdk:"task_name"`
+	Limit     types.Int64  `tfsdk:"limit"`
+	Runs      types.List   `tfsdk:"runs"`
+}
+
+// actionRunObjectType is the object type of an element of runs
+var actionRunObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"name":            types.StringType,
+	"start_time":      types.StringType,
+	"completion_time": types.StringType,
+	"status":          types.StringType,
+	"succeeded":       types.BoolType,
+	"reason":          types.StringType,
+	"message":         types.StringType,
+	"results":         types.MapType{ElemType: types.StringType},
+	"user_email":      types.StringType,
+}}
+
+func (d *TektonActionRunsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tekton_action_runs"
+}
+
+func (d *TektonActionRunsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the runs of an action, newest first: the TaskRuns of its Task, with their times, " +
+			"the Succeeded condition, results and the user who ran it. Use it for dashboards, " +
+			"or to gate a deployment on the outcome of the last run.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier in the format namespace/task_name",
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace of the action. Defaults to the provider's default_namespace, or \"tekton-pipelines\".",
+				Optional:    true,
+				Computed:    true,
+			},
+			"task_name": schema.StringAttribute{
+				Description: "Name of the action's Task, e.g. the task_name attribute of an action resource",
+				Required:    true,
+			},
+			"limit": schema.Int64Attribute{
+				Description: fmt.Sprintf("Maximum number of runs to return. Defaults to %d.", defaultActionRunsLimit),
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"runs": schema.ListNestedAttribute{
+				Description: "Runs of the action, newest first",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the TaskRun",
+							Computed:    true,
+						},
+						"start_time": schema.StringAttribute{
+							Description: "RFC 3339 time the run started, or null before it starts",
+							Computed:    true,
+						},
+						"completion_time": schema.StringAttribute{
+							Description: "RFC 3339 time the run completed, or null while it runs",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "Status of the Succeeded condition: \"True\", \"False\", or \"Unknown\" while the run is in progress. " +
+								"Null before Tekton reports it.",
+							Computed: true,
+						},
+						"succeeded": schema.BoolAttribute{
+							Description: "Whether the run succeeded. Null until it completes.",
+							Computed:    true,
+						},
+						"reason": schema.StringAttribute{
+							Description: "Reason of the Succeeded condition, e.g. \"Succeeded\", \"Failed\", \"TaskRunTimeout\" or \"Running\"",
+							Computed:    true,
+						},
+						"message": schema.StringAttribute{
+							Description: "Message of the Succeeded condition",
+							Computed:    true,
+						},
+						"results": schema.MapAttribute{
+							Description: "Results the steps wrote, by name",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"user_email": schema.StringAttribute{
+							Description: "FACETS_USER_EMAIL param of the run: the user who ran the action. Null when it was not set.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TektonActionRunsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Clients are created lazily in Read, so terraform validate needs no kubeconfig
+	if req.ProviderData != nil {
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Provider Data Type",
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
+			)
+			return
+		}
+		d.providerData = providerData.Model
+		d.clientFactory = kubernetesClientFactory(providerData)
+	}
+}
+
+func (d *TektonActionRunsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TektonActionRunsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	factory := d.clientFactory
+	if factory == nil {
+		factory = k8s.GetKubernetesClient
+	}
+	client, err := factory()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			fmt.Sprintf("failed to create Kubernetes client: %s", err.Error()),
+		)
+		return
+	}
+
+	namespace := state.Namespace.ValueString()
+	if namespace == "" {
+		namespace = d.providerData.defaultNamespace()
+	}
+	limit := int64(defaultActionRunsLimit)
+	if !state.Limit.IsNull() {
+		limit = state.Limit.ValueInt64()
+	}
+
+	runs, err := tekton.ListTaskRuns(ctx, client, namespace, state.TaskName.ValueString(), int(limit))
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing action runs", err.Error())
+		return
+	}
+
+	elems := make([]attr.Value, 0, len(runs))
+	for _, run := range runs {
+		elem, diags := actionRunValue(run)
+		resp.Diagnostics.Append(diags...)
+		elems = append(elems, elem)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+	state.Runs, diags = types.ListValue(actionRunObjectType, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.ID = types.StringValue(namespace + "/" + state.TaskName.ValueString())
+	state.Namespace = types.StringValue(namespace)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// actionRunValue converts a run to an element of runs, with null for the
+// values Tekton has not reported yet
+func actionRunValue(run tekton.TaskRun) (attr.Value, diag.Diagnostics) {
+	optional := func(s string) types.String {
+		if s == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(s)
+	}
+
+	succeeded := types.BoolNull()
+	switch run.Succeeded {
+	case "True":
+		succeeded = types.BoolValue(true)
+	case "False":
+		succeeded = types.BoolValue(false)
+	}
+
+	return types.ObjectValue(actionRunObjectType.AttrTypes, map[string]attr.Value{
+		"name":            types.StringValue(run.Name),
+		"start_time":      optional(run.StartTime),
+		"completion_time": optional(run.CompletionTime),
+		"status":          optional(run.Succeeded),
+		"succeeded":       succeeded,
+		"reason":          optional(run.Reason),
+		"message":         optional(run.Message),
+		"results":         optionalStringMap(run.Results),
+		"user_email":      optional(run.UserEmail),
+	})
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestTektonActionRunsDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	succeeded := func(status string) map[string]any {
+		return map[string]any{"conditions": []any{map[string]any{"type": "Succeeded", "status": status, "reason": "r-" + status}}}
+	}
+	client := testfake.NewClient(
+		testfake.TaskRun("tekton-pipelines", "migrate-1", "migrate", "2026-05-01T10:00:00Z", succeeded("True")),
+		testfake.TaskRun("tekton-pipelines", "migrate-2", "migrate", "2026-05-02T10:00:00Z", succeeded("False")),
+		testfake.TaskRun("tekton-pipelines", "migrate-3", "migrate", "2026-05-03T10:00:00Z", succeeded("Unknown")),
+	)
+	d := &TektonActionRunsDataSource{clientFactory: func() (dynamic.Interface, error) { return client, nil }}
+
+	resp := readDataSource(t, d, map[string]tftypes.Value{
+		"task_name": tftypes.NewValue(tftypes.String, "migrate"),
+		"limit":     tftypes.NewValue(tftypes.Number, 2),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() diags = %v", resp.Diagnostics)
+	}
+	var state TektonActionRunsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+
+	if state.ID.ValueString() != "tekton-pipelines/migrate" {
+		t.Errorf("id = %s", state.ID)
+	}
+	runs := state.Runs.Elements()
+	if len(runs) != 2 {
+		t.Fatalf("runs = %s, want the 2 newest", state.Runs)
+	}
+	want := []struct {
+		name      string
+		status    string
+		succeeded types.Bool
+	}{
+		{"migrate-3", "Unknown", types.BoolNull()},
+		{"migrate-2", "False", types.BoolValue(false)},
+	}
+	for i, w := range want {
+		attrs := runs[i].(types.Object).Attributes()
+		if attrs["name"] != types.StringValue(w.name) || attrs["status"] != types.StringValue(w.status) || !attrs["succeeded"].Equal(w.succeeded) {
+			t.Errorf("runs[%d] = %s, want %s with status %s", i, runs[i], w.name, w.status)
+		}
+		if !attrs["start_time"].IsNull() || !attrs["user_email"].IsNull() {
+			t.Errorf("runs[%d] start_time = %s, user_email = %s, want null", i, attrs["start_time"], attrs["user_email"])
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// testActionTask renders the Task of testPlanWithImage with its metadata,
+// as the apiserver returns it
+func testActionTask(t *testing.T) *unstructured.Unstructured {
+	ctx := context.Background()
+	in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+	metadata, _, diags := buildActionMetadata(ctx, in, nil)
+	if diags.HasError() {
+		t.Fatalf("buildActionMetadata() diags = %v", diags)
+	}
+	names, _ := plannedNames(ctx, in)
+
+	plan := testPlanWithImage("bitnami/kubectl:1.30")
+	plan.Description = types.StringValue("Restart the API")
+	plan.TaskName = types.StringValue(names.TaskName)
+	plan.StepActionName = types.StringValue(names.StepActionName)
+	_, task, _ := (&TektonActionKubernetesResource{}).renderObjects(ctx, plan, tekton.StepPolicy{}, tekton.CredentialStep{}, metadata)
+
+	data, err := json.Marshal(task.Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromServer := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &fromServer.Object); err != nil {
+		t.Fatal(err)
+	}
+	return fromServer
+}
+
+// readDataSource runs Read with the given config attributes, the others null
+func readDataSource(t *testing.T, d datasource.DataSource, config map[string]tftypes.Value) *datasource.ReadResponse {
+	ctx := context.Background()
+	schemaResp := &datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+	rawType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	fields := map[string]tftypes.Value{}
+	for name, ft := range rawType.AttributeTypes {
+		fields[name] = tftypes.NewValue(ft, nil)
+		if v, ok := config[name]; ok {
+			fields[name] = v
+		}
+	}
+	raw := tftypes.NewValue(rawType, fields)
+
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: raw}}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw}}, resp)
+	return resp
+}
+
+func TestTektonActionDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	task := testActionTask(t)
+	client := testfake.NewClient(task)
+	d := &TektonActionDataSource{clientFactory: func() (dynamic.Interface, error) { return client, nil }}
+
+	lookups := map[string]map[string]tftypes.Value{
+		"identity": {
+			"name":                    tftypes.NewValue(tftypes.String, "my-action"),
+			"facets_resource_name":    tftypes.NewValue(tftypes.String, "my-app"),
+			"environment_unique_name": tftypes.NewValue(tftypes.String, "production"),
+		},
+		"task_name": {
+			"task_name": tftypes.NewValue(tftypes.String, task.GetName()),
+		},
+	}
+	for name, config := range lookups {
+		t.Run(name, func(t *testing.T) {
+			resp := readDataSource(t, d, config)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() diags = %v", resp.Diagnostics)
+			}
+			var state TektonActionDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+
+			if state.TaskName.ValueString() != task.GetName() || state.StepActionName.ValueString() != "setup-credentials-"+task.GetName() {
+				t.Errorf("task_name = %s, step_action_name = %s", state.TaskName, state.StepActionName)
+			}
+			if state.Name.ValueString() != "my-action" || state.FacetsResourceKind.ValueString() != "service" || state.ActionType.ValueString() != "kubernetes" {
+				t.Errorf("name = %s, facets_resource_kind = %s, action_type = %s", state.Name, state.FacetsResourceKind, state.ActionType)
+			}
+			if state.Description.ValueString() != "Restart the API" {
+				t.Errorf("description = %s", state.Description)
+			}
+			if len(state.Steps.Elements()) != 1 || len(state.Params.Elements()) != 0 {
+				t.Errorf("steps = %s, params = %s, want the user step and no user params", state.Steps, state.Params)
+			}
+			if state.Labels.Elements()[tekton.LabelResourceName] != types.StringValue("my-app") {
+				t.Errorf("labels = %s", state.Labels)
+			}
+		})
+	}
+}
+
+func TestTektonActionDataSource_NotFound(t *testing.T) {
+	client := testfake.NewClient()
+	d := &TektonActionDataSource{clientFactory: func() (dynamic.Interface, error) { return client, nil }}
+
+	resp := readDataSource(t, d, map[string]tftypes.Value{"task_name": tftypes.NewValue(tftypes.String, "missing")})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Action not found" {
+		t.Errorf("Read() diags = %v, want Action not found", resp.Diagnostics)
+	}
+}
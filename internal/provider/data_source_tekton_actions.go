@@ -0,0 +1,325 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ datasource.DataSource              = &TektonActionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &TektonActionsDataSource{}
+)
+
+// NewTektonActionsDataSource creates a new actions data source
+func NewTektonActionsDataSource() datasource.DataSource {
+	return &TektonActionsDataSource{
+		clientFactory: k8s.GetKubernetesClient,
+	}
+}
+
+// TektonActionsDataSource lists the actions of a namespace by their Facets
+// labels, e.g. for a catalog of an environment's actions
+type TektonActionsDataSource struct {
+	providerData *FacetsProviderModel
+	// clientFactory produces a Kubernetes dynamic client, like the resources'
+	// field of the same name. Tests in the same package may override it.
+	clientFactory func() (dynamic.Interface, error)
+}
+
+// TektonActionsDataSourceModel represents the data source data model
+type TektonActionsDataSourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Namespace             types.String `tfsdk:"namespace"`
+	EnvironmentUniqueName types.String `tfsdk:"environment_unique_name"`
+	ResourceKind          types.String `tfsdk:"resource_kind"`
+	CloudAction           types.Bool   `tfsdk:"cloud_action"`
+	ClusterID             types.String `tfsdk:"cluster_id"`
+	MatchLabels           types.Map    `tfsdk:"match_labels"`
+	Actions               types.List   `tfsdk:"actions"`
+}
+
+// actionSummaryObjectType is the object type of an element of actions
+var actionSummaryObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"task_name":               types.StringType,
+	"step_action_name":        types.StringType,
+	"step_action_exists":      types.BoolType,
+	"name":                    types.StringType,
+	"facets_resource_name":    types.StringType,
+	"facets_resource_kind":    types.StringType,
+	"environment_unique_name": types.StringType,
+	"cluster_id":              types.StringType,
+	"action_type":             types.StringType,
+	"description":             types.StringType,
+	"params":                  types.ListType{ElemType: tekton.ParamObjectType},
+}}
+
+func (d *TektonActionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tekton_actions"
+}
+
+func (d *TektonActionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the actions created by facets_tekton_action_kubernetes and facets_tekton_action_aws in a namespace, " +
+			"e.g. for a catalog or an audit of an environment's actions. Filters select actions by their Facets labels; " +
+			"unset filters match any value.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier in the format namespace/label_selector",
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace to list. Defaults to the provider's default_namespace, or \"tekton-pipelines\".",
+				Optional:    true,
+				Computed:    true,
+			},
+			"environment_unique_name": schema.StringAttribute{
+				Description: "Lists only the actions of this Facets environment",
+				Optional:    true,
+			},
+			"resource_kind": schema.StringAttribute{
+				Description: "Lists only the actions of Facets resources of this kind",
+				Optional:    true,
+			},
+			"cloud_action": schema.BoolAttribute{
+				Description: "Lists only AWS actions when true, or only Kubernetes actions when false",
+				Optional:    true,
+			},
+			"cluster_id": schema.StringAttribute{
+				Description: "Lists only the actions recorded for this cluster",
+				Optional:    true,
+			},
+			"match_labels": schema.MapAttribute{
+				Description: "Further labels the actions must carry, e.g. custom labels given to the action resources",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"actions": schema.ListNestedAttribute{
+				Description: "Matching actions, ordered by task_name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"task_name": schema.StringAttribute{
+							Description: "Name of the action's Task",
+							Computed:    true,
+						},
+						"step_action_name": schema.StringAttribute{
+							Description: "Name of the action's credential StepAction",
+							Computed:    true,
+						},
+						"step_action_exists": schema.BoolAttribute{
+							Description: "Whether the credential StepAction exists. Null when the provider's identity may not list StepActions.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Display name of the action",
+							Computed:    true,
+						},
+						"facets_resource_name": schema.StringAttribute{
+							Description: "Resource name of the action in the Facets blueprint",
+							Computed:    true,
+						},
+						"facets_resource_kind": schema.StringAttribute{
+							Description: "Kind of the action's Facets resource",
+							Computed:    true,
+						},
+						"environment_unique_name": schema.StringAttribute{
+							Description: "Unique name of the action's Facets environment",
+							Computed:    true,
+						},
+						"cluster_id": schema.StringAttribute{
+							Description: "Cluster identifier recorded on the Task",
+							Computed:    true,
+						},
+						"action_type": schema.StringAttribute{
+							Description: "\"kubernetes\" or \"aws\"",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Description of the Task, or null when it has none",
+							Computed:    true,
+						},
+						"params": schema.ListNestedAttribute{
+							Description: "User-defined params of the Task",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Description: "Parameter name",
+										Computed:    true,
+									},
+									"type": schema.StringAttribute{
+										Description: "Parameter type",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TektonActionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Clients are created lazily in Read, so terraform validate needs no kubeconfig
+	if req.ProviderData != nil {
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Provider Data Type",
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
+			)
+			return
+		}
+		d.providerData = providerData.Model
+		d.clientFactory = kubernetesClientFactory(providerData)
+	}
+}
+
+func (d *TektonActionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TektonActionsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	selector := tekton.ActionSelector{
+		EnvUniqueName: state.EnvironmentUniqueName.ValueString(),
+		ResourceKind:  state.ResourceKind.ValueString(),
+		ClusterID:     state.ClusterID.ValueString(),
+		CloudAction:   state.CloudAction.ValueBoolPointer(),
+	}
+	if !state.MatchLabels.IsNull() {
+		resp.Diagnostics.Append(state.MatchLabels.ElementsAs(ctx, &selector.MatchLabels, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	labelSelector, err := selector.LabelSelector()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid action filter", err.Error())
+		return
+	}
+
+	factory := d.clientFactory
+	if factory == nil {
+		factory = k8s.GetKubernetesClient
+	}
+	client, err := factory()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			fmt.Sprintf("failed to create Kubernetes client: %s", err.Error()),
+		)
+		return
+	}
+
+	namespace := state.Namespace.ValueString()
+	if namespace == "" {
+		namespace = d.providerData.defaultNamespace()
+	}
+	tasks, err := tekton.ListAll(ctx, client, taskGVR, namespace, labelSelector.String())
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing actions", err.Error())
+		return
+	}
+
+	// StepActions carry the same labels as their Tasks. nil means they may not
+	// be listed, so whether they exist is unknown.
+	var stepActions map[string]bool
+	items, err := tekton.ListAll(ctx, client, stepActionGVR, namespace, labelSelector.String())
+	switch {
+	case err == nil:
+		stepActions = make(map[string]bool, len(items))
+		for _, item := range items {
+			stepActions[item.GetName()] = true
+		}
+	case !apierrors.IsForbidden(err):
+		resp.Diagnostics.AddError("Error listing actions", err.Error())
+		return
+	}
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].GetName() < tasks[j].GetName() })
+	elems := make([]attr.Value, 0, len(tasks))
+	for i := range tasks {
+		elem, diags := actionSummaryValue(&tasks[i], stepActions)
+		resp.Diagnostics.Append(diags...)
+		if elem != nil {
+			elems = append(elems, elem)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+	state.Actions, diags = types.ListValue(actionSummaryObjectType, elems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.ID = types.StringValue(namespace + "/" + labelSelector.String())
+	state.Namespace = types.StringValue(namespace)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// actionSummaryValue describes the action a listed Task belongs to, or returns
+// nil for a Task not created by the action resources. stepActions holds the
+// names of the existing StepActions, or is nil when they are unknown.
+func actionSummaryValue(task *unstructured.Unstructured, stepActions map[string]bool) (attr.Value, diag.Diagnostics) {
+	metadata, err := tekton.MetadataFromObject(task)
+	if err != nil {
+		return nil, nil
+	}
+
+	actionType, reserved := actionTypeNameKubernetes, tekton.KubernetesReservedNames()
+	if metadata.IsCloudAction {
+		actionType, reserved = actionTypeNameAWS, tekton.AWSReservedNames()
+	}
+	parsed := tekton.ParseTask(task, reserved)
+
+	stepActionName := credentialStepActionName(task)
+	stepActionExists := types.BoolNull()
+	if stepActions != nil {
+		stepActionExists = types.BoolValue(stepActions[stepActionName])
+	}
+	description := types.StringNull()
+	if parsed.Description != "" {
+		description = types.StringValue(parsed.Description)
+	}
+	params, diags := parsedParamsValue(parsed.Params)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	obj, d := types.ObjectValue(actionSummaryObjectType.AttrTypes, map[string]attr.Value{
+		"task_name":               types.StringValue(task.GetName()),
+		"step_action_name":        types.StringValue(stepActionName),
+		"step_action_exists":      stepActionExists,
+		"name":                    types.StringValue(metadata.DisplayName),
+		"facets_resource_name":    types.StringValue(metadata.ResourceName),
+		"facets_resource_kind":    types.StringValue(metadata.ResourceKind),
+		"environment_unique_name": types.StringValue(metadata.EnvUniqueName),
+		"cluster_id":              types.StringValue(metadata.ClusterID),
+		"action_type":             types.StringValue(actionType),
+		"description":             description,
+		"params":                  params,
+	})
+	diags.Append(d...)
+	return obj, diags
+}
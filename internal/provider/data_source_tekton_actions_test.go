@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestTektonActionsDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	task := testActionTask(t)
+	stepActionName := "setup-credentials-" + task.GetName()
+	other := testfake.Task(task.GetNamespace(), "unrelated", map[string]string{"app": "other"})
+
+	tests := []struct {
+		name       string
+		config     map[string]tftypes.Value
+		stepAction bool
+		forbidden  bool
+		wantCount  int
+		wantExists types.Bool
+	}{
+		{
+			name:       "matching environment",
+			config:     map[string]tftypes.Value{"environment_unique_name": tftypes.NewValue(tftypes.String, "production")},
+			stepAction: true,
+			wantCount:  1,
+			wantExists: types.BoolValue(true),
+		},
+		{
+			name:       "missing StepAction",
+			config:     map[string]tftypes.Value{"resource_kind": tftypes.NewValue(tftypes.String, "service")},
+			wantCount:  1,
+			wantExists: types.BoolValue(false),
+		},
+		{
+			name:       "StepActions forbidden",
+			config:     map[string]tftypes.Value{},
+			forbidden:  true,
+			wantCount:  1,
+			wantExists: types.BoolNull(),
+		},
+		{
+			name:      "other environment",
+			config:    map[string]tftypes.Value{"environment_unique_name": tftypes.NewValue(tftypes.String, "staging")},
+			wantCount: 0,
+		},
+		{
+			name:      "AWS actions only",
+			config:    map[string]tftypes.Value{"cloud_action": tftypes.NewValue(tftypes.Bool, true)},
+			wantCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := testfake.NewClient(task.DeepCopy(), other)
+			if tt.stepAction {
+				if err := client.Tracker().Add(testfake.StepAction(task.GetNamespace(), stepActionName, task.GetLabels())); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.forbidden {
+				testfake.WithError(client, "list", stepActionGVR, testfake.ErrForbidden(stepActionGVR, ""))
+			}
+			d := &TektonActionsDataSource{clientFactory: func() (dynamic.Interface, error) { return client, nil }}
+
+			resp := readDataSource(t, d, tt.config)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() diags = %v", resp.Diagnostics)
+			}
+			var state TektonActionsDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+
+			actions := state.Actions.Elements()
+			if len(actions) != tt.wantCount {
+				t.Fatalf("actions = %s, want %d", state.Actions, tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			attrs := actions[0].(types.Object).Attributes()
+			if attrs["task_name"] != types.StringValue(task.GetName()) || attrs["name"] != types.StringValue("my-action") {
+				t.Errorf("task_name = %s, name = %s", attrs["task_name"], attrs["name"])
+			}
+			if attrs["description"] != types.StringValue("Restart the API") || attrs["action_type"] != types.StringValue("kubernetes") {
+				t.Errorf("description = %s, action_type = %s", attrs["description"], attrs["action_type"])
+			}
+			if !attrs["step_action_exists"].Equal(tt.wantExists) {
+				t.Errorf("step_action_exists = %s, want %s", attrs["step_action_exists"], tt.wantExists)
+			}
+		})
+	}
+}
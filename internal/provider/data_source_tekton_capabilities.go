@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ datasource.DataSource              = &TektonCapabilitiesDataSource{}
+	_ datasource.DataSourceWithConfigure = &TektonCapabilitiesDataSource{}
+)
+
+// NewTektonCapabilitiesDataSource creates a new capabilities data source
+func NewTektonCapabilitiesDataSource() datasource.DataSource {
+	return &TektonCapabilitiesDataSource{
+		clientFactory:    k8s.GetKubernetesClient,
+		discoveryFactory: k8s.GetDiscoveryClient,
+	}
+}
+
+// TektonCapabilitiesDataSource describes the cluster's Tekton installation, so
+// modules can adapt to what it supports
+type TektonCapabilitiesDataSource struct {
+	providerData *FacetsProviderModel
+	// clientFactory and discoveryFactory produce Kubernetes clients from the
+	// provider's client pool, like the resources' fields of the same name.
+	// Tests in the same package may override them to inject fakes.
+	clientFactory    func() (dynamic.Interface, error)
+	discoveryFactory func() (discovery.DiscoveryInterface, error)
+}
+
+// TektonCapabilitiesDataSourceModel represents the data source data model
+type TektonCapabilitiesDataSourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	TektonNamespace    types.String `tfsdk:"tekton_namespace"`
+	PipelinesVersion   types.String `tfsdk:"pipelines_version"`
+	TaskVersions       types.List   `tfsdk:"task_versions"`
+	StepActionVersions types.List   `tfsdk:"step_action_versions"`
+	PipelineVersions   types.List   `tfsdk:"pipeline_versions"`
+	FeatureFlags       types.Map    `tfsdk:"feature_flags"`
+	TriggersInstalled  types.Bool   `tfsdk:"triggers_installed"`
+	ResultsInstalled   types.Bool   `tfsdk:"results_installed"`
+	ChainsInstalled    types.Bool   `tfsdk:"chains_installed"`
+}
+
+func (d *TektonCapabilitiesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tekton_capabilities"
+}
+
+func (d *TektonCapabilitiesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Describes the Tekton installation of the cluster: the Tekton Pipelines version, the served API versions, " +
+			"the feature flags, and whether Tekton Triggers, Results and Chains are installed. " +
+			"Values the provider's identity may not read are null.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the data source, the Tekton namespace",
+				Computed:    true,
+			},
+			"tekton_namespace": schema.StringAttribute{
+				Description: "Namespace Tekton Pipelines is installed in. Defaults to the provider's tekton_namespace, or \"tekton-pipelines\".",
+				Optional:    true,
+			},
+			"pipelines_version": schema.StringAttribute{
+				Description: "Tekton Pipelines version, e.g. \"v0.62.1\", from the pipelines-info ConfigMap or the controller Deployment. " +
+					"Null when Tekton Pipelines is not installed or the version is unknown.",
+				Computed: true,
+			},
+			"task_versions": schema.ListAttribute{
+				Description: "Served tekton.dev versions of Tasks, preferred version first. Empty when Tasks are not served.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"step_action_versions": schema.ListAttribute{
+				Description: "Served tekton.dev versions of StepActions, preferred version first. Empty when StepActions are not served.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"pipeline_versions": schema.ListAttribute{
+				Description: "Served tekton.dev versions of Pipelines, preferred version first. Empty when Pipelines are not served.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"feature_flags": schema.MapAttribute{
+				Description: "Data of Tekton's feature-flags ConfigMap, e.g. enable-step-actions. Null when the ConfigMap does not exist.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"triggers_installed": schema.BoolAttribute{
+				Description: "Whether Tekton Triggers is installed, i.e. triggers.tekton.dev is served",
+				Computed:    true,
+			},
+			"results_installed": schema.BoolAttribute{
+				Description: "Whether Tekton Results is installed, i.e. the tekton-results-api Deployment exists in tekton_namespace",
+				Computed:    true,
+			},
+			"chains_installed": schema.BoolAttribute{
+				Description: "Whether Tekton Chains is installed, i.e. the tekton-chains-controller Deployment exists in the tekton-chains namespace",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *TektonCapabilitiesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Clients are created lazily in Read, so terraform validate needs no kubeconfig
+	if req.ProviderData != nil {
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Provider Data Type",
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
+			)
+			return
+		}
+		d.providerData = providerData.Model
+		d.clientFactory = kubernetesClientFactory(providerData)
+		d.discoveryFactory = kubernetesDiscoveryFactory(providerData)
+	}
+}
+
+func (d *TektonCapabilitiesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TektonCapabilitiesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clientFactory, discoveryFactory := d.clientFactory, d.discoveryFactory
+	if clientFactory == nil {
+		clientFactory = k8s.GetKubernetesClient
+	}
+	if discoveryFactory == nil {
+		discoveryFactory = k8s.GetDiscoveryClient
+	}
+	client, err := clientFactory()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			fmt.Sprintf("failed to create Kubernetes client: %s", err.Error()),
+		)
+		return
+	}
+	disc, err := discoveryFactory()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Discovery Client",
+			err.Error(),
+		)
+		return
+	}
+
+	tektonNamespace := state.TektonNamespace.ValueString()
+	if tektonNamespace == "" {
+		tektonNamespace = d.providerData.tektonNamespace()
+	}
+	capabilities, err := tekton.DiscoverCapabilities(ctx, disc, client, tektonNamespace)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Tekton capabilities",
+			err.Error(),
+		)
+		return
+	}
+
+	state.ID = types.StringValue(tektonNamespace)
+	state.PipelinesVersion = types.StringNull()
+	if capabilities.PipelinesVersion != "" {
+		state.PipelinesVersion = types.StringValue(capabilities.PipelinesVersion)
+	}
+	state.TriggersInstalled = types.BoolValue(capabilities.TriggersInstalled)
+	state.ResultsInstalled = types.BoolPointerValue(capabilities.ResultsInstalled)
+	state.ChainsInstalled = types.BoolPointerValue(capabilities.ChainsInstalled)
+
+	var diags diag.Diagnostics
+	state.TaskVersions, diags = types.ListValueFrom(ctx, types.StringType, nonNilStrings(capabilities.TaskVersions))
+	resp.Diagnostics.Append(diags...)
+	state.StepActionVersions, diags = types.ListValueFrom(ctx, types.StringType, nonNilStrings(capabilities.StepActionVersions))
+	resp.Diagnostics.Append(diags...)
+	state.PipelineVersions, diags = types.ListValueFrom(ctx, types.StringType, nonNilStrings(capabilities.PipelineVersions))
+	resp.Diagnostics.Append(diags...)
+	state.FeatureFlags = types.MapNull(types.StringType)
+	if capabilities.FeatureFlags != nil {
+		state.FeatureFlags, diags = types.MapValueFrom(ctx, types.StringType, capabilities.FeatureFlags)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// nonNilStrings returns an empty slice for nil, so a list is empty rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestTektonCapabilitiesDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: tekton.GroupVersion,
+		APIResources: []metav1.APIResource{{Name: "tasks"}, {Name: "stepactions"}},
+	}}}}
+	client := testfake.NewClient(testfake.ConfigMap(tektonPipelinesNamespace, tekton.FeatureFlagsConfigMap, map[string]string{tekton.FeatureFlagEnableStepActions: "true"}))
+	d := &TektonCapabilitiesDataSource{
+		clientFactory:    func() (dynamic.Interface, error) { return client, nil },
+		discoveryFactory: func() (discovery.DiscoveryInterface, error) { return disc, nil },
+	}
+
+	resp := readDataSource(t, d, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() diags = %v", resp.Diagnostics)
+	}
+
+	var state TektonCapabilitiesDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueString() != tektonPipelinesNamespace {
+		t.Errorf("id = %s, want %s", state.ID, tektonPipelinesNamespace)
+	}
+	if !state.PipelinesVersion.IsNull() {
+		t.Errorf("pipelines_version = %s, want null without pipelines-info", state.PipelinesVersion)
+	}
+	if len(state.StepActionVersions.Elements()) != 1 || len(state.PipelineVersions.Elements()) != 0 || state.PipelineVersions.IsNull() {
+		t.Errorf("step_action_versions = %s, pipeline_versions = %s", state.StepActionVersions, state.PipelineVersions)
+	}
+	if len(state.FeatureFlags.Elements()) != 1 || state.TriggersInstalled.ValueBool() || state.ResultsInstalled.ValueBool() {
+		t.Errorf("feature_flags = %s, triggers_installed = %s, results_installed = %s", state.FeatureFlags, state.TriggersInstalled, state.ResultsInstalled)
+	}
+}
+
+func TestTektonCapabilitiesDataSource_ProviderTektonNamespace(t *testing.T) {
+	ctx := context.Background()
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	client := testfake.NewClient(testfake.ConfigMap("openshift-pipelines", tekton.FeatureFlagsConfigMap, map[string]string{tekton.FeatureFlagEnableStepActions: "true"}))
+	d := &TektonCapabilitiesDataSource{
+		providerData:     &FacetsProviderModel{TektonNamespace: types.StringValue("openshift-pipelines")},
+		clientFactory:    func() (dynamic.Interface, error) { return client, nil },
+		discoveryFactory: func() (discovery.DiscoveryInterface, error) { return disc, nil },
+	}
+
+	resp := readDataSource(t, d, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() diags = %v", resp.Diagnostics)
+	}
+
+	var state TektonCapabilitiesDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueString() != "openshift-pipelines" || len(state.FeatureFlags.Elements()) != 1 {
+		t.Errorf("id = %s, feature_flags = %s, want the provider's tekton_namespace", state.ID, state.FeatureFlags)
+	}
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &actionNameFunction{}
+
+// NewTaskNameFunction creates the task_name function
+func NewTaskNameFunction() function.Function {
+	return &actionNameFunction{
+		name:    "task_name",
+		summary: "Task name of an action",
+		description: "Returns the name of the Task facets_tekton_action_kubernetes and facets_tekton_action_aws create " +
+			"for an action, without creating it.",
+		result: func(namespace string, names *tekton.ResourceNames) string {
+			return names.TaskName
+		},
+	}
+}
+
+// NewStepActionNameFunction creates the step_action_name function
+func NewStepActionNameFunction() function.Function {
+	return &actionNameFunction{
+		name:    "step_action_name",
+		summary: "Credential StepAction name of an action",
+		description: "Returns the name of the credential StepAction facets_tekton_action_kubernetes and facets_tekton_action_aws " +
+			"create for an action, without creating it.",
+		result: func(namespace string, names *tekton.ResourceNames) string {
+			return names.StepActionName
+		},
+	}
+}
+
+// NewActionIDFunction creates the action_id function
+func NewActionIDFunction() function.Function {
+	return &actionNameFunction{
+		name:    "action_id",
+		summary: "Resource ID of an action",
+		description: "Returns the id of facets_tekton_action_kubernetes and facets_tekton_action_aws for an action, " +
+			"namespace/task_name, without creating it. It is also the ID to import an action by.",
+		withNamespace: true,
+		result: func(namespace string, names *tekton.ResourceNames) string {
+			return namespace + "/" + names.TaskName
+		},
+	}
+}
+
+// actionNameFunction computes a name of an action from its Facets identity
+// with tekton.GenerateNames, so modules can reference an action before it is
+// created and always get the names the action resources use
+type actionNameFunction struct {
+	name        string
+	summary     string
+	description string
+	// withNamespace adds a leading namespace parameter
+	withNamespace bool
+	result        func(namespace string, names *tekton.ResourceNames) string
+}
+
+func (f *actionNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *actionNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	var params []function.Parameter
+	if f.withNamespace {
+		params = append(params, function.StringParameter{
+			Name:        "namespace",
+			Description: "Namespace of the action",
+		})
+	}
+	params = append(params,
+		function.StringParameter{
+			Name:        "resource_name",
+			Description: "facets_resource_name of the action",
+		},
+		function.StringParameter{
+			Name:        "environment_unique_name",
+			Description: "unique_name of the action's facets_environment",
+		},
+		function.StringParameter{
+			Name:        "name",
+			Description: "Display name of the action, its name attribute",
+		},
+	)
+
+	resp.Definition = function.Definition{
+		Summary:     f.summary,
+		Description: f.description,
+		Parameters:  params,
+		Return:      function.StringReturn{},
+	}
+}
+
+func (f *actionNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var namespace, resourceName, envUniqueName, displayName string
+	args := []any{&resourceName, &envUniqueName, &displayName}
+	if f.withNamespace {
+		args = append([]any{&namespace}, args...)
+	}
+
+	resp.Error = req.Arguments.Get(ctx, args...)
+	if resp.Error != nil {
+		return
+	}
+
+	names := tekton.GenerateNames(resourceName, envUniqueName, displayName)
+	resp.Error = resp.Result.Set(ctx, f.result(namespace, names))
+}
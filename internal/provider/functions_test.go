@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestActionNameFunctions(t *testing.T) {
+	ctx := context.Background()
+	names := tekton.GenerateNames("my-app", "production", "my-action")
+	identity := []attr.Value{types.StringValue("my-app"), types.StringValue("production"), types.StringValue("my-action")}
+
+	tests := []struct {
+		name string
+		fn   function.Function
+		args []attr.Value
+		want string
+	}{
+		{"task_name", NewTaskNameFunction(), identity, names.TaskName},
+		{"step_action_name", NewStepActionNameFunction(), identity, names.StepActionName},
+		{"action_id", NewActionIDFunction(), append([]attr.Value{types.StringValue("tekton-pipelines")}, identity...), "tekton-pipelines/" + names.TaskName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defResp := &function.DefinitionResponse{}
+			tt.fn.Definition(ctx, function.DefinitionRequest{}, defResp)
+			if len(defResp.Definition.Parameters) != len(tt.args) {
+				t.Fatalf("Definition() has %d parameters, want %d", len(defResp.Definition.Parameters), len(tt.args))
+			}
+
+			resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+			tt.fn.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(tt.args)}, resp)
+			if resp.Error != nil {
+				t.Fatalf("Run() error = %v", resp.Error)
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("Run() = %s, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskNameFunction_MatchesResource(t *testing.T) {
+	ctx := context.Background()
+	in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+	planned, _ := plannedNames(ctx, in)
+
+	resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	NewTaskNameFunction().Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{
+		in.FacetsResourceName, types.StringValue("production"), in.Name,
+	})}, resp)
+	if got := resp.Result.Value(); !got.Equal(types.StringValue(planned.TaskName)) {
+		t.Errorf("task_name() = %s, want the planned task_name %s", got, planned.TaskName)
+	}
+}
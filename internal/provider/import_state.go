@@ -0,0 +1,416 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// actionIdentityModel is the resource identity of an action: the namespace and
+// name of its Task. Unlike the Facets identity, the Task name never changes
+// over the lifetime of an action.
+type actionIdentityModel struct {
+	Namespace types.String `tfsdk:"namespace"`
+	TaskName  types.String `tfsdk:"task_name"`
+}
+
+// actionIdentitySchema is the identity schema of both action resources
+func actionIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"namespace": identityschema.StringAttribute{
+				Description:       "Namespace of the action. Defaults to the provider's default_namespace on import.",
+				OptionalForImport: true,
+			},
+			"task_name": identityschema.StringAttribute{
+				Description:       "Name of the action's Task, e.g. computed with provider::facets::task_name",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// setActionIdentity sets the identity of an action. identity is nil when
+// Terraform does not support resource identity.
+func setActionIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, namespace, taskName types.String) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, actionIdentityModel{Namespace: namespace, TaskName: taskName})
+}
+
+// importTarget is the Task an import refers to
+type importTarget struct {
+	Namespace string
+	TaskName  string
+	// Facets is the Facets identity the Task name was computed from, nil when
+	// the import names the Task directly
+	Facets *facetsIdentity
+}
+
+// facetsIdentity identifies an action the way Facets does
+type facetsIdentity struct {
+	EnvUniqueName string
+	ResourceName  string
+	DisplayName   string
+}
+
+// importActionTarget resolves the Task an import refers to, from the import
+// identity or an ID of the form [namespace/]task_name or
+// [namespace/]environment_unique_name:facets_resource_name:name. Task names
+// are computed from a Facets identity with tekton.GenerateNames. The
+// namespace defaults to defaultNamespace.
+func importActionTarget(ctx context.Context, req resource.ImportStateRequest, defaultNamespace string) (target importTarget, diags diag.Diagnostics) {
+	target.Namespace = defaultNamespace
+
+	if req.ID == "" && req.Identity != nil {
+		var identity actionIdentityModel
+		diags.Append(req.Identity.Get(ctx, &identity)...)
+		if diags.HasError() {
+			return target, diags
+		}
+		if identity.Namespace.ValueString() != "" {
+			target.Namespace = identity.Namespace.ValueString()
+		}
+		target.TaskName = identity.TaskName.ValueString()
+		return target, diags
+	}
+
+	rest := req.ID
+	// A display name may contain slashes; only one before the Facets identity
+	// separates the namespace
+	colon := strings.Index(rest, ":")
+	if slash := strings.Index(rest, "/"); slash >= 0 && (colon < 0 || slash < colon) {
+		target.Namespace, rest = rest[:slash], rest[slash+1:]
+	}
+
+	if strings.Contains(rest, ":") {
+		parts := strings.SplitN(rest, ":", 3)
+		if len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "" && target.Namespace != "" {
+			target.Facets = &facetsIdentity{EnvUniqueName: parts[0], ResourceName: parts[1], DisplayName: parts[2]}
+			target.TaskName = tekton.GenerateNames(parts[1], parts[0], parts[2]).TaskName
+			return target, diags
+		}
+	} else if rest != "" && target.Namespace != "" && !strings.Contains(rest, "/") {
+		target.TaskName = rest
+		return target, diags
+	}
+
+	diags.AddError(
+		"Invalid Import ID",
+		fmt.Sprintf("Expected an import ID of the form [namespace/]task_name or "+
+			"[namespace/]environment_unique_name:facets_resource_name:name, got: %s", req.ID),
+	)
+	return target, diags
+}
+
+// String describes the target for diagnostics
+func (t importTarget) String() string {
+	if t.Facets == nil {
+		return fmt.Sprintf("Task %s/%s", t.Namespace, t.TaskName)
+	}
+	return fmt.Sprintf("Task %s/%s of action %q of resource %q in environment %q",
+		t.Namespace, t.TaskName, t.Facets.DisplayName, t.Facets.ResourceName, t.Facets.EnvUniqueName)
+}
+
+// check confirms that an imported Task belongs to the Facets identity it was
+// found by, so an import never adopts a Task of another action
+func (t importTarget) check(imported importedAction) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if t.Facets == nil {
+		return diags
+	}
+	got := facetsIdentity{
+		EnvUniqueName: imported.EnvUniqueName,
+		ResourceName:  imported.FacetsResourceName.ValueString(),
+		DisplayName:   imported.Name.ValueString(),
+	}
+	if got != *t.Facets {
+		diags.AddError(
+			"Error importing resource",
+			fmt.Sprintf("%s belongs to action %q of resource %q in environment %q. "+
+				"Import it by its Task name if it was renamed after it was created.",
+				t, got.DisplayName, got.ResourceName, got.EnvUniqueName),
+		)
+	}
+	return diags
+}
+
+// importedAction is the configuration of an action read back from its Task,
+// credential StepAction and env Secret by terraform import
+type importedAction struct {
+	Name               types.String
+	Description        types.String
+	FacetsResourceName types.String
+	FacetsEnvironment  types.Object
+	FacetsResource     types.Object
+	Labels             types.Map
+	Annotations        types.Map
+	Steps              types.List
+	Params             types.List
+	StepActionName     types.String
+	ClusterID          types.String
+	CredentialStep     types.Object
+	// EnvUniqueName is the unique_name of FacetsEnvironment
+	EnvUniqueName string
+}
+
+// importAction parses an action's configuration back from its Task, leaving
+// out what the provider injects: the setup-credentials step and the params
+// and env vars in reserved. Labels and annotations exclude the auto-generated
+// ones and unchanged provider defaults, and step resources equal to the
+// provider's default_step_resources are left null, so importing an action
+// managed by the same configuration plans no changes.
+//
+// The StepAction is the one the setup-credentials step references. Its image
+// and the step's pull policy become credential_step where they differ from the
+// provider's. A missing StepAction, or sensitive_env values missing from the
+// env Secret, are reported as warnings: the next apply writes them again. So
+// is an rbac ServiceAccount, since the rules are not read back.
+func importAction(ctx context.Context, client dynamic.Interface, task *unstructured.Unstructured, reserved tekton.ReservedNames, providerData *FacetsProviderModel) (imported importedAction, diags diag.Diagnostics) {
+	// Recover identity from the facets.cloud/* annotations (exact values),
+	// falling back to the labels for Tasks created before they existed
+	metadata, err := tekton.MetadataFromObject(task)
+	if err != nil {
+		diags.AddError("Error importing resource", err.Error())
+		return imported, diags
+	}
+	imported.Name = types.StringValue(metadata.DisplayName)
+	imported.FacetsResourceName = types.StringValue(metadata.ResourceName)
+	imported.ClusterID = types.StringValue(metadata.ClusterID)
+	imported.EnvUniqueName = metadata.EnvUniqueName
+	imported.FacetsEnvironment = types.ObjectValueMust(
+		map[string]attr.Type{"unique_name": types.StringType},
+		map[string]attr.Value{"unique_name": types.StringValue(metadata.EnvUniqueName)},
+	)
+	imported.FacetsResource = types.ObjectValueMust(
+		map[string]attr.Type{"kind": types.StringType},
+		map[string]attr.Value{"kind": types.StringValue(metadata.ResourceKind)},
+	)
+
+	if serviceAccount := metadata.CustomAnnotations[tekton.AnnotationServiceAccount]; serviceAccount != "" {
+		diags.AddWarning(
+			"RBAC Not Imported",
+			fmt.Sprintf("The Task %s/%s runs as the ServiceAccount %s, created by the rbac attribute, which import does not read back. "+
+				"Add the same rbac block to the configuration; the next apply updates the ServiceAccount, role and binding.",
+				task.GetNamespace(), task.GetName(), serviceAccount),
+		)
+	}
+	delete(metadata.CustomAnnotations, tekton.AnnotationKubeconfigDelivery)
+	delete(metadata.CustomAnnotations, tekton.AnnotationServiceAccount)
+	imported.Labels = withoutDefaults(metadata.CustomLabels, providerData.defaultLabels())
+	imported.Annotations = withoutDefaults(metadata.CustomAnnotations, providerData.defaultAnnotations())
+
+	parsed := tekton.ParseTask(task, reserved)
+	imported.Description = types.StringNull()
+	if parsed.Description != "" {
+		imported.Description = types.StringValue(parsed.Description)
+	}
+	imported.Params = types.ListNull(tekton.ParamObjectType)
+	if len(parsed.Params) > 0 {
+		var d diag.Diagnostics
+		imported.Params, d = parsedParamsValue(parsed.Params)
+		diags.Append(d...)
+	}
+
+	secretData, d := importSecretData(ctx, client, task, parsed.Steps)
+	diags.Append(d...)
+	if diags.HasError() {
+		return imported, diags
+	}
+	policy, _ := providerData.stepPolicy(ctx)
+	imported.Steps, d = importedStepsValue(parsed.Steps, secretData, policy.DefaultResources)
+	diags.Append(d...)
+
+	stepActionName := credentialStepActionName(task)
+	imported.StepActionName = types.StringValue(stepActionName)
+	imported.CredentialStep = types.ObjectNull(credentialStepAttrTypes)
+	stepAction, err := client.Resource(stepActionGVR).Namespace(task.GetNamespace()).Get(ctx, stepActionName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		imported.CredentialStep, d = importedCredentialStep(ctx, task, stepAction, providerData)
+		diags.Append(d...)
+	case apierrors.IsNotFound(err):
+		diags.AddWarning(
+			"StepAction Not Found",
+			fmt.Sprintf("The Task %s/%s references the StepAction %s, which does not exist. The next apply creates it again.",
+				task.GetNamespace(), task.GetName(), stepActionName),
+		)
+	case err != nil:
+		diags.AddError(
+			"Error importing resource",
+			fmt.Sprintf("Could not read StepAction %s/%s: %s", task.GetNamespace(), stepActionName, err.Error()),
+		)
+	}
+	return imported, diags
+}
+
+// importedCredentialStep returns the credential_step of an action: the
+// StepAction's image and the setup-credentials step's pull policy, each null
+// when it matches what the provider's credential_step resolves to
+func importedCredentialStep(ctx context.Context, task, stepAction *unstructured.Unstructured, providerData *FacetsProviderModel) (types.Object, diag.Diagnostics) {
+	providerStep, _, diags := providerData.credentialStep(ctx, types.ObjectNull(credentialStepAttrTypes))
+	if diags.HasError() {
+		return types.ObjectNull(credentialStepAttrTypes), diags
+	}
+
+	image := types.StringNull()
+	if current, _, _ := unstructured.NestedString(stepAction.Object, "spec", "image"); current != "" && current != providerStep.ImageOrDefault() {
+		image = types.StringValue(current)
+	}
+	pullPolicy := types.StringNull()
+	steps, _, _ := unstructured.NestedSlice(task.Object, "spec", "steps")
+	for _, s := range steps {
+		step, _ := s.(map[string]interface{})
+		if step["name"] != tekton.SetupCredentialsStepName {
+			continue
+		}
+		if current, _, _ := unstructured.NestedString(step, "imagePullPolicy"); current != "" && current != providerStep.ImagePullPolicy {
+			pullPolicy = types.StringValue(current)
+		}
+	}
+
+	if image.IsNull() && pullPolicy.IsNull() {
+		return types.ObjectNull(credentialStepAttrTypes), diags
+	}
+	credentialStep, d := types.ObjectValue(credentialStepAttrTypes, map[string]attr.Value{
+		"image":             image,
+		"image_pull_policy": pullPolicy,
+	})
+	diags.Append(d...)
+	return credentialStep, diags
+}
+
+// importSecretData reads the env Secret of a Task when any step reads from it
+func importSecretData(ctx context.Context, client dynamic.Interface, task *unstructured.Unstructured, steps []tekton.ParsedStep) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	sensitive := false
+	for _, step := range steps {
+		sensitive = sensitive || len(step.SensitiveEnv) > 0
+	}
+	if !sensitive {
+		return nil, diags
+	}
+
+	secretName := tekton.EnvSecretName(task.GetName())
+	secret, err := client.Resource(secretGVR).Namespace(task.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		diags.AddError(
+			"Error reading Secret",
+			fmt.Sprintf("Could not read Secret %s/%s: %s", task.GetNamespace(), secretName, err.Error()),
+		)
+		return nil, diags
+	}
+	if err != nil {
+		return map[string]string{}, diags
+	}
+	return tekton.SecretData(secret), diags
+}
+
+// importedStepsValue converts parsed steps to a resource's steps list. A step
+// whose sensitive_env values are missing from the Secret gets a null
+// sensitive_env, like refresh does, so the next apply writes them again.
+func importedStepsValue(steps []tekton.ParsedStep, secretData map[string]string, defaults tekton.ResourceList) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	elems := make([]attr.Value, 0, len(steps))
+	for _, step := range steps {
+		resources := types.ObjectNull(tekton.ComputeResourcesObjectType.AttrTypes)
+		fromDefaults := stringMapsEqual(step.Requests, defaults.Requests) && stringMapsEqual(step.Limits, defaults.Limits)
+		if (len(step.Requests) > 0 || len(step.Limits) > 0) && !fromDefaults {
+			resources = types.ObjectValueMust(tekton.ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+				"requests": optionalStringMap(step.Requests),
+				"limits":   optionalStringMap(step.Limits),
+			})
+		}
+
+		env := types.ListNull(tekton.EnvVarObjectType)
+		if len(step.Env) > 0 {
+			envElems := make([]attr.Value, 0, len(step.Env))
+			for _, e := range step.Env {
+				envElems = append(envElems, types.ObjectValueMust(tekton.EnvVarObjectType.AttrTypes, map[string]attr.Value{
+					"name":  types.StringValue(e.Name),
+					"value": types.StringValue(e.Value),
+				}))
+			}
+			env = types.ListValueMust(tekton.EnvVarObjectType, envElems)
+		}
+
+		sensitiveEnv := types.MapNull(types.StringType)
+		if len(step.SensitiveEnv) > 0 {
+			values := make(map[string]attr.Value, len(step.SensitiveEnv))
+			var missing []string
+			for name, key := range step.SensitiveEnv {
+				value, ok := secretData[key]
+				if !ok {
+					missing = append(missing, name)
+					continue
+				}
+				values[name] = types.StringValue(value)
+			}
+			if len(missing) == 0 {
+				sensitiveEnv = types.MapValueMust(types.StringType, values)
+			} else {
+				sort.Strings(missing)
+				diags.AddWarning(
+					"Sensitive Env Not Found",
+					fmt.Sprintf("The env Secret holds no value for %v of step %q. The next apply writes sensitive_env again.", missing, step.Name),
+				)
+			}
+		}
+
+		elems = append(elems, types.ObjectValueMust(tekton.StepObjectType.AttrTypes, map[string]attr.Value{
+			"name":          types.StringValue(step.Name),
+			"image":         types.StringValue(step.Image),
+			"script":        types.StringValue(step.Script),
+			"resources":     resources,
+			"env":           env,
+			"sensitive_env": sensitiveEnv,
+		}))
+	}
+	list, d := types.ListValue(tekton.StepObjectType, elems)
+	diags.Append(d...)
+	return list, diags
+}
+
+// withoutDefaults returns the entries of m that are not provider defaults
+// with the same value, or null when none are left
+func withoutDefaults(m map[string]string, defaults types.Map) types.Map {
+	elems := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		if d, ok := defaults.Elements()[k].(types.String); ok && d.ValueString() == v {
+			continue
+		}
+		elems[k] = types.StringValue(v)
+	}
+	if len(elems) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
+
+// stringMapsEqual reports whether a and b hold the same entries, treating nil
+// and empty maps alike
+func stringMapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
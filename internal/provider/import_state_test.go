@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// asFromServer returns a copy of a rendered object as the apiserver returns it
+func asFromServer(t *testing.T, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	t.Helper()
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &out.Object); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// testImportPlan returns a Kubernetes action plan with custom labels, a
+// param, and steps with env, sensitive_env and explicit resources, and the
+// provider it is planned with
+func testImportPlan() (TektonActionKubernetesResourceModel, *FacetsProviderModel) {
+	providerData := &FacetsProviderModel{
+		DefaultLabels: testStringMap(map[string]string{"team": "platform"}),
+		DefaultStepResources: types.ObjectValueMust(tekton.ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+			"requests": testStringMap(map[string]string{"cpu": "100m"}),
+			"limits":   types.MapNull(types.StringType),
+		}),
+		MaxStepResources:       types.ObjectNull(tekton.ComputeResourcesObjectType.AttrTypes),
+		AllowedImageRegistries: types.ListNull(types.StringType),
+	}
+
+	plan := testPlanWithImage("bitnami/kubectl:1.30")
+	plan.Description = types.StringValue("Restart the API")
+	plan.Labels = testStringMap(map[string]string{"owner": "api-team"})
+	plan.Annotations = testStringMap(map[string]string{"docs": "https://example.com/runbook"})
+	plan.Params = types.ListValueMust(tekton.ParamObjectType, []attr.Value{
+		types.ObjectValueMust(tekton.ParamObjectType.AttrTypes, map[string]attr.Value{
+			"name": types.StringValue("DEPLOYMENT"),
+			"type": types.StringValue("string"),
+		}),
+	})
+	migrate := types.ObjectValueMust(tekton.StepObjectType.AttrTypes, map[string]attr.Value{
+		"name":   types.StringValue("migrate"),
+		"image":  types.StringValue("migrate/migrate:v4"),
+		"script": types.StringValue("migrate up"),
+		"resources": types.ObjectValueMust(tekton.ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+			"requests": testStringMap(map[string]string{"memory": "256Mi"}),
+			"limits":   testStringMap(map[string]string{"memory": "512Mi"}),
+		}),
+		"env": types.ListValueMust(tekton.EnvVarObjectType, []attr.Value{
+			types.ObjectValueMust(tekton.EnvVarObjectType.AttrTypes, map[string]attr.Value{
+				"name":  types.StringValue("LOG_LEVEL"),
+				"value": types.StringValue("debug"),
+			}),
+		}),
+		"sensitive_env": testStringMap(map[string]string{"DATABASE_URL": "postgres://u:p@db/app"}),
+	})
+	plan.Steps = types.ListValueMust(tekton.StepObjectType, append(plan.Steps.Elements(), migrate))
+	return plan, providerData
+}
+
+// renderImportObjects renders the objects of a plan as the apiserver returns them
+func renderImportObjects(t *testing.T, plan TektonActionKubernetesResourceModel, providerData *FacetsProviderModel) (stepAction, task, secret *unstructured.Unstructured) {
+	t.Helper()
+	ctx := context.Background()
+	metadata, _, diags := buildActionMetadata(ctx, plan.metadataInput(), providerData)
+	if diags.HasError() {
+		t.Fatalf("buildActionMetadata() diags = %v", diags)
+	}
+	names, _ := plannedNames(ctx, plan.metadataInput())
+	plan.TaskName = types.StringValue(names.TaskName)
+	plan.StepActionName = types.StringValue(names.StepActionName)
+	policy, _ := providerData.stepPolicy(ctx)
+	credentialStep, _, _ := providerData.credentialStep(ctx, plan.CredentialStep)
+
+	stepAction, task, secret = (&TektonActionKubernetesResource{}).renderObjects(ctx, plan, policy, credentialStep, metadata)
+	return asFromServer(t, stepAction), asFromServer(t, task), asFromServer(t, secret)
+}
+
+func TestImportAction_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	plan, providerData := testImportPlan()
+	stepAction, task, secret := renderImportObjects(t, plan, providerData)
+	client := testfake.NewClient(stepAction, task, secret)
+
+	imported, diags := importAction(ctx, client, task, tekton.KubernetesReservedNames(), providerData)
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("importAction() diags = %v", diags)
+	}
+
+	for name, c := range map[string]struct{ got, want attr.Value }{
+		"name":                 {imported.Name, plan.Name},
+		"description":          {imported.Description, plan.Description},
+		"facets_resource_name": {imported.FacetsResourceName, plan.FacetsResourceName},
+		"facets_environment":   {imported.FacetsEnvironment, plan.FacetsEnvironment},
+		"facets_resource":      {imported.FacetsResource, plan.FacetsResource},
+		"labels":               {imported.Labels, plan.Labels},
+		"annotations":          {imported.Annotations, plan.Annotations},
+		"params":               {imported.Params, plan.Params},
+		"steps":                {imported.Steps, plan.Steps},
+		"cluster_id":           {imported.ClusterID, plan.ClusterID},
+		"step_action_name":     {imported.StepActionName, types.StringValue(stepAction.GetName())},
+		"credential_step":      {imported.CredentialStep, plan.CredentialStep},
+	} {
+		if !c.got.Equal(c.want) {
+			t.Errorf("%s = %v, want %v", name, c.got, c.want)
+		}
+	}
+}
+
+func TestImportAction_MissingStepActionAndSecret(t *testing.T) {
+	ctx := context.Background()
+	plan, providerData := testImportPlan()
+	_, task, _ := renderImportObjects(t, plan, providerData)
+	client := testfake.NewClient(task)
+
+	imported, diags := importAction(ctx, client, task, tekton.KubernetesReservedNames(), providerData)
+	if diags.HasError() {
+		t.Fatalf("importAction() diags = %v", diags)
+	}
+	if diags.WarningsCount() != 2 {
+		t.Errorf("expected warnings for the StepAction and the sensitive_env values, got %v", summaries(diags))
+	}
+
+	var steps []tekton.StepModel
+	imported.Steps.ElementsAs(ctx, &steps, false)
+	if len(steps) != 2 || !steps[1].SensitiveEnv.IsNull() {
+		t.Errorf("expected sensitive_env to be null without the Secret, got %v", imported.Steps)
+	}
+}
+
+func TestImportAction_CredentialStepAndRBAC(t *testing.T) {
+	ctx := context.Background()
+	plan, providerData := testImportPlan()
+	image := types.StringValue("harbor.example.com/facets/actions-base-image:v1.0.0")
+	plan.CredentialStep = testCredentialStepOverride(image, types.StringValue("Always"))
+	stepAction, task, secret := renderImportObjects(t, plan, providerData)
+	annotations := task.GetAnnotations()
+	annotations[tekton.AnnotationServiceAccount] = "facets-action-runner"
+	task.SetAnnotations(annotations)
+	client := testfake.NewClient(stepAction, task, secret)
+
+	imported, diags := importAction(ctx, client, task, tekton.KubernetesReservedNames(), providerData)
+	if diags.HasError() {
+		t.Fatalf("importAction() diags = %v", diags)
+	}
+	if !imported.CredentialStep.Equal(plan.CredentialStep) {
+		t.Errorf("credential_step = %v, want %v", imported.CredentialStep, plan.CredentialStep)
+	}
+	if diags.WarningsCount() != 1 || diags[0].Summary() != "RBAC Not Imported" {
+		t.Errorf("expected a warning about rbac, got %v", summaries(diags))
+	}
+
+	// A credential step the provider sets is not an override of the action
+	providerData.CredentialStep = testProviderCredentialStep(image, types.StringValue("Always"), types.StringNull())
+	imported, _ = importAction(ctx, client, task, tekton.KubernetesReservedNames(), providerData)
+	if !imported.CredentialStep.IsNull() {
+		t.Errorf("credential_step = %v, want null when it matches the provider", imported.CredentialStep)
+	}
+}
+
+func TestImportActionTarget_ID(t *testing.T) {
+	ctx := context.Background()
+	restart := tekton.GenerateNames("my-app", "production", "Restart API pods").TaskName
+	odd := tekton.GenerateNames("my-app", "production", "a/b: c").TaskName
+
+	for _, tc := range []struct {
+		id, namespace, taskName string
+		facets                  bool
+	}{
+		{id: "59f6f855860ddc99a32e2944c96db5fa", namespace: "tekton-pipelines", taskName: "59f6f855860ddc99a32e2944c96db5fa"},
+		{id: "ops/59f6f855860ddc99a32e2944c96db5fa", namespace: "ops", taskName: "59f6f855860ddc99a32e2944c96db5fa"},
+		{id: "production:my-app:Restart API pods", namespace: "tekton-pipelines", taskName: restart, facets: true},
+		{id: "ops/production:my-app:Restart API pods", namespace: "ops", taskName: restart, facets: true},
+		{id: "ops/production:my-app:a/b: c", namespace: "ops", taskName: odd, facets: true},
+	} {
+		target, diags := importActionTarget(ctx, resource.ImportStateRequest{ID: tc.id}, "tekton-pipelines")
+		if diags.HasError() {
+			t.Errorf("importActionTarget(%q) diags = %v", tc.id, diags)
+			continue
+		}
+		if target.Namespace != tc.namespace || target.TaskName != tc.taskName || (target.Facets != nil) != tc.facets {
+			t.Errorf("importActionTarget(%q) = %+v, want %s/%s", tc.id, target, tc.namespace, tc.taskName)
+		}
+	}
+
+	for _, id := range []string{"", "ops/", "/abc", "a/b/c", "production:my-app", "production::name", "ops/production:my-app:"} {
+		if _, diags := importActionTarget(ctx, resource.ImportStateRequest{ID: id}, "tekton-pipelines"); !diags.HasError() {
+			t.Errorf("importActionTarget(%q) expected an error", id)
+		}
+	}
+}
+
+func TestImportActionTarget_Identity(t *testing.T) {
+	ctx := context.Background()
+	schema := actionIdentitySchema()
+	identity := func(namespace, taskName interface{}) *tfsdk.ResourceIdentity {
+		return &tfsdk.ResourceIdentity{
+			Schema: schema,
+			Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+				"namespace": tftypes.NewValue(tftypes.String, namespace),
+				"task_name": tftypes.NewValue(tftypes.String, taskName),
+			}),
+		}
+	}
+
+	target, diags := importActionTarget(ctx, resource.ImportStateRequest{Identity: identity(nil, "abc")}, "tekton-pipelines")
+	if diags.HasError() || target.Namespace != "tekton-pipelines" || target.TaskName != "abc" {
+		t.Errorf("importActionTarget() = %+v, %v", target, diags)
+	}
+	target, diags = importActionTarget(ctx, resource.ImportStateRequest{Identity: identity("ops", "abc")}, "tekton-pipelines")
+	if diags.HasError() || target.Namespace != "ops" || target.TaskName != "abc" {
+		t.Errorf("importActionTarget() = %+v, %v", target, diags)
+	}
+}
+
+// TestImportActionTarget_Check imports a Task by its Facets identity and
+// checks a Task labelled with another identity is refused
+func TestImportActionTarget_Check(t *testing.T) {
+	ctx := context.Background()
+	plan, providerData := testImportPlan()
+	_, task, _ := renderImportObjects(t, plan, providerData)
+	imported, diags := importAction(ctx, testfake.NewClient(task), task, tekton.KubernetesReservedNames(), providerData)
+	if diags.HasError() {
+		t.Fatalf("importAction() diags = %v", diags)
+	}
+
+	id := imported.EnvUniqueName + ":" + plan.FacetsResourceName.ValueString() + ":" + plan.Name.ValueString()
+	target, diags := importActionTarget(ctx, resource.ImportStateRequest{ID: id}, task.GetNamespace())
+	if diags.HasError() {
+		t.Fatalf("importActionTarget(%q) diags = %v", id, diags)
+	}
+	if target.TaskName != task.GetName() {
+		t.Errorf("task name = %s, want %s", target.TaskName, task.GetName())
+	}
+	if diags := target.check(imported); diags.HasError() {
+		t.Errorf("check() diags = %v", diags)
+	}
+
+	target.Facets.DisplayName = "Something else"
+	if diags := target.check(imported); !diags.HasError() {
+		t.Error("expected an error for a Task of another action")
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// manifestResourceAttribute is the schema of task_manifest and
+// step_action_manifest, kind being "Task" or "StepAction"
+func manifestResourceAttribute(kind string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("YAML of the %s the provider applies, rendered during plan so changes to it show up in "+
+			"terraform plan as a readable diff. Env vars from sensitive_env are redacted. Unknown while anything the "+
+			"%s depends on is unknown.", kind, kind),
+		Computed: true,
+	}
+}
+
+// actionManifests renders the StepAction and Task of an action as
+// step_action_manifest and task_manifest
+func actionManifests(stepAction, task *unstructured.Unstructured) (stepActionManifest, taskManifest types.String, diags diag.Diagnostics) {
+	secretName := tekton.EnvSecretName(task.GetName())
+	for _, m := range []struct {
+		obj    *unstructured.Unstructured
+		target *types.String
+	}{
+		{stepAction, &stepActionManifest},
+		{task, &taskManifest},
+	} {
+		manifest, err := tekton.Manifest(m.obj, secretName)
+		if err != nil {
+			diags.AddError("Unable to Render Manifest", err.Error())
+			return stepActionManifest, taskManifest, diags
+		}
+		*m.target = types.StringValue(manifest)
+	}
+	return stepActionManifest, taskManifest, diags
+}
+
+// planManifests sets step_action_manifest and task_manifest of a plan to the
+// planned StepAction and Task
+func planManifests(ctx context.Context, plan *tfsdk.Plan, stepAction, task *unstructured.Unstructured) diag.Diagnostics {
+	stepActionManifest, taskManifest, diags := actionManifests(stepAction, task)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(plan.SetAttribute(ctx, path.Root("step_action_manifest"), stepActionManifest)...)
+	diags.Append(plan.SetAttribute(ctx, path.Root("task_manifest"), taskManifest)...)
+	return diags
+}
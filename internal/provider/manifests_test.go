@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestActionManifests_MatchApply(t *testing.T) {
+	ctx := context.Background()
+	r := &TektonActionKubernetesResource{}
+	plan := testPlanWithImage("bitnami/kubectl:1.30")
+
+	stepAction, task, diags := r.planObjects(ctx, plan)
+	if diags.HasError() || task == nil {
+		t.Fatalf("planObjects() = %v, %v", task, diags)
+	}
+	plannedStepAction, plannedTask, diags := actionManifests(stepAction, task)
+	if diags.HasError() {
+		t.Fatalf("actionManifests() diags = %v", diags)
+	}
+	if !strings.Contains(plannedTask.ValueString(), "image: bitnami/kubectl:1.30") ||
+		!strings.Contains(plannedStepAction.ValueString(), "kind: StepAction") {
+		t.Errorf("task_manifest = %s\nstep_action_manifest = %s", plannedTask, plannedStepAction)
+	}
+
+	// Create renders the objects from the applied plan; the manifests must not change
+	metadata, _, _ := buildActionMetadata(ctx, plan.metadataInput(), nil)
+	names, _ := plannedNames(ctx, plan.metadataInput())
+	plan.TaskName = types.StringValue(names.TaskName)
+	plan.StepActionName = types.StringValue(names.StepActionName)
+	stepAction, task, _ = r.renderObjects(ctx, plan, tekton.StepPolicy{}, tekton.CredentialStep{}, metadata)
+	appliedStepAction, appliedTask, _ := actionManifests(stepAction, task)
+	if !appliedTask.Equal(plannedTask) || !appliedStepAction.Equal(plannedStepAction) {
+		t.Errorf("manifests at apply differ from the plan:\n%s\nvs\n%s", appliedTask, plannedTask)
+	}
+}
+
+// TestActionManifests_RenameKeepsNames renames an existing action and checks
+// the planned manifests keep the Task and StepAction names from state, as
+// Update does
+func TestActionManifests_RenameKeepsNames(t *testing.T) {
+	ctx := context.Background()
+	r := &TektonActionKubernetesResource{}
+	original := testPlanWithImage("bitnami/kubectl:1.30")
+	names, _ := plannedNames(ctx, original.metadataInput())
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	state.SetAttribute(ctx, path.Root("task_name"), names.TaskName)
+	state.SetAttribute(ctx, path.Root("step_action_name"), names.StepActionName)
+
+	plan := testPlanWithImage("bitnami/kubectl:1.30")
+	plan.Name = types.StringValue("renamed-action")
+	plan.TaskName = types.StringUnknown()
+	plan.StepActionName = types.StringUnknown()
+	if diags := stateNames(ctx, state, &plan.TaskName, &plan.StepActionName); diags.HasError() {
+		t.Fatalf("stateNames() diags = %v", diags)
+	}
+	stepAction, task, diags := r.planObjects(ctx, plan)
+	if diags.HasError() || task == nil {
+		t.Fatalf("planObjects() = %v, %v", task, diags)
+	}
+	if task.GetName() != names.TaskName || stepAction.GetName() != names.StepActionName {
+		t.Errorf("planned names = %s, %s, want %s, %s", task.GetName(), stepAction.GetName(), names.TaskName, names.StepActionName)
+	}
+	plannedStepAction, plannedTask, _ := actionManifests(stepAction, task)
+
+	// Update renders the objects with the names from state
+	metadata, _, _ := buildActionMetadata(ctx, plan.metadataInput(), nil)
+	stepAction, task, _ = r.renderObjects(ctx, plan, tekton.StepPolicy{}, tekton.CredentialStep{}, metadata)
+	appliedStepAction, appliedTask, _ := actionManifests(stepAction, task)
+	if !appliedTask.Equal(plannedTask) || !appliedStepAction.Equal(plannedStepAction) {
+		t.Errorf("manifests at apply differ from the plan:\n%s\nvs\n%s", appliedTask, plannedTask)
+	}
+}
+
+// TestAWSActionManifests_Deterministic renders an AWS action without a
+// session_name twice, as ModifyPlan and then Create do, and checks the
+// manifests match
+func TestAWSActionManifests_Deterministic(t *testing.T) {
+	ctx := context.Background()
+	assumeRoleTypes := map[string]attr.Type{
+		"role_arn":     types.StringType,
+		"external_id":  types.StringType,
+		"session_name": types.StringType,
+	}
+	r := &TektonActionAWSResource{providerData: &FacetsProviderModel{
+		AWS: types.ObjectValueMust(map[string]attr.Type{
+			"region":      types.StringType,
+			"assume_role": types.ObjectType{AttrTypes: assumeRoleTypes},
+		}, map[string]attr.Value{
+			"region": types.StringValue("us-east-1"),
+			"assume_role": types.ObjectValueMust(assumeRoleTypes, map[string]attr.Value{
+				"role_arn":     types.StringValue("arn:aws:iam::123456789012:role/deployer"),
+				"external_id":  types.StringNull(),
+				"session_name": types.StringNull(),
+			}),
+		}),
+	}}
+	k8sPlan := testPlanWithImage("amazon/aws-cli:2.15.0")
+	plan := TektonActionAWSResourceModel{
+		Name:               k8sPlan.Name,
+		Description:        k8sPlan.Description,
+		FacetsResourceName: k8sPlan.FacetsResourceName,
+		FacetsEnvironment:  k8sPlan.FacetsEnvironment,
+		FacetsResource:     k8sPlan.FacetsResource,
+		Namespace:          k8sPlan.Namespace,
+		Labels:             k8sPlan.Labels,
+		Annotations:        k8sPlan.Annotations,
+		Steps:              k8sPlan.Steps,
+		Params:             k8sPlan.Params,
+		CredentialStep:     k8sPlan.CredentialStep,
+		RBAC:               k8sPlan.RBAC,
+		ClusterID:          k8sPlan.ClusterID,
+	}
+
+	render := func() (stepActionManifest, taskManifest types.String) {
+		stepAction, task, diags := r.planObjects(ctx, plan)
+		if diags.HasError() || stepAction == nil {
+			t.Fatalf("planObjects() = %v, %v", stepAction, diags)
+		}
+		stepActionManifest, taskManifest, diags = actionManifests(stepAction, task)
+		if diags.HasError() {
+			t.Fatalf("actionManifests() diags = %v", diags)
+		}
+		return stepActionManifest, taskManifest
+	}
+	plannedStepAction, plannedTask := render()
+	appliedStepAction, appliedTask := render()
+	if !appliedStepAction.Equal(plannedStepAction) || !appliedTask.Equal(plannedTask) {
+		t.Errorf("manifests differ between renders:\n%s\nvs\n%s", appliedStepAction, plannedStepAction)
+	}
+	names, _ := plannedNames(ctx, plan.metadataInput())
+	if want := "role_session_name = " + tekton.DefaultSessionName(names.TaskName); !strings.Contains(plannedStepAction.ValueString(), want) {
+		t.Errorf("step_action_manifest does not contain %q:\n%s", want, plannedStepAction)
+	}
+}
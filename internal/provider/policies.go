@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/policy"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Action types exposed to policies as action_type
+const (
+	actionTypeKubernetes = "kubernetes"
+	actionTypeAWS        = "aws"
+)
+
+// ProviderPolicyModel is an element of the provider's policies list
+type ProviderPolicyModel struct {
+	Name       types.String `tfsdk:"name"`
+	Expression types.String `tfsdk:"expression"`
+	Message    types.String `tfsdk:"message"`
+	Severity   types.String `tfsdk:"severity"`
+}
+
+// hasPolicies reports whether the provider configures any policies, known or not.
+// Safe to call on a nil provider model.
+func (m *FacetsProviderModel) hasPolicies() bool {
+	return m != nil && !m.Policies.IsNull() && (m.Policies.IsUnknown() || len(m.Policies.Elements()) > 0)
+}
+
+// policyRules returns the provider's policies. known is false when the list or
+// any of its values is still unknown.
+func (m *FacetsProviderModel) policyRules(ctx context.Context) (rules []policy.Rule, known bool, diags diag.Diagnostics) {
+	if !m.hasPolicies() {
+		return nil, true, diags
+	}
+	if !valuesKnown(ctx, m.Policies) {
+		return nil, false, diags
+	}
+
+	var policies []ProviderPolicyModel
+	diags.Append(m.Policies.ElementsAs(ctx, &policies, false)...)
+	if diags.HasError() {
+		return nil, false, diags
+	}
+	for _, p := range policies {
+		severity := policy.Severity(p.Severity.ValueString())
+		if severity == "" {
+			severity = policy.SeverityError
+		}
+		rules = append(rules, policy.Rule{
+			Name:       p.Name.ValueString(),
+			Expression: p.Expression.ValueString(),
+			Message:    p.Message.ValueString(),
+			Severity:   severity,
+		})
+	}
+	return rules, true, diags
+}
+
+// evaluatePolicies evaluates the provider's policies against an action's
+// rendered Task and StepAction. Violated error policies become errors and
+// violated warning policies become warnings.
+//
+// Policies are evaluated during plan and again during apply, in case they
+// were unknown during plan. At apply (atApply = true) only errors are
+// reported, so warnings are not shown twice, and unknown policies are an error.
+func evaluatePolicies(ctx context.Context, providerData *FacetsProviderModel, actionType string, stepAction, task *unstructured.Unstructured, atApply bool) diag.Diagnostics {
+	rules, known, diags := providerData.policyRules(ctx)
+	if diags.HasError() || (known && len(rules) == 0) {
+		return diags
+	}
+	if !known {
+		if atApply {
+			diags.AddError(
+				"Unable to Evaluate Policies",
+				"The provider's policies are still unknown during apply.",
+			)
+		}
+		return diags
+	}
+
+	evaluator, err := policy.Compile(rules)
+	if err != nil {
+		diags.AddError("Invalid Policy", err.Error())
+		return diags
+	}
+	violations, err := evaluator.Evaluate(policy.Input{
+		Task:        task.Object,
+		StepAction:  stepAction.Object,
+		Labels:      task.GetLabels(),
+		Annotations: task.GetAnnotations(),
+		ActionType:  actionType,
+	})
+	if err != nil {
+		diags.AddError("Unable to Evaluate Policies", err.Error())
+		return diags
+	}
+
+	for _, v := range violations {
+		summary := fmt.Sprintf("Policy Violation: %s", v.Rule.Name)
+		switch {
+		case v.Rule.Severity == policy.SeverityWarning && !atApply:
+			diags.AddWarning(summary, v.Message())
+		case v.Rule.Severity != policy.SeverityWarning:
+			diags.AddError(summary, v.Message())
+		}
+	}
+	return diags
+}
+
+// stateNames reads task_name and step_action_name from prior state into the
+// given values. Update keeps both names, so a plan for an existing action must
+// render its objects with them rather than with freshly generated ones.
+func stateNames(ctx context.Context, state tfsdk.State, taskName, stepActionName *types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if state.Raw.IsNull() {
+		return diags
+	}
+	diags.Append(state.GetAttribute(ctx, path.Root("task_name"), taskName)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("step_action_name"), stepActionName)...)
+	return diags
+}
+
+// plannedNames returns the Task and StepAction names for a plan, generated the
+// same way as in Create. The inputs must be known.
+func plannedNames(ctx context.Context, in actionMetadataInput) (*tekton.ResourceNames, diag.Diagnostics) {
+	var facetsEnv tekton.FacetsEnvironmentModel
+	diags := in.FacetsEnvironment.As(ctx, &facetsEnv, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, diags
+	}
+	return tekton.GenerateNames(
+		in.FacetsResourceName.ValueString(),
+		facetsEnv.UniqueName.ValueString(),
+		in.Name.ValueString(),
+	), diags
+}
+
+// valuesKnown reports whether every value, including nested elements and
+// attributes, is known
+func valuesKnown(ctx context.Context, values ...attr.Value) bool {
+	for _, v := range values {
+		tfValue, err := v.ToTerraformValue(ctx)
+		if err != nil || !tfValue.IsFullyKnown() {
+			return false
+		}
+	}
+	return true
+}
+
+var _ validator.String = policyExpressionValidator{}
+
+// policyExpressionValidator compiles a policy expression so syntax and type
+// errors fail terraform validate
+type policyExpressionValidator struct{}
+
+func (v policyExpressionValidator) Description(ctx context.Context) string {
+	return "must be a valid CEL expression returning a bool"
+}
+
+func (v policyExpressionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v policyExpressionValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := policy.CheckExpression(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Policy Expression", err.Error())
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testPolicies(policies ...ProviderPolicyModel) types.List {
+	attrTypes := map[string]attr.Type{
+		"name":       types.StringType,
+		"expression": types.StringType,
+		"message":    types.StringType,
+		"severity":   types.StringType,
+	}
+	elems := make([]attr.Value, 0, len(policies))
+	for _, p := range policies {
+		elems = append(elems, types.ObjectValueMust(attrTypes, map[string]attr.Value{
+			"name":       p.Name,
+			"expression": p.Expression,
+			"message":    p.Message,
+			"severity":   p.Severity,
+		}))
+	}
+	return types.ListValueMust(types.ObjectType{AttrTypes: attrTypes}, elems)
+}
+
+func testPolicy(name, expression, severity string) ProviderPolicyModel {
+	s := types.StringNull()
+	if severity != "" {
+		s = types.StringValue(severity)
+	}
+	return ProviderPolicyModel{
+		Name:       types.StringValue(name),
+		Expression: types.StringValue(expression),
+		Message:    types.StringNull(),
+		Severity:   s,
+	}
+}
+
+// testPlanWithImage returns a Kubernetes action plan with a single step using image
+func testPlanWithImage(image string) TektonActionKubernetesResourceModel {
+	in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+	step := types.ObjectValueMust(tekton.StepObjectType.AttrTypes, map[string]attr.Value{
+		"name":          types.StringValue("deploy"),
+		"image":         types.StringValue(image),
+		"script":        types.StringValue("kubectl rollout restart deployment/api"),
+		"resources":     types.ObjectNull(tekton.ComputeResourcesObjectType.AttrTypes),
+		"env":           types.ListNull(tekton.EnvVarObjectType),
+		"sensitive_env": types.MapNull(types.StringType),
+	})
+	return TektonActionKubernetesResourceModel{
+		Name:               in.Name,
+		Description:        types.StringNull(),
+		FacetsResourceName: in.FacetsResourceName,
+		FacetsEnvironment:  in.FacetsEnvironment,
+		FacetsResource:     in.FacetsResource,
+		Namespace:          types.StringValue(k8sReadTestNamespace),
+		Labels:             types.MapNull(types.StringType),
+		Annotations:        types.MapNull(types.StringType),
+		Steps:              types.ListValueMust(tekton.StepObjectType, []attr.Value{step}),
+		Params:             types.ListNull(tekton.ParamObjectType),
+		CredentialStep:     types.ObjectNull(credentialStepAttrTypes),
+		RBAC:               types.ObjectNull(actionRBACAttrTypes),
+		KubeconfigDelivery: types.StringNull(),
+		ClusterID:          in.ClusterID,
+	}
+}
+
+func summaries(diags diag.Diagnostics) string {
+	var s []string
+	for _, d := range diags {
+		s = append(s, d.Severity().String()+": "+d.Summary())
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	providerData := &FacetsProviderModel{Policies: testPolicies(
+		testPolicy("kind-is-task", `task.kind == "Task"`, ""),
+		testPolicy("no-latest", `task.spec.steps.all(s, !s.image.endsWith(":latest"))`, "error"),
+		testPolicy("production-only", `labels["environment_unique_name"] == "production"`, "warning"),
+	)}
+	task := testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, map[string]string{"environment_unique_name": "staging"})
+	task.Object["spec"] = map[string]interface{}{
+		"steps": []interface{}{map[string]interface{}{"name": "deploy", "image": "alpine:latest"}},
+	}
+	stepAction := testfake.StepAction(k8sReadTestNamespace, "setup-credentials-1", nil)
+
+	diags := evaluatePolicies(context.Background(), providerData, actionTypeKubernetes, stepAction, task, false)
+	if got, want := summaries(diags), "Error: Policy Violation: no-latest, Warning: Policy Violation: production-only"; got != want {
+		t.Errorf("plan diagnostics = %q, want %q", got, want)
+	}
+
+	diags = evaluatePolicies(context.Background(), providerData, actionTypeKubernetes, stepAction, task, true)
+	if got, want := summaries(diags), "Error: Policy Violation: no-latest"; got != want {
+		t.Errorf("apply diagnostics = %q, want %q", got, want)
+	}
+}
+
+func TestEvaluatePolicies_Unknown(t *testing.T) {
+	providerData := &FacetsProviderModel{Policies: types.ListUnknown(types.ObjectType{})}
+	task := testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, nil)
+	stepAction := testfake.StepAction(k8sReadTestNamespace, "setup-credentials-1", nil)
+
+	if diags := evaluatePolicies(context.Background(), providerData, actionTypeKubernetes, stepAction, task, false); diags.HasError() {
+		t.Errorf("unknown policies must be skipped during plan, got %v", diags)
+	}
+	if diags := evaluatePolicies(context.Background(), providerData, actionTypeKubernetes, stepAction, task, true); !diags.HasError() {
+		t.Error("expected unknown policies to fail during apply")
+	}
+}
+
+func TestK8sPlanPolicies(t *testing.T) {
+	r := &TektonActionKubernetesResource{providerData: &FacetsProviderModel{Policies: testPolicies(
+		testPolicy("no-latest", `task.spec.steps.all(s, !has(s.image) || !s.image.endsWith(":latest"))`, ""),
+		testPolicy("credentials-step-first", `task.spec.steps[0].ref.name == step_action.metadata.name`, ""),
+		testPolicy("kubernetes-only", `action_type == "kubernetes"`, ""),
+	)}}
+
+	planPolicies := func(plan TektonActionKubernetesResourceModel) diag.Diagnostics {
+		stepAction, task, diags := r.planObjects(context.Background(), plan)
+		if task != nil {
+			diags.Append(evaluatePolicies(context.Background(), r.providerData, actionTypeKubernetes, stepAction, task, false)...)
+		}
+		return diags
+	}
+
+	diags := planPolicies(testPlanWithImage("bitnami/kubectl:latest"))
+	if got, want := summaries(diags), "Error: Policy Violation: no-latest"; got != want {
+		t.Errorf("diagnostics = %q, want %q", got, want)
+	}
+
+	if diags := planPolicies(testPlanWithImage("bitnami/kubectl:1.30")); diags.HasError() {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+
+	plan := testPlanWithImage("bitnami/kubectl:latest")
+	plan.Steps = types.ListUnknown(tekton.StepObjectType)
+	if _, task, diags := r.planObjects(context.Background(), plan); task != nil || diags.HasError() {
+		t.Errorf("objects must not be rendered while steps are unknown, got %v", diags)
+	}
+}
+
+// TestK8sPlanPolicies_ReadmeExamples checks the README's example policies
+// against a rendered action, whose setup-credentials step has no image
+func TestK8sPlanPolicies_ReadmeExamples(t *testing.T) {
+	r := &TektonActionKubernetesResource{providerData: &FacetsProviderModel{Policies: testPolicies(
+		testPolicy("no-latest-in-production", `labels["environment_unique_name"] != "production" ||
+task.spec.steps.all(s, !has(s.image) || !s.image.endsWith(":latest"))`, ""),
+		testPolicy("memory-limits", `task.spec.steps.all(s, !has(s.image) || (has(s.computeResources) && has(s.computeResources.limits) && 'memory' in s.computeResources.limits))`, "warning"),
+	)}}
+	planPolicies := func(plan TektonActionKubernetesResourceModel) diag.Diagnostics {
+		stepAction, task, diags := r.planObjects(context.Background(), plan)
+		if task != nil {
+			diags.Append(evaluatePolicies(context.Background(), r.providerData, actionTypeKubernetes, stepAction, task, false)...)
+		}
+		return diags
+	}
+
+	plan := testPlanWithImage("bitnami/kubectl:1.30")
+	if got, want := summaries(planPolicies(plan)), "Warning: Policy Violation: memory-limits"; got != want {
+		t.Errorf("diagnostics = %q, want %q", got, want)
+	}
+
+	steps := plan.Steps.Elements()
+	step := steps[0].(types.Object).Attributes()
+	step["resources"] = types.ObjectValueMust(tekton.ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+		"requests": types.MapNull(types.StringType),
+		"limits":   testStringMap(map[string]string{"memory": "256Mi"}),
+	})
+	steps[0] = types.ObjectValueMust(tekton.StepObjectType.AttrTypes, step)
+	plan.Steps = types.ListValueMust(tekton.StepObjectType, steps)
+	if diags := planPolicies(plan); len(diags) > 0 {
+		t.Errorf("unexpected diagnostics: %v", summaries(diags))
+	}
+}
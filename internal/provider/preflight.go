@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// tektonNamespace returns the provider's tekton_namespace, falling back to
+// tektonPipelinesNamespace. Safe to call on a nil provider model.
+func (m *FacetsProviderModel) tektonNamespace() string {
+	if m != nil && m.TektonNamespace.ValueString() != "" {
+		return m.TektonNamespace.ValueString()
+	}
+	return tektonPipelinesNamespace
+}
+
+// runnerServiceAccount returns the provider's runner_service_account, or ""
+// when it is not configured. Safe to call on a nil provider model.
+func (m *FacetsProviderModel) runnerServiceAccount() string {
+	if m == nil {
+		return ""
+	}
+	return m.RunnerServiceAccount.ValueString()
+}
+
+// preflightAction checks the cluster prerequisites of an action before Create
+// writes anything. The runner ServiceAccount is only checked when the
+// provider's runner_service_account names it, and for actions without rbac,
+// which create their own.
+func preflightAction(ctx context.Context, providerData *FacetsProviderModel, discoveryFactory func() (discovery.DiscoveryInterface, error), client dynamic.Interface, namespace string, rbac types.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if discoveryFactory == nil {
+		discoveryFactory = k8s.GetDiscoveryClient
+	}
+	disc, err := discoveryFactory()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Kubernetes Discovery Client",
+			err.Error(),
+		)
+		return diags
+	}
+
+	target := tekton.PreflightTarget{
+		Namespace:       namespace,
+		ServiceAccount:  providerData.runnerServiceAccount(),
+		TektonNamespace: providerData.tektonNamespace(),
+	}
+	if enabled, _, _ := rbacScope(ctx, rbac); enabled {
+		target.ServiceAccount = ""
+	}
+	return tekton.Preflight(ctx, disc, client, target)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestPreflightAction(t *testing.T) {
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: tekton.GroupVersion,
+		APIResources: []metav1.APIResource{{Name: "tasks"}, {Name: "stepactions"}},
+	}}}}
+	factory := func() (discovery.DiscoveryInterface, error) { return disc, nil }
+	client := testfake.NewClient(
+		testfake.Namespace(k8sReadTestNamespace, "uid"),
+		testfake.ConfigMap(tektonPipelinesNamespace, tekton.FeatureFlagsConfigMap, map[string]string{tekton.FeatureFlagEnableStepActions: "true"}),
+	)
+
+	providerData := &FacetsProviderModel{RunnerServiceAccount: types.StringValue(tekton.DefaultRunnerServiceAccount)}
+
+	diags := preflightAction(context.Background(), providerData, factory, client, k8sReadTestNamespace, types.ObjectNull(actionRBACAttrTypes))
+	if len(diags) != 1 || diags[0].Summary() != "Runner ServiceAccount not found" {
+		t.Errorf("preflightAction() without rbac = %v, want the missing runner ServiceAccount", diags)
+	}
+
+	// An action with rbac runs as its own ServiceAccount, created with it
+	if diags := preflightAction(context.Background(), providerData, factory, client, k8sReadTestNamespace, testRBAC(false)); len(diags) != 0 {
+		t.Errorf("preflightAction() with rbac = %v, want no diagnostics", diags)
+	}
+
+	// Without runner_service_account the runner ServiceAccount is unknown
+	if diags := preflightAction(context.Background(), nil, factory, client, k8sReadTestNamespace, types.ObjectNull(actionRBACAttrTypes)); len(diags) != 0 {
+		t.Errorf("preflightAction() without runner_service_account = %v, want no diagnostics", diags)
+	}
+}
+
+// TestPreflightAction_ProviderNamespaces checks the runner ServiceAccount and
+// the feature flags are looked up where the provider configuration says
+func TestPreflightAction_ProviderNamespaces(t *testing.T) {
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: tekton.GroupVersion,
+		APIResources: []metav1.APIResource{{Name: "tasks"}, {Name: "stepactions"}},
+	}}}}
+	factory := func() (discovery.DiscoveryInterface, error) { return disc, nil }
+	client := testfake.NewClient(
+		testfake.Namespace(k8sReadTestNamespace, "uid"),
+		tekton.BuildRunnerServiceAccount("facets-runner", k8sReadTestNamespace, nil, nil),
+		testfake.ConfigMap("openshift-pipelines", tekton.FeatureFlagsConfigMap, map[string]string{tekton.FeatureFlagEnableStepActions: "false"}),
+	)
+	providerData := &FacetsProviderModel{
+		TektonNamespace:      types.StringValue("openshift-pipelines"),
+		RunnerServiceAccount: types.StringValue("facets-runner"),
+	}
+
+	diags := preflightAction(context.Background(), providerData, factory, client, k8sReadTestNamespace, types.ObjectNull(actionRBACAttrTypes))
+	if len(diags) != 1 || diags[0].Summary() != "Tekton StepActions disabled" {
+		t.Errorf("preflightAction() = %v, want only the disabled StepActions in openshift-pipelines", diags)
+	}
+}
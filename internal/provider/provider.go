@@ -2,22 +2,65 @@ package provider
 
 import (
 	"context"
+	"regexp"
+	"strings"
 
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/policy"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ provider.Provider = &FacetsProvider{}
+var (
+	_ provider.Provider                     = &FacetsProvider{}
+	_ provider.ProviderWithConfigValidators = &FacetsProvider{}
+	_ provider.ProviderWithFunctions        = &FacetsProvider{}
+)
 
 type FacetsProvider struct {
 	version string
 }
 
 type FacetsProviderModel struct {
-	AWS types.Object `tfsdk:"aws"`
+	AWS             types.Object `tfsdk:"aws"`
+	Kubernetes      types.Object `tfsdk:"kubernetes"`
+	ClusterID       types.String `tfsdk:"cluster_id"`
+	DeriveClusterID types.Bool   `tfsdk:"derive_cluster_id"`
+	OutputMode      types.String `tfsdk:"output_mode"`
+	OutputDir       types.String `tfsdk:"output_dir"`
+
+	DefaultLabels      types.Map    `tfsdk:"default_labels"`
+	DefaultAnnotations types.Map    `tfsdk:"default_annotations"`
+	DefaultNamespace   types.String `tfsdk:"default_namespace"`
+
+	TektonNamespace      types.String `tfsdk:"tekton_namespace"`
+	RunnerServiceAccount types.String `tfsdk:"runner_service_account"`
+
+	DefaultStepResources   types.Object `tfsdk:"default_step_resources"`
+	MaxStepResources       types.Object `tfsdk:"max_step_resources"`
+	AllowedImageRegistries types.List   `tfsdk:"allowed_image_registries"`
+	Policies               types.List   `tfsdk:"policies"`
+	CredentialStep         types.Object `tfsdk:"credential_step"`
+}
+
+// FacetsProviderData is passed to resources and data sources as ProviderData.
+// It carries the provider configuration and the client pool shared by every
+// resource of this provider instance.
+type FacetsProviderData struct {
+	Model   *FacetsProviderModel
+	Clients *k8s.ClientPool
 }
 
 type ProviderAWSConfig struct {
@@ -70,8 +113,8 @@ func (p *FacetsProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 								Optional: true,
 							},
 							"session_name": schema.StringAttribute{
-								Description: "Session name to use when assuming the role. If not provided, a random " +
-									"session name will be generated. This appears in CloudTrail logs and can be used " +
+								Description: "Session name to use when assuming the role. If not provided, each action " +
+									"uses terraform-<task_name>. This appears in CloudTrail logs and can be used " +
 									"for tracking and auditing purposes.",
 								Optional: true,
 							},
@@ -79,10 +122,319 @@ func (p *FacetsProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 					},
 				},
 			},
+			"cluster_id": schema.StringAttribute{
+				Description: "Cluster identifier recorded in the cluster_id label and facets.cloud/cluster-id annotation " +
+					"of every Task and StepAction. Takes precedence over derive_cluster_id and the CLUSTER_ID " +
+					"environment variable. Defaults to \"na\" when nothing is configured.",
+				Optional: true,
+			},
+			"derive_cluster_id": schema.BoolAttribute{
+				Description: "Derive the cluster identifier from the UID of the kube-system namespace when cluster_id " +
+					"is not set. The UID is stable for the lifetime of the cluster, so every workspace targeting " +
+					"the same cluster records the same cluster_id. Takes precedence over the CLUSTER_ID environment variable.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("cluster_id")),
+				},
+			},
+			"output_mode": schema.StringAttribute{
+				Description: "How resources reach the cluster. \"apply\" (the default) applies them through the Kubernetes API. " +
+					"\"render\" writes them as YAML files to output_dir instead, for clusters whose objects are delivered " +
+					"by GitOps (e.g. Argo CD): creating, updating and destroying an action writes, updates and removes its files, " +
+					"and the provider never connects to a cluster. Render mode does not support sensitive_env, " +
+					"facets_tekton_runtime, facets_tekton_capabilities or derive_cluster_id.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(outputModeApply, outputModeRender),
+				},
+			},
+			"output_dir": schema.StringAttribute{
+				Description: "Directory the YAML files are written to when output_mode is \"render\", one file per object at " +
+					"<namespace>/<resource>/<name>.yaml, e.g. tekton-pipelines/tasks/<task_name>.yaml. Relative paths are " +
+					"resolved against the directory Terraform runs in. Required in render mode.",
+				Optional: true,
+			},
+			"default_labels": schema.MapAttribute{
+				Description: "Labels added to the Task and StepAction of every action managed by this provider, " +
+					"similar to the AWS provider's default_tags. Resource labels override these, and " +
+					"auto-generated labels override both. The merged result is shown in each resource's effective_labels.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"default_annotations": schema.MapAttribute{
+				Description: "Annotations added to the Task and StepAction of every action managed by this provider. " +
+					"Resource annotations override these, and the facets.cloud/* annotations override both. " +
+					"The merged result is shown in each resource's effective_annotations.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"default_namespace": schema.StringAttribute{
+				Description: "Namespace for actions that do not set namespace. Defaults to tekton-pipelines. " +
+					"Changing it replaces the actions that rely on it.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`),
+						"must be a valid Kubernetes namespace name (lowercase alphanumeric and hyphens, cannot start or end with hyphen)",
+					),
+					stringvalidator.LengthAtMost(63),
+				},
+			},
+			"tekton_namespace": schema.StringAttribute{
+				Description: "Namespace Tekton Pipelines is installed in, whose feature-flags ConfigMap the preflight checks " +
+					"read before an action is created. Defaults to tekton-pipelines. Set it to the tekton_namespace of " +
+					"facets_tekton_runtime when that differs.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`),
+						"must be a valid Kubernetes namespace name (lowercase alphanumeric and hyphens, cannot start or end with hyphen)",
+					),
+					stringvalidator.LengthAtMost(63),
+				},
+			},
+			"runner_service_account": schema.StringAttribute{
+				Description: "ServiceAccount the Facets runner starts TaskRuns as, e.g. the service_account_name of " +
+					"facets_tekton_runtime. When set, the preflight checks confirm it exists in an action's namespace " +
+					"before the action is created. When unset, the check is skipped.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.LengthAtMost(253),
+				},
+			},
+			"default_step_resources": schema.SingleNestedAttribute{
+				Description: "Compute resources applied to every step that does not set resources, so Tasks " +
+					"are not scheduled without requests and limits.",
+				Optional:   true,
+				Attributes: stepResourcesPolicyAttributes(),
+			},
+			"max_step_resources": schema.SingleNestedAttribute{
+				Description: "Upper bounds for step compute resources. Each entry of requests caps the same " +
+					"resource in every step's requests, and each entry of limits caps it in every step's limits. " +
+					"Steps that exceed a maximum fail during plan.",
+				Optional:   true,
+				Attributes: stepResourcesPolicyAttributes(),
+			},
+			"allowed_image_registries": schema.ListAttribute{
+				Description: "Registries step images may be pulled from, e.g. ghcr.io or 123456789012.dkr.ecr.us-east-1.amazonaws.com. " +
+					"An entry with a path, e.g. ghcr.io/facets-cloud, allows only repositories below it. Images " +
+					"without a registry host are treated as docker.io. Checked during plan; when unset, every image is allowed.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"policies": schema.ListNestedAttribute{
+				Description: "CEL policies evaluated during plan against the rendered Task and StepAction of every action. " +
+					"Expressions must return true for an action to pass and can use the variables task, step_action " +
+					"(the rendered objects), labels and annotations (the Task's metadata, including the Facets labels) " +
+					"and action_type (\"kubernetes\" or \"aws\").",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the policy, shown in diagnostics",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"expression": schema.StringAttribute{
+							Description: "CEL expression that must evaluate to true, e.g. " +
+								"`task.spec.steps.all(s, !has(s.image) || !s.image.endsWith(':latest'))`. " +
+								"The injected setup-credentials step references the StepAction and has no image.",
+							Required: true,
+							Validators: []validator.String{
+								policyExpressionValidator{},
+							},
+						},
+						"message": schema.StringAttribute{
+							Description: "Message shown when the policy is violated. Defaults to the policy name and expression.",
+							Optional:    true,
+						},
+						"severity": schema.StringAttribute{
+							Description: "error (default) fails the plan; warning only reports the violation",
+							Optional:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(string(policy.SeverityError), string(policy.SeverityWarning)),
+							},
+						},
+					},
+				},
+			},
+			"credential_step": schema.SingleNestedAttribute{
+				Description: "Image and pull policy of the setup-credentials step that every action runs first, " +
+					"e.g. to pull it from an internal registry. Defaults to " + tekton.DefaultCredentialStepImage + ". " +
+					"An override image must provide " + strings.Join(tekton.KubernetesCredentialStepTools, ", ") +
+					" for facets_tekton_action_kubernetes and " + strings.Join(tekton.AWSCredentialStepTools, ", ") +
+					" for facets_tekton_action_aws on its PATH; the step checks for them first and fails with an " +
+					"error naming the missing tool. Resources can override it with their own credential_step.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"image": schema.StringAttribute{
+						Description: "Image of the credential StepAction. Used as is; registry_mirror is not applied.",
+						Optional:    true,
+						Validators:  credentialStepImageValidators(),
+					},
+					"image_pull_policy": schema.StringAttribute{
+						Description: "Pull policy of the setup-credentials step: Always, IfNotPresent or Never. " +
+							"Defaults to the Kubernetes default for the image's tag.",
+						Optional:   true,
+						Validators: imagePullPolicyValidators(),
+					},
+					"registry_mirror": schema.StringAttribute{
+						Description: "Registry, with an optional path, that mirrors Docker Hub, e.g. harbor.example.com/dockerhub. " +
+							"The default image is pulled as <registry_mirror>/facetscloud/actions-base-image:<tag>. " +
+							"Ignored when image is set.",
+						Optional:   true,
+						Validators: registryMirrorValidators(),
+					},
+				},
+			},
+			"kubernetes": schema.SingleNestedAttribute{
+				Description: "Kubernetes connection configuration used to manage Tekton Tasks and StepActions. " +
+					"When omitted, the kubeconfig from the KUBECONFIG environment variable or ~/.kube/config is used. " +
+					"In-cluster (service account) authentication is only used when in_cluster = true.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"in_cluster": schema.BoolAttribute{
+						Description: "Use the pod's service account token and the in-cluster API endpoint. " +
+							"Cannot be combined with any other setting in this block.",
+						Optional: true,
+					},
+					"host": schema.StringAttribute{
+						Description: "The hostname (in form of URI) of the Kubernetes API server.",
+						Optional:    true,
+					},
+					"token": schema.StringAttribute{
+						Description: "Bearer token used to authenticate to the Kubernetes API server.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"cluster_ca_certificate": schema.StringAttribute{
+						Description: "PEM-encoded root certificates bundle for TLS authentication.",
+						Optional:    true,
+					},
+					"client_certificate": schema.StringAttribute{
+						Description: "PEM-encoded client certificate for TLS authentication.",
+						Optional:    true,
+					},
+					"client_key": schema.StringAttribute{
+						Description: "PEM-encoded client certificate key for TLS authentication.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"insecure": schema.BoolAttribute{
+						Description: "Skip verification of the server's TLS certificate. Not recommended outside testing.",
+						Optional:    true,
+					},
+					"tls_server_name": schema.StringAttribute{
+						Description: "Server name passed to the server for SNI and used in the client to check server certificates against.",
+						Optional:    true,
+					},
+					"proxy_url": schema.StringAttribute{
+						Description: "URL of the proxy to use for requests to the Kubernetes API server.",
+						Optional:    true,
+					},
+					"config_path": schema.StringAttribute{
+						Description: "Path to the kubeconfig file. Conflicts with config_paths.",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("config_paths")),
+						},
+					},
+					"config_paths": schema.ListAttribute{
+						Description: "List of kubeconfig files, merged in order like the KUBECONFIG environment variable. " +
+							"Conflicts with config_path.",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"config_context": schema.StringAttribute{
+						Description: "Context to select from the kubeconfig. Defaults to the kubeconfig's current-context.",
+						Optional:    true,
+					},
+					"config_context_auth_info": schema.StringAttribute{
+						Description: "Overrides the user of the selected kubeconfig context.",
+						Optional:    true,
+					},
+					"config_context_cluster": schema.StringAttribute{
+						Description: "Overrides the cluster of the selected kubeconfig context.",
+						Optional:    true,
+					},
+					"qps": schema.Float64Attribute{
+						Description: "Maximum sustained queries per second to the Kubernetes API server, shared by all " +
+							"resources using this connection. Defaults to 20.",
+						Optional: true,
+						Validators: []validator.Float64{
+							float64validator.AtLeast(1),
+						},
+					},
+					"burst": schema.Int64Attribute{
+						Description: "Maximum burst of queries to the Kubernetes API server above qps. Defaults to 40.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"exec": schema.SingleNestedAttribute{
+						Description: "Credential plugin used to obtain a token, e.g. `aws eks get-token`.",
+						Optional:    true,
+						Attributes: map[string]schema.Attribute{
+							"api_version": schema.StringAttribute{
+								Description: "API version of the ExecCredential (e.g., client.authentication.k8s.io/v1beta1).",
+								Required:    true,
+							},
+							"command": schema.StringAttribute{
+								Description: "Command to execute.",
+								Required:    true,
+							},
+							"args": schema.ListAttribute{
+								Description: "Arguments passed to the command.",
+								Optional:    true,
+								ElementType: types.StringType,
+							},
+							"env": schema.MapAttribute{
+								Description: "Environment variables set when executing the command.",
+								Optional:    true,
+								ElementType: types.StringType,
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// stepResourcesPolicyAttributes returns the requests/limits attributes shared by
+// default_step_resources and max_step_resources
+func stepResourcesPolicyAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"requests": schema.MapAttribute{
+			Description: "Compute resource requests (e.g., cpu, memory)",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"limits": schema.MapAttribute{
+			Description: "Compute resource limits (e.g., cpu, memory)",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+	}
+}
+
+// ConfigValidators checks the step resource policy quantities and the output
+// mode settings during terraform validate
+func (p *FacetsProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		stepResourcesPolicyValidator{},
+		outputModeValidator{},
+	}
+}
+
 func (p *FacetsProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var config FacetsProviderModel
 
@@ -93,19 +445,40 @@ func (p *FacetsProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	}
 
 	// Store provider data for resource access
-	// AWS config validation happens in the resource's Configure() method
-	resp.ResourceData = &config
+	// AWS and Kubernetes config validation happens lazily during CRUD operations
+	// so terraform validate never needs cluster access. The client pool is
+	// likewise empty until the first CRUD call needs a client.
+	providerData := &FacetsProviderData{
+		Model:   &config,
+		Clients: k8s.NewClientPool(),
+	}
+	resp.ResourceData = providerData
+	resp.DataSourceData = providerData
 }
 
 func (p *FacetsProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewTektonActionKubernetesResource,
 		NewTektonActionAWSResource,
+		NewTektonRuntimeResource,
 	}
 }
 
 func (p *FacetsProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewTektonCapabilitiesDataSource,
+		NewTektonActionDataSource,
+		NewTektonActionsDataSource,
+		NewTektonActionRunsDataSource,
+	}
+}
+
+func (p *FacetsProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewTaskNameFunction,
+		NewStepActionNameFunction,
+		NewActionIDFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// configureProvider runs the provider's Configure with an empty configuration
+func configureProvider(t *testing.T) *provider.ConfigureResponse {
+	t.Helper()
+	ctx := context.Background()
+	p := New("test")()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	rawType := schemaResp.Schema.Type().TerraformType(ctx)
+	fields := map[string]tftypes.Value{}
+	for name, ft := range rawType.(tftypes.Object).AttributeTypes {
+		fields[name] = tftypes.NewValue(ft, nil)
+	}
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(rawType, fields)}
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure() diags = %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+// TestProviderConfigure_DataSourceData checks data sources get the provider
+// configuration, so they connect with the provider's kubernetes block
+func TestProviderConfigure_DataSourceData(t *testing.T) {
+	resp := configureProvider(t)
+	if _, ok := resp.DataSourceData.(*FacetsProviderData); !ok {
+		t.Fatalf("DataSourceData = %T, want *FacetsProviderData", resp.DataSourceData)
+	}
+
+	d := &TektonCapabilitiesDataSource{}
+	configureResp := &datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: resp.DataSourceData}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure() diags = %v", configureResp.Diagnostics)
+	}
+	if d.clientFactory == nil || d.discoveryFactory == nil {
+		t.Error("expected the data source to use the provider's client factories")
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// ActionRBACModel is a resource's rbac block
+type ActionRBACModel struct {
+	ClusterScoped             types.Bool `tfsdk:"cluster_scoped"`
+	Rules                     types.List `tfsdk:"rules"`
+	ServiceAccountAnnotations types.Map  `tfsdk:"service_account_annotations"`
+}
+
+// RBACRuleModel is a rule of a resource's rbac block
+type RBACRuleModel struct {
+	APIGroups     types.List `tfsdk:"api_groups"`
+	Resources     types.List `tfsdk:"resources"`
+	ResourceNames types.List `tfsdk:"resource_names"`
+	Verbs         types.List `tfsdk:"verbs"`
+}
+
+// rbacRuleObjectType is the object type of a rule of a resource's rbac block
+var rbacRuleObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"api_groups":     types.ListType{ElemType: types.StringType},
+	"resources":      types.ListType{ElemType: types.StringType},
+	"resource_names": types.ListType{ElemType: types.StringType},
+	"verbs":          types.ListType{ElemType: types.StringType},
+}}
+
+// actionRBACAttrTypes are the attribute types of a resource's rbac block
+var actionRBACAttrTypes = map[string]attr.Type{
+	"cluster_scoped":              types.BoolType,
+	"rules":                       types.ListType{ElemType: rbacRuleObjectType},
+	"service_account_annotations": types.MapType{ElemType: types.StringType},
+}
+
+// rbacResourceAttribute is the rbac block shared by the action resources
+func rbacResourceAttribute() schema.SingleNestedAttribute {
+	nonEmptyStrings := []validator.List{
+		listvalidator.SizeAtLeast(1),
+		listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+	}
+	return schema.SingleNestedAttribute{
+		Description: "Runs the action as its own ServiceAccount instead of the shared facets-workflows-sa. " +
+			"The provider creates the ServiceAccount, a Role (or ClusterRole) with rules and a binding between them, " +
+			"and records the ServiceAccount in the facets.cloud/service-account annotation for the Facets runner.",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"cluster_scoped": schema.BoolAttribute{
+				Description: "Create a ClusterRole and ClusterRoleBinding instead of a Role and RoleBinding in the action's namespace. Defaults to false.",
+				Optional:    true,
+			},
+			"service_account_annotations": schema.MapAttribute{
+				Description: "Extra annotations of the ServiceAccount, e.g. eks.amazonaws.com/role-arn for IRSA. " +
+					"The action's annotations take precedence.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"rules": schema.ListNestedAttribute{
+				Description: "Rules of the Role or ClusterRole",
+				Required:    true,
+				Validators:  []validator.List{listvalidator.SizeAtLeast(1)},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"api_groups": schema.ListAttribute{
+							Description: "API groups of the resources, e.g. \"\" for the core group or \"apps\"",
+							Required:    true,
+							ElementType: types.StringType,
+							Validators:  []validator.List{listvalidator.SizeAtLeast(1)},
+						},
+						"resources": schema.ListAttribute{
+							Description: "Resources the rule applies to, e.g. \"pods\" or \"deployments/scale\"",
+							Required:    true,
+							ElementType: types.StringType,
+							Validators:  nonEmptyStrings,
+						},
+						"resource_names": schema.ListAttribute{
+							Description: "Names of the resources the rule is restricted to. Applies to all names when omitted.",
+							Optional:    true,
+							ElementType: types.StringType,
+							Validators:  nonEmptyStrings,
+						},
+						"verbs": schema.ListAttribute{
+							Description: "Verbs the rule allows, e.g. \"get\", \"list\" or \"patch\"",
+							Required:    true,
+							ElementType: types.StringType,
+							Validators:  nonEmptyStrings,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// rbacScope reports whether an rbac block is set and whether it is cluster-scoped
+func rbacScope(ctx context.Context, rbac types.Object) (enabled, clusterScoped bool, diags diag.Diagnostics) {
+	if rbac.IsNull() || rbac.IsUnknown() {
+		return false, false, diags
+	}
+	var model ActionRBACModel
+	diags.Append(rbac.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	return !diags.HasError(), model.ClusterScoped.ValueBool(), diags
+}
+
+// rbacRefs returns the objects an rbac block generates, with only their names
+// set, or nil when the block is not set. Used to delete them.
+func rbacRefs(ctx context.Context, rbac types.Object, namespace, taskName string) []tekton.RBACObject {
+	enabled, clusterScoped, diags := rbacScope(ctx, rbac)
+	if !enabled || diags.HasError() {
+		return nil
+	}
+	return tekton.RBACRefs(tekton.ServiceAccountName(taskName), namespace, clusterScoped)
+}
+
+// buildRBACObjects returns the ServiceAccount, Role or ClusterRole, and
+// binding of an rbac block, or nil when the block is not set
+func buildRBACObjects(ctx context.Context, rbac types.Object, namespace, taskName string, metadata *tekton.ResourceMetadata) ([]tekton.RBACObject, diag.Diagnostics) {
+	enabled, clusterScoped, diags := rbacScope(ctx, rbac)
+	if !enabled || diags.HasError() {
+		return nil, diags
+	}
+	var model ActionRBACModel
+	diags.Append(rbac.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	var ruleModels []RBACRuleModel
+	diags.Append(model.Rules.ElementsAs(ctx, &ruleModels, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	rules := make([]tekton.PolicyRule, 0, len(ruleModels))
+	for _, m := range ruleModels {
+		var rule tekton.PolicyRule
+		diags.Append(m.APIGroups.ElementsAs(ctx, &rule.APIGroups, false)...)
+		diags.Append(m.Resources.ElementsAs(ctx, &rule.Resources, false)...)
+		diags.Append(m.Verbs.ElementsAs(ctx, &rule.Verbs, false)...)
+		if !m.ResourceNames.IsNull() {
+			diags.Append(m.ResourceNames.ElementsAs(ctx, &rule.ResourceNames, false)...)
+		}
+		rules = append(rules, rule)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	objects := tekton.BuildRBACObjects(
+		tekton.ServiceAccountName(taskName),
+		namespace,
+		metadata.LabelsAsInterface(),
+		metadata.AnnotationsAsInterface(),
+		clusterScoped,
+		rules,
+	)
+
+	// The ServiceAccount also gets the extra annotations, e.g. for IRSA
+	serviceAccount := objects[0].Object
+	annotations, known := mergeStringMaps(model.ServiceAccountAnnotations, types.MapNull(types.StringType))
+	if !known {
+		annotations = map[string]string{}
+	}
+	for k, v := range serviceAccount.GetAnnotations() {
+		annotations[k] = v
+	}
+	serviceAccount.SetAnnotations(annotations)
+	return objects, diags
+}
+
+// applyRBAC creates the rbac objects in order, or updates them when they
+// already exist
+func applyRBAC(ctx context.Context, operations *tekton.ResourceOperations, objects []tekton.RBACObject) error {
+	for _, o := range objects {
+		if err := operations.CreateResource(ctx, o.Object, o.Resource.Group, o.Resource.Version, o.Resource.Resource); err != nil {
+			return fmt.Errorf("%s %s: %w", o.Object.GetKind(), o.Object.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// deleteRBAC deletes the rbac objects in reverse order, attempting all of
+// them. NotFound is not an error.
+func deleteRBAC(ctx context.Context, operations *tekton.ResourceOperations, objects []tekton.RBACObject) error {
+	var errs []error
+	for i := len(objects) - 1; i >= 0; i-- {
+		o := objects[i]
+		if err := operations.DeleteResource(ctx, o.Object.GetNamespace(), o.Object.GetName(), o.Resource.Group, o.Resource.Version, o.Resource.Resource); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", o.Object.GetKind(), o.Object.GetName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rollbackRBAC deletes rbac objects created earlier in a failed Create,
+// warning when that fails too
+func rollbackRBAC(ctx context.Context, operations *tekton.ResourceOperations, objects []tekton.RBACObject) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if err := deleteRBAC(ctx, operations, objects); err != nil {
+		diags.AddWarning(
+			"Rollback of orphaned RBAC objects failed",
+			fmt.Sprintf("Could not clean up the action's ServiceAccount, Role and binding: %s. Manual cleanup may be required.", err.Error()),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// testRBAC returns an rbac block with a single rule allowing get on pods
+func testRBAC(clusterScoped bool) types.Object {
+	list := func(values ...string) types.List {
+		elems := make([]attr.Value, len(values))
+		for i, v := range values {
+			elems[i] = types.StringValue(v)
+		}
+		return types.ListValueMust(types.StringType, elems)
+	}
+	rule := types.ObjectValueMust(rbacRuleObjectType.AttrTypes, map[string]attr.Value{
+		"api_groups":     list(""),
+		"resources":      list("pods"),
+		"resource_names": types.ListNull(types.StringType),
+		"verbs":          list("get"),
+	})
+	return types.ObjectValueMust(actionRBACAttrTypes, map[string]attr.Value{
+		"cluster_scoped":              types.BoolValue(clusterScoped),
+		"rules":                       types.ListValueMust(rbacRuleObjectType, []attr.Value{rule}),
+		"service_account_annotations": testStringMap(map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/action"}),
+	})
+}
+
+func TestBuildRBACObjects(t *testing.T) {
+	objects, diags := buildRBACObjects(context.Background(), types.ObjectNull(actionRBACAttrTypes), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{})
+	if diags.HasError() || objects != nil {
+		t.Fatalf("expected no objects without rbac, got %v, %v", objects, diags)
+	}
+
+	objects, diags = buildRBACObjects(context.Background(), testRBAC(false), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{DisplayName: "my-action"})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(objects) != 3 || objects[0].Object.GetName() != tekton.ServiceAccountName(k8sTaskName) {
+		t.Fatalf("objects = %v", objects)
+	}
+	if got := objects[1].Object.GetAnnotations()[tekton.AnnotationDisplayName]; got != "my-action" {
+		t.Errorf("Role annotation %s = %q, want my-action", tekton.AnnotationDisplayName, got)
+	}
+	serviceAccountAnnotations := objects[0].Object.GetAnnotations()
+	if serviceAccountAnnotations["eks.amazonaws.com/role-arn"] == "" || serviceAccountAnnotations[tekton.AnnotationDisplayName] != "my-action" {
+		t.Errorf("ServiceAccount annotations = %v, want the service_account_annotations and the action's annotations", serviceAccountAnnotations)
+	}
+	if _, found := objects[1].Object.GetAnnotations()["eks.amazonaws.com/role-arn"]; found {
+		t.Error("service_account_annotations should only be set on the ServiceAccount")
+	}
+}
+
+func TestBuildActionMetadata_ServiceAccountAnnotation(t *testing.T) {
+	in := testMetadataInput(types.MapNull(types.StringType), types.MapNull(types.StringType))
+	in.RBAC = testRBAC(false)
+
+	metadata, known, diags := buildActionMetadata(context.Background(), in, nil)
+	if diags.HasError() || !known {
+		t.Fatalf("buildActionMetadata() known = %v, diags = %v", known, diags)
+	}
+	names, _ := plannedNames(context.Background(), in)
+	if got := metadata.Annotations()[tekton.AnnotationServiceAccount]; got != names.ServiceAccountName {
+		t.Errorf("annotation %s = %q, want %q", tekton.AnnotationServiceAccount, got, names.ServiceAccountName)
+	}
+
+	// An existing action keeps the ServiceAccount named after its Task
+	in.TaskName = types.StringValue(k8sTaskName)
+	metadata, _, _ = buildActionMetadata(context.Background(), in, nil)
+	if got := metadata.Annotations()[tekton.AnnotationServiceAccount]; got != tekton.ServiceAccountName(k8sTaskName) {
+		t.Errorf("annotation %s = %q, want %q", tekton.AnnotationServiceAccount, got, tekton.ServiceAccountName(k8sTaskName))
+	}
+
+	in.RBAC = types.ObjectNull(actionRBACAttrTypes)
+	metadata, _, _ = buildActionMetadata(context.Background(), in, nil)
+	if _, found := metadata.Annotations()[tekton.AnnotationServiceAccount]; found {
+		t.Errorf("annotation %s should not be set without rbac", tekton.AnnotationServiceAccount)
+	}
+}
+
+// rbacExists reports whether the object of gvr named name exists in namespace
+func rbacExists(t *testing.T, c dynamic.Interface, gvr k8sschema.GroupVersionResource, namespace, name string) bool {
+	t.Helper()
+	_, err := c.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("get %s %s: %v", gvr.Resource, name, err)
+	}
+	return err == nil
+}
+
+func TestK8sCreate_CreatesRBAC(t *testing.T) {
+	r, c := resourceWithFake()
+	ops := tekton.NewResourceOperations(c)
+	rbac, _ := buildRBACObjects(context.Background(), testRBAC(false), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{})
+
+	stepAction := testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
+	if diags := r.createResources(context.Background(), ops, stepAction, task, nil, rbac); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	name := tekton.ServiceAccountName(k8sTaskName)
+	for _, gvr := range []k8sschema.GroupVersionResource{testfake.ServiceAccountGVR, testfake.RoleGVR, testfake.RoleBindingGVR} {
+		if !rbacExists(t, c, gvr, k8sReadTestNamespace, name) {
+			t.Errorf("expected %s %s to be created", gvr.Resource, name)
+		}
+	}
+}
+
+func TestK8sCreate_TaskFails_RollsBackRBAC(t *testing.T) {
+	r, c := resourceWithFake()
+	testfake.WithError(c, "create", testfake.TaskGVR, testfake.ErrInternalServer("etcd unavailable"))
+	ops := tekton.NewResourceOperations(c)
+	rbac, _ := buildRBACObjects(context.Background(), testRBAC(true), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{})
+
+	stepAction := testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
+	if diags := r.createResources(context.Background(), ops, stepAction, task, nil, rbac); !diags.HasError() {
+		t.Fatal("expected Task-create error to surface in diagnostics")
+	}
+
+	for _, o := range rbac {
+		if rbacExists(t, c, o.Resource, o.Object.GetNamespace(), o.Object.GetName()) {
+			t.Errorf("expected %s %s rolled back after Task-create failed", o.Object.GetKind(), o.Object.GetName())
+		}
+	}
+}
+
+func TestK8sUpdate_SwitchToClusterScopedRBAC(t *testing.T) {
+	previous, _ := buildRBACObjects(context.Background(), testRBAC(false), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{})
+	r, c := resourceWithFake(
+		testfake.Task(k8sReadTestNamespace, k8sTaskName, nil),
+		testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil),
+		previous[0].Object, previous[1].Object, previous[2].Object,
+	)
+	ops := tekton.NewResourceOperations(c)
+
+	rbac, _ := buildRBACObjects(context.Background(), testRBAC(true), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{})
+	stale := tekton.StaleRBAC(rbacRefs(context.Background(), testRBAC(false), k8sReadTestNamespace, k8sTaskName), rbac)
+	stepAction := testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
+	if diags := r.updateResources(context.Background(), ops, stepAction, task, nil, false, rbac, stale); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	name := tekton.ServiceAccountName(k8sTaskName)
+	clusterName := tekton.ClusterRBACName(k8sReadTestNamespace, name)
+	if !rbacExists(t, c, testfake.ServiceAccountGVR, k8sReadTestNamespace, name) {
+		t.Error("expected the ServiceAccount to be kept")
+	}
+	if !rbacExists(t, c, testfake.ClusterRoleGVR, "", clusterName) || !rbacExists(t, c, testfake.ClusterRoleBindingGVR, "", clusterName) {
+		t.Error("expected the ClusterRole and ClusterRoleBinding to be created")
+	}
+	if rbacExists(t, c, testfake.RoleGVR, k8sReadTestNamespace, name) || rbacExists(t, c, testfake.RoleBindingGVR, k8sReadTestNamespace, name) {
+		t.Error("expected the previous Role and RoleBinding to be deleted")
+	}
+}
+
+func TestK8sDelete_DeletesRBAC(t *testing.T) {
+	objects, _ := buildRBACObjects(context.Background(), testRBAC(false), k8sReadTestNamespace, k8sTaskName, &tekton.ResourceMetadata{})
+	r, c := resourceWithFake(
+		testfake.Task(k8sReadTestNamespace, k8sTaskName, nil),
+		testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil),
+		objects[0].Object, objects[1].Object, objects[2].Object,
+	)
+	ops := tekton.NewResourceOperations(c)
+
+	refs := rbacRefs(context.Background(), testRBAC(false), k8sReadTestNamespace, k8sTaskName)
+	if diags := r.deleteResources(context.Background(), ops, k8sReadTestNamespace, k8sTaskName, k8sStepActionName, false, refs); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	for _, o := range objects {
+		if rbacExists(t, c, o.Resource, o.Object.GetNamespace(), o.Object.GetName()) {
+			t.Errorf("expected %s %s to be deleted", o.Object.GetKind(), o.Object.GetName())
+		}
+	}
+}
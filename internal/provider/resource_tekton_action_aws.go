@@ -4,15 +4,17 @@ import (
 	"context"
 	"fmt"
 	"regexp"
-	"strings"
 
 	"github.com/facets-cloud/terraform-provider-facets/internal/aws"
 	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
 	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
@@ -20,49 +22,90 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 )
 
-const tektonPipelinesNamespace = "tekton-pipelines"
+// namespaceOrDefault returns the namespace recorded in state, or
+// tektonPipelinesNamespace for state written before the AWS resource had a
+// namespace attribute (those objects were always created there).
+func namespaceOrDefault(namespace types.String) string {
+	if namespace.IsNull() || namespace.IsUnknown() || namespace.ValueString() == "" {
+		return tektonPipelinesNamespace
+	}
+	return namespace.ValueString()
+}
 
 var (
-	_ resource.Resource                = &TektonActionAWSResource{}
-	_ resource.ResourceWithConfigure   = &TektonActionAWSResource{}
-	_ resource.ResourceWithImportState = &TektonActionAWSResource{}
+	_ resource.Resource                     = &TektonActionAWSResource{}
+	_ resource.ResourceWithConfigure        = &TektonActionAWSResource{}
+	_ resource.ResourceWithImportState      = &TektonActionAWSResource{}
+	_ resource.ResourceWithConfigValidators = &TektonActionAWSResource{}
+	_ resource.ResourceWithModifyPlan       = &TektonActionAWSResource{}
+	_ resource.ResourceWithIdentity         = &TektonActionAWSResource{}
 )
 
 // NewTektonActionAWSResource creates a new AWS action resource
 func NewTektonActionAWSResource() resource.Resource {
 	return &TektonActionAWSResource{
-		clientFactory: k8s.GetKubernetesClient,
+		clientFactory:    k8s.GetKubernetesClient,
+		discoveryFactory: k8s.GetDiscoveryClient,
 	}
 }
 
 // TektonActionAWSResource manages Tekton Tasks and StepActions for AWS workflows
 type TektonActionAWSResource struct {
 	providerData *FacetsProviderModel
-	// No cached client - fresh client created per operation for thread safety.
-	//
-	// clientFactory produces a Kubernetes dynamic client. Defaults to
-	// k8s.GetKubernetesClient in production via NewTektonActionAWSResource.
+	// clientFactory produces a Kubernetes dynamic client. Configure replaces
+	// the k8s.GetKubernetesClient default set by NewTektonActionAWSResource
+	// with a lookup in the provider's shared, rate-limited client pool.
 	// Tests in the same package may override this field directly to inject a
 	// fake client. Do not access from outside the provider package.
 	clientFactory func() (dynamic.Interface, error)
+	// discoveryFactory produces the discovery client used by the preflight
+	// checks in Create, from the same pool as clientFactory
+	discoveryFactory func() (discovery.DiscoveryInterface, error)
 }
 
 // TektonActionAWSResourceModel represents the resource data model
 // This is identical to the Kubernetes action model since the schema is the same
 type TektonActionAWSResourceModel struct {
-	ID                 types.String `tfsdk:"id"`
-	Name               types.String `tfsdk:"name"`
-	Description        types.String `tfsdk:"description"`
-	FacetsResourceName types.String `tfsdk:"facets_resource_name"`
-	FacetsEnvironment  types.Object `tfsdk:"facets_environment"`
-	FacetsResource     types.Object `tfsdk:"facets_resource"`
-	Steps              types.List   `tfsdk:"steps"`
-	Params             types.List   `tfsdk:"params"`
-	TaskName           types.String `tfsdk:"task_name"`
-	StepActionName     types.String `tfsdk:"step_action_name"`
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Description          types.String `tfsdk:"description"`
+	FacetsResourceName   types.String `tfsdk:"facets_resource_name"`
+	FacetsEnvironment    types.Object `tfsdk:"facets_environment"`
+	FacetsResource       types.Object `tfsdk:"facets_resource"`
+	Namespace            types.String `tfsdk:"namespace"`
+	Labels               types.Map    `tfsdk:"labels"`
+	Annotations          types.Map    `tfsdk:"annotations"`
+	Steps                types.List   `tfsdk:"steps"`
+	Params               types.List   `tfsdk:"params"`
+	CredentialStep       types.Object `tfsdk:"credential_step"`
+	RBAC                 types.Object `tfsdk:"rbac"`
+	TaskName             types.String `tfsdk:"task_name"`
+	StepActionName       types.String `tfsdk:"step_action_name"`
+	ClusterID            types.String `tfsdk:"cluster_id"`
+	EffectiveLabels      types.Map    `tfsdk:"effective_labels"`
+	EffectiveAnnotations types.Map    `tfsdk:"effective_annotations"`
+	TaskManifest         types.String `tfsdk:"task_manifest"`
+	StepActionManifest   types.String `tfsdk:"step_action_manifest"`
+}
+
+// metadataInput returns the attributes that determine the action's labels and annotations
+func (m TektonActionAWSResourceModel) metadataInput() actionMetadataInput {
+	return actionMetadataInput{
+		Name:               m.Name,
+		FacetsResourceName: m.FacetsResourceName,
+		FacetsEnvironment:  m.FacetsEnvironment,
+		FacetsResource:     m.FacetsResource,
+		Labels:             m.Labels,
+		Annotations:        m.Annotations,
+		ClusterID:          m.ClusterID,
+		RBAC:               m.RBAC,
+		TaskName:           m.TaskName,
+		IsCloudAction:      true,
+	}
 }
 
 func (r *TektonActionAWSResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -128,6 +171,37 @@ func (r *TektonActionAWSResource) Schema(ctx context.Context, req resource.Schem
 					},
 				},
 			},
+			"namespace": schema.StringAttribute{
+				Description: "Kubernetes namespace for Tekton resources. Defaults to \"tekton-pipelines\". Changing this forces recreation of the resource.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`),
+						"must be a valid Kubernetes namespace name (lowercase alphanumeric and hyphens, cannot start or end with hyphen)",
+					),
+					stringvalidator.LengthAtMost(63),
+				},
+			},
+			"labels": schema.MapAttribute{
+				Description: "Custom labels to apply to the Tekton Task and StepAction resources. " +
+					"These labels are merged with auto-generated labels (display_name, resource_name, " +
+					"resource_kind, environment_unique_name, cluster_id). Auto-generated labels take " +
+					"precedence and cannot be overwritten.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"annotations": schema.MapAttribute{
+				Description: "Custom annotations to apply to the Tekton Task and StepAction resources, " +
+					"e.g. for cost or ownership tooling. These are merged with the facets.cloud/* annotations " +
+					"that record the action's identity; the facets.cloud/* annotations take precedence.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
 			"steps": schema.ListNestedAttribute{
 				Description: "List of steps for the Tekton Task",
 				Required:    true,
@@ -188,6 +262,23 @@ func (r *TektonActionAWSResource) Schema(ctx context.Context, req resource.Schem
 								},
 							},
 						},
+						"sensitive_env": schema.MapAttribute{
+							Description: "Environment variables with sensitive values, such as tokens. The values are " +
+								"stored in a Kubernetes Secret owned by this resource and referenced from the step " +
+								"with secretKeyRef, so they never appear in the Task. They are still stored in " +
+								"Terraform state, marked sensitive.",
+							Optional:    true,
+							Sensitive:   true,
+							ElementType: types.StringType,
+							Validators: []validator.Map{
+								mapvalidator.KeysAre(
+									stringvalidator.RegexMatches(
+										regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`),
+										"must be a valid environment variable name (uppercase letters, numbers, and underscores, cannot start with a number)",
+									),
+								),
+							},
+						},
 					},
 				},
 			},
@@ -214,6 +305,8 @@ func (r *TektonActionAWSResource) Schema(ctx context.Context, req resource.Schem
 					},
 				},
 			},
+			"credential_step": credentialStepResourceAttribute(tekton.AWSCredentialStepTools),
+			"rbac":            rbacResourceAttribute(),
 			"task_name": schema.StringAttribute{
 				Description: "Generated Tekton Task name (computed from hash of resource_name, environment, and name). " +
 					"This is the actual Kubernetes resource name and may be truncated to 63 characters.",
@@ -224,10 +317,116 @@ func (r *TektonActionAWSResource) Schema(ctx context.Context, req resource.Schem
 					"This StepAction automatically configures AWS access for the workflow steps.",
 				Computed: true,
 			},
+			"cluster_id": schema.StringAttribute{
+				Description: "Cluster identifier recorded in the cluster_id label and facets.cloud/cluster-id annotation. " +
+					"Resolved from the provider's cluster_id, derive_cluster_id or the CLUSTER_ID environment variable " +
+					"(in that order), defaulting to \"na\". A change shows up in the plan as an in-place update.",
+				Computed: true,
+			},
+			"effective_labels": schema.MapAttribute{
+				Description: "All labels applied to the Task and StepAction: the provider's default_labels, " +
+					"overridden by labels, overridden by the auto-generated labels.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"effective_annotations": schema.MapAttribute{
+				Description: "All annotations applied to the Task and StepAction: the provider's default_annotations, " +
+					"overridden by annotations, overridden by the facets.cloud/* annotations.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"task_manifest":        manifestResourceAttribute("Task"),
+			"step_action_manifest": manifestResourceAttribute("StepAction"),
 		},
 	}
 }
 
+// IdentitySchema identifies an action by the namespace and name of its Task,
+// so import blocks can name it with provider::facets::task_name
+func (r *TektonActionAWSResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = actionIdentitySchema()
+}
+
+// ConfigValidators rejects reserved and duplicate step, param and env var names
+// and invalid step compute resources
+func (r *TektonActionAWSResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		actionNamesValidator{reserved: tekton.AWSReservedNames()},
+		computeResourcesValidator{},
+	}
+}
+
+// ModifyPlan resolves cluster_id, the default namespace and the merged
+// effective_labels/effective_annotations from the provider configuration, so
+// changes to provider defaults show up in the plan instead of being applied
+// silently. It then renders the planned objects as task_manifest and
+// step_action_manifest and evaluates the provider's policies against them.
+func (r *TektonActionAWSResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyActionPlan(ctx, req, resp, r.providerData, r.dynamicClient, true)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan TektonActionAWSResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(stateNames(ctx, req.State, &plan.TaskName, &plan.StepActionName)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	stepAction, task, diags := r.planObjects(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || task == nil {
+		return
+	}
+	resp.Diagnostics.Append(planManifests(ctx, &resp.Plan, stepAction, task)...)
+	if r.providerData.hasPolicies() {
+		resp.Diagnostics.Append(evaluatePolicies(ctx, r.providerData, actionTypeAWS, stepAction, task, false)...)
+	}
+}
+
+// planObjects renders the planned StepAction and Task. Both are nil while
+// anything the objects depend on, including the provider's aws block, is
+// unknown or missing; Create and Update render them again and report a
+// missing aws block.
+func (r *TektonActionAWSResource) planObjects(ctx context.Context, plan TektonActionAWSResourceModel) (stepAction, task *unstructured.Unstructured, diags diag.Diagnostics) {
+	if r.providerData == nil || r.providerData.AWS.IsNull() || !valuesKnown(ctx, r.providerData.AWS, plan.Description, plan.Namespace, plan.Steps, plan.Params) {
+		return nil, nil, diags
+	}
+	awsConfig, err := aws.GetAWSConfig(ctx, &aws.ProviderModel{AWS: r.providerData.AWS})
+	if err != nil {
+		return nil, nil, diags
+	}
+	policy, known := r.providerData.stepPolicy(ctx)
+	if !known {
+		return nil, nil, diags
+	}
+	credentialStep, known, d := r.providerData.credentialStep(ctx, plan.CredentialStep)
+	diags.Append(d...)
+	if diags.HasError() || !known {
+		return nil, nil, diags
+	}
+	metadata, known, d := buildActionMetadata(ctx, plan.metadataInput(), r.providerData)
+	diags.Append(d...)
+	if diags.HasError() || !known {
+		return nil, nil, diags
+	}
+	if plan.TaskName.IsNull() || plan.TaskName.IsUnknown() {
+		names, d := plannedNames(ctx, plan.metadataInput())
+		diags.Append(d...)
+		if diags.HasError() {
+			return nil, nil, diags
+		}
+		plan.TaskName = types.StringValue(names.TaskName)
+		plan.StepActionName = types.StringValue(names.StepActionName)
+	}
+
+	stepAction, task, _, err = r.renderObjects(ctx, plan, policy, credentialStep, metadata, awsConfig)
+	if err != nil {
+		return nil, nil, diags
+	}
+	return stepAction, task, diags
+}
+
 func (r *TektonActionAWSResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Client will be created lazily when needed during CRUD operations.
 	// This allows terraform validate to pass without requiring a kubeconfig.
@@ -237,23 +436,27 @@ func (r *TektonActionAWSResource) Configure(ctx context.Context, req resource.Co
 	// to allow terraform validate to succeed without AWS credentials.
 	if req.ProviderData != nil {
 		// Type assert to get provider model
-		providerModel, ok := req.ProviderData.(*FacetsProviderModel)
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
 		if !ok {
 			resp.Diagnostics.AddError(
 				"Unexpected Provider Data Type",
-				fmt.Sprintf("Expected *FacetsProviderModel, got: %T", req.ProviderData),
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
 			)
 			return
 		}
-		r.providerData = providerModel
+		r.providerData = providerData.Model
+		r.clientFactory = kubernetesClientFactory(providerData)
+		r.discoveryFactory = kubernetesDiscoveryFactory(providerData)
 	}
 }
 
-// getClient returns a fresh Kubernetes client and operations for each call.
-// This pattern matches terraform-provider-helm best practices - no cached state,
-// thread-safe, and avoids stale client issues.
+// getClient returns a Kubernetes client and operations for each call.
+// The client comes from the provider's client pool, which is keyed by the
+// effective connection config and safe for concurrent use, so resources
+// targeting the same cluster share one transport and rate limiter.
 //
-// In production, clientFactory is k8s.GetKubernetesClient (set by
+// In production, clientFactory is built from the provider data in Configure.
+// Without provider data it stays k8s.GetKubernetesClient (set by
 // NewTektonActionAWSResource). In tests, the field can be overridden to
 // inject a fake dynamic.Interface for unit-testing CRUD lifecycle paths.
 func (r *TektonActionAWSResource) getClient() (dynamic.Interface, *tekton.ResourceOperations, error) {
@@ -271,6 +474,12 @@ func (r *TektonActionAWSResource) getClient() (dynamic.Interface, *tekton.Resour
 	return client, tekton.NewResourceOperations(client), nil
 }
 
+// dynamicClient returns a fresh Kubernetes client without the operations wrapper
+func (r *TektonActionAWSResource) dynamicClient() (dynamic.Interface, error) {
+	client, _, err := r.getClient()
+	return client, err
+}
+
 func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan TektonActionAWSResourceModel
 
@@ -280,7 +489,7 @@ func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.Creat
 	}
 
 	// Create fresh client for this operation
-	_, operations, err := r.getClient()
+	client, operations, err := r.getClient()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Kubernetes Client",
@@ -289,6 +498,21 @@ func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	// Set defaults
+	if plan.Namespace.IsNull() || plan.Namespace.ValueString() == "" {
+		plan.Namespace = types.StringValue(r.providerData.defaultNamespace())
+	}
+
+	// Check the cluster prerequisites before the first write, so a missing
+	// Tekton installation or namespace is reported as such. Render mode has no
+	// cluster to check.
+	if !r.providerData.renderMode() {
+		resp.Diagnostics.Append(preflightAction(ctx, r.providerData, r.discoveryFactory, client, plan.Namespace.ValueString(), plan.RBAC)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Extract environment unique_name from environment object
 	var facetsEnv tekton.FacetsEnvironmentModel
 	resp.Diagnostics.Append(plan.FacetsEnvironment.As(ctx, &facetsEnv, basetypes.ObjectAsOptions{})...)
@@ -296,13 +520,6 @@ func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	// Extract resource_kind from facets_resource object
-	var facetsRes tekton.FacetsResourceModel
-	resp.Diagnostics.Append(plan.FacetsResource.As(ctx, &facetsRes, basetypes.ObjectAsOptions{})...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
 	// Generate names using hash for uniqueness
 	names := tekton.GenerateNames(
 		plan.FacetsResourceName.ValueString(),
@@ -311,17 +528,53 @@ func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.Creat
 	)
 	plan.TaskName = types.StringValue(names.TaskName)
 	plan.StepActionName = types.StringValue(names.StepActionName)
-	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", tektonPipelinesNamespace, names.TaskName))
+	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", plan.Namespace.ValueString(), names.TaskName))
 
-	// Create metadata (no custom labels for AWS actions currently)
-	metadata := tekton.NewResourceMetadata(
-		plan.Name.ValueString(),
-		plan.FacetsResourceName.ValueString(),
-		facetsRes.Kind.ValueString(),
-		facetsEnv.UniqueName.ValueString(),
-		true, // cloud_action: true for AWS actions
-		nil,  // customLabels: not supported for AWS actions yet
-	)
+	// Cluster ID is resolved during plan (ModifyPlan); only resolve again if it was unknown then
+	clusterID, err := plannedClusterID(ctx, plan.ClusterID, r.providerData, r.dynamicClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Cluster ID",
+			err.Error(),
+		)
+		return
+	}
+	plan.ClusterID = types.StringValue(clusterID)
+
+	// Merge provider defaults, custom labels/annotations and auto-generated metadata
+	metadata, known, diags := buildActionMetadata(ctx, plan.metadataInput(), r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !known {
+		resp.Diagnostics.AddError(
+			"Unable to Build Metadata",
+			"Labels, annotations or identity attributes are still unknown during apply.",
+		)
+		return
+	}
+	plan.EffectiveLabels, diags = stringMapValue(ctx, metadata.Labels())
+	resp.Diagnostics.Append(diags...)
+	plan.EffectiveAnnotations, diags = stringMapValue(ctx, metadata.Annotations())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Enforce the provider's step policy; steps without resources get its defaults
+	policy, diags := applyStepPolicy(ctx, plan.Steps, r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resolve the credential step image and pull policy
+	credentialStep, diags := applyCredentialStep(ctx, plan.CredentialStep, r.providerData, policy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Validate provider data is available
 	if r.providerData == nil {
@@ -345,13 +598,8 @@ func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	// Create StepAction
-	stepAction, err := tekton.BuildAWSStepAction(
-		plan.StepActionName.ValueString(),
-		tektonPipelinesNamespace,
-		metadata.LabelsAsInterface(),
-		awsConfig,
-	)
+	// Build StepAction and Task, then check them against the provider's policies
+	stepAction, task, secret, err := r.renderObjects(ctx, plan, policy, credentialStep, metadata, awsConfig)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error building StepAction",
@@ -359,30 +607,66 @@ func (r *TektonActionAWSResource) Create(ctx context.Context, req resource.Creat
 		)
 		return
 	}
-	// Build Task
-	task := r.buildAWSTask(ctx, plan, metadata.LabelsAsInterface())
+	resp.Diagnostics.Append(evaluatePolicies(ctx, r.providerData, actionTypeAWS, stepAction, task, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.StepActionManifest, plan.TaskManifest, diags = actionManifests(stepAction, task)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(r.createResources(ctx, operations, stepAction, task)...)
+	// Build the action's own ServiceAccount, Role and binding, when it has rbac
+	rbacObjects, diags := buildRBACObjects(ctx, plan.RBAC, plan.Namespace.ValueString(), plan.TaskName.ValueString(), metadata)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.createResources(ctx, operations, stepAction, task, secret, rbacObjects)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, plan.Namespace, plan.TaskName)...)
 }
 
-// createResources creates the StepAction and Task in cluster. Mirrors the K8s variant.
+// createResources creates the sensitive env Secret and the rbac objects (when
+// there are any), the StepAction and the Task in cluster. Mirrors the K8s variant.
 //
 // Fix for issue #10 / Bug #1: if Task creation fails after StepAction creation
 // succeeded, the StepAction is rolled back via DeleteResource (idempotent on
 // NotFound per fix #11). If rollback itself fails, a warning is surfaced
 // alongside the original Task-create error so the operator knows manual
 // cleanup may be required.
-func (r *TektonActionAWSResource) createResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task *unstructured.Unstructured) diag.Diagnostics {
+func (r *TektonActionAWSResource) createResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task, secret *unstructured.Unstructured, rbac []tekton.RBACObject) diag.Diagnostics {
 	var diags diag.Diagnostics
+	// The sensitive env Secret comes first, so the Task never references a
+	// Secret that does not exist
+	if secret != nil {
+		if err := applyEnvSecret(ctx, operations, secret); err != nil {
+			diags.AddError(
+				"Error creating Secret",
+				fmt.Sprintf("Could not create sensitive env Secret: %s", err.Error()),
+			)
+			return diags
+		}
+	}
+	// The ServiceAccount the Task is annotated with exists before the Task
+	if err := applyRBAC(ctx, operations, rbac); err != nil {
+		diags.Append(rollbackRBAC(ctx, operations, rbac)...)
+		diags.Append(rollbackEnvSecret(ctx, operations, secret)...)
+		diags.AddError(
+			"Error creating RBAC objects",
+			fmt.Sprintf("Could not create the action's ServiceAccount, Role and binding: %s", err.Error()),
+		)
+		return diags
+	}
 	if err := operations.CreateResource(ctx, stepAction, "tekton.dev", "v1beta1", "stepactions"); err != nil {
+		diags.Append(rollbackRBAC(ctx, operations, rbac)...)
+		diags.Append(rollbackEnvSecret(ctx, operations, secret)...)
 		diags.AddError(
 			"Error creating StepAction",
 			fmt.Sprintf("Could not create StepAction: %s", err.Error()),
@@ -405,6 +689,8 @@ func (r *TektonActionAWSResource) createResources(ctx context.Context, operation
 					stepAction.GetNamespace(), stepAction.GetName(), rollbackErr.Error()),
 			)
 		}
+		diags.Append(rollbackRBAC(ctx, operations, rbac)...)
+		diags.Append(rollbackEnvSecret(ctx, operations, secret)...)
 		diags.AddError(
 			"Error creating Task",
 			fmt.Sprintf("Could not create Task: %s", err.Error()),
@@ -421,6 +707,9 @@ func (r *TektonActionAWSResource) Read(ctx context.Context, req resource.ReadReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// Set the identity up front so state written before identity support
+	// gets one even when the action is removed below
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, state.Namespace, state.TaskName)...)
 
 	// Create fresh client for this operation
 	client, _, err := r.getClient()
@@ -432,7 +721,7 @@ func (r *TektonActionAWSResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	remove, diags := r.readResourceState(ctx, client, state)
+	task, remove, diags := r.readResourceState(ctx, client, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -442,6 +731,34 @@ func (r *TektonActionAWSResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
+	// Backfill the namespace for state written before the attribute existed,
+	// so the next plan does not see a namespace change and force replacement.
+	state.Namespace = types.StringValue(namespaceOrDefault(state.Namespace))
+
+	if task != nil {
+		// Backfill the cluster ID for state written before the attribute existed,
+		// so upgrading does not plan a spurious cluster_id change
+		if state.ClusterID.IsNull() {
+			state.ClusterID = clusterIDFromTask(task)
+		}
+
+		// Refresh the labels and annotations actually set on the Task, so
+		// out-of-band changes show up as drift against effective_labels
+		state.EffectiveLabels, state.EffectiveAnnotations, diags = effectiveMetadataFromObject(ctx, task)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Clear sensitive_env values the Secret no longer holds, so the next
+		// plan writes them again
+		state.Steps, diags = refreshSensitiveEnv(ctx, client, state.Namespace.ValueString(), state.TaskName.ValueString(), state.Steps)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -454,44 +771,46 @@ func (r *TektonActionAWSResource) Read(ctx context.Context, req resource.ReadReq
 // state. Both Task and StepAction are checked; asymmetric in-cluster drift
 // (one present, one missing) surfaces a warning and retains state.
 //
-// Note: the AWS variant pins the namespace to tektonPipelinesNamespace
-// (the AWS model has no Namespace field).
-func (r *TektonActionAWSResource) readResourceState(ctx context.Context, client dynamic.Interface, state TektonActionAWSResourceModel) (removeFromState bool, diags diag.Diagnostics) {
+// Note: state written before the AWS resource had a namespace attribute has a
+// null namespace; namespaceOrDefault maps it to tektonPipelinesNamespace.
+func (r *TektonActionAWSResource) readResourceState(ctx context.Context, client dynamic.Interface, state TektonActionAWSResourceModel) (task *unstructured.Unstructured, removeFromState bool, diags diag.Diagnostics) {
+	namespace := namespaceOrDefault(state.Namespace)
 	taskGVR := k8sschema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "tasks"}
 	stepActionGVR := k8sschema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "stepactions"}
 
 	taskExists := true
-	_, err := client.Resource(taskGVR).Namespace(tektonPipelinesNamespace).Get(ctx, state.TaskName.ValueString(), metav1.GetOptions{})
+	task, err := client.Resource(taskGVR).Namespace(namespace).Get(ctx, state.TaskName.ValueString(), metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			task = nil
 			taskExists = false
 		} else {
 			diags.AddError(
 				"Error reading Task",
-				fmt.Sprintf("Could not read Task %s/%s: %s", tektonPipelinesNamespace, state.TaskName.ValueString(), err.Error()),
+				fmt.Sprintf("Could not read Task %s/%s: %s", namespace, state.TaskName.ValueString(), err.Error()),
 			)
-			return false, diags
+			return task, false, diags
 		}
 	}
 
 	stepActionExists := true
-	_, err = client.Resource(stepActionGVR).Namespace(tektonPipelinesNamespace).Get(ctx, state.StepActionName.ValueString(), metav1.GetOptions{})
+	_, err = client.Resource(stepActionGVR).Namespace(namespace).Get(ctx, state.StepActionName.ValueString(), metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			stepActionExists = false
 		} else {
 			diags.AddError(
 				"Error reading StepAction",
-				fmt.Sprintf("Could not read StepAction %s/%s: %s", tektonPipelinesNamespace, state.StepActionName.ValueString(), err.Error()),
+				fmt.Sprintf("Could not read StepAction %s/%s: %s", namespace, state.StepActionName.ValueString(), err.Error()),
 			)
-			return false, diags
+			return task, false, diags
 		}
 	}
 
 	switch {
 	case !taskExists && !stepActionExists:
 		// Both genuinely deleted — clean removal from state.
-		return true, diags
+		return nil, true, diags
 	case taskExists != stepActionExists:
 		// Asymmetric drift — refuse to silently mutate state.
 		diags.AddWarning(
@@ -504,10 +823,10 @@ func (r *TektonActionAWSResource) readResourceState(ctx context.Context, client
 				state.ID.ValueString(), taskExists, stepActionExists,
 			),
 		)
-		return false, diags
+		return task, false, diags
 	default:
 		// Both present and healthy.
-		return false, diags
+		return task, false, diags
 	}
 }
 
@@ -532,34 +851,58 @@ func (r *TektonActionAWSResource) Update(ctx context.Context, req resource.Updat
 	}
 
 	// Use state values for computed fields (StepActionName, TaskName)
-	// These are computed and unknown in the plan
+	// These are computed and unknown in the plan. Namespace has
+	// RequiresReplace(), so Update is never called with a changed namespace.
 	plan.StepActionName = state.StepActionName
 	plan.TaskName = state.TaskName
 	plan.ID = state.ID
+	plan.Namespace = types.StringValue(namespaceOrDefault(state.Namespace))
 
-	// Extract environment unique_name from environment object
-	var facetsEnv tekton.FacetsEnvironmentModel
-	resp.Diagnostics.Append(plan.FacetsEnvironment.As(ctx, &facetsEnv, basetypes.ObjectAsOptions{})...)
+	// Cluster ID is resolved during plan (ModifyPlan); only resolve again if it was unknown then
+	clusterID, err := plannedClusterID(ctx, plan.ClusterID, r.providerData, r.dynamicClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Cluster ID",
+			err.Error(),
+		)
+		return
+	}
+	plan.ClusterID = types.StringValue(clusterID)
+
+	// Merge provider defaults, custom labels/annotations and auto-generated metadata
+	metadata, known, diags := buildActionMetadata(ctx, plan.metadataInput(), r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !known {
+		resp.Diagnostics.AddError(
+			"Unable to Build Metadata",
+			"Labels, annotations or identity attributes are still unknown during apply.",
+		)
+		return
+	}
+	plan.EffectiveLabels, diags = stringMapValue(ctx, metadata.Labels())
+	resp.Diagnostics.Append(diags...)
+	plan.EffectiveAnnotations, diags = stringMapValue(ctx, metadata.Annotations())
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Extract resource_kind from facets_resource object
-	var facetsRes tekton.FacetsResourceModel
-	resp.Diagnostics.Append(plan.FacetsResource.As(ctx, &facetsRes, basetypes.ObjectAsOptions{})...)
+	// Enforce the provider's step policy; steps without resources get its defaults
+	policy, diags := applyStepPolicy(ctx, plan.Steps, r.providerData)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Create metadata (no custom labels for AWS actions currently)
-	metadata := tekton.NewResourceMetadata(
-		plan.Name.ValueString(),
-		plan.FacetsResourceName.ValueString(),
-		facetsRes.Kind.ValueString(),
-		facetsEnv.UniqueName.ValueString(),
-		true, // cloud_action: true for AWS actions
-		nil,  // customLabels: not supported for AWS actions yet
-	)
+	// Resolve the credential step image and pull policy
+	credentialStep, diags := applyCredentialStep(ctx, plan.CredentialStep, r.providerData, policy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Validate provider data is available
 	if r.providerData == nil {
@@ -583,13 +926,8 @@ func (r *TektonActionAWSResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
-	// Update StepAction
-	stepAction, err := tekton.BuildAWSStepAction(
-		plan.StepActionName.ValueString(),
-		tektonPipelinesNamespace,
-		metadata.LabelsAsInterface(),
-		awsConfig,
-	)
+	// Build StepAction and Task, then check them against the provider's policies
+	stepAction, task, secret, err := r.renderObjects(ctx, plan, policy, credentialStep, metadata, awsConfig)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error building StepAction",
@@ -597,18 +935,38 @@ func (r *TektonActionAWSResource) Update(ctx context.Context, req resource.Updat
 		)
 		return
 	}
-	// Build Task
-	task := r.buildAWSTask(ctx, plan, metadata.LabelsAsInterface())
+	resp.Diagnostics.Append(evaluatePolicies(ctx, r.providerData, actionTypeAWS, stepAction, task, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.StepActionManifest, plan.TaskManifest, diags = actionManifests(stepAction, task)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	resp.Diagnostics.Append(r.updateResources(ctx, operations, stepAction, task)...)
+	// Build the action's own ServiceAccount, Role and binding, and find the
+	// objects of the previous rbac that are no longer needed
+	rbacObjects, diags := buildRBACObjects(ctx, plan.RBAC, plan.Namespace.ValueString(), plan.TaskName.ValueString(), metadata)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	staleRBAC := tekton.StaleRBAC(rbacRefs(ctx, state.RBAC, namespaceOrDefault(state.Namespace), state.TaskName.ValueString()), rbacObjects)
+
+	resp.Diagnostics.Append(r.updateResources(ctx, operations, stepAction, task, secret, hasSensitiveEnv(ctx, state.Steps), rbacObjects, staleRBAC)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, plan.Namespace, plan.TaskName)...)
 }
 
-// updateResources updates the Task and StepAction in cluster. Mirrors the K8s variant.
+// updateResources updates the sensitive env Secret, the rbac objects, the
+// Task and the StepAction in cluster. It then deletes the Secret when
+// removeSecret is set and no step uses sensitive_env anymore, and the
+// staleRBAC objects. Mirrors the K8s variant.
 //
 // Task-first ordering rationale: updating Task before StepAction ensures that
 // if Task update fails (validation, RBAC, webhook), the StepAction is never
@@ -617,8 +975,26 @@ func (r *TektonActionAWSResource) Update(ctx context.Context, req resource.Updat
 // functional because the Task references the StepAction by immutable ref.name;
 // the old StepAction spec still resolves. The operator re-runs to retry the
 // StepAction update only.
-func (r *TektonActionAWSResource) updateResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task *unstructured.Unstructured) diag.Diagnostics {
+func (r *TektonActionAWSResource) updateResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task, secret *unstructured.Unstructured, removeSecret bool, rbac, staleRBAC []tekton.RBACObject) diag.Diagnostics {
 	var diags diag.Diagnostics
+	// Write the sensitive env Secret before the Task that references its keys
+	if secret != nil {
+		if err := applyEnvSecret(ctx, operations, secret); err != nil {
+			diags.AddError(
+				"Error updating Secret",
+				fmt.Sprintf("Could not update sensitive env Secret: %s", err.Error()),
+			)
+			return diags
+		}
+	}
+	// Write the ServiceAccount and its Role before the Task that names it
+	if err := applyRBAC(ctx, operations, rbac); err != nil {
+		diags.AddError(
+			"Error updating RBAC objects",
+			fmt.Sprintf("Could not update the action's ServiceAccount, Role and binding: %s", err.Error()),
+		)
+		return diags
+	}
 	// Update Task FIRST. If it fails (validation, RBAC, webhook), the StepAction
 	// is never touched and the cluster remains in a coherent pre-Update state.
 	if err := operations.UpdateResource(ctx, task, "tekton.dev", "v1beta1", "tasks"); err != nil {
@@ -640,6 +1016,22 @@ func (r *TektonActionAWSResource) updateResources(ctx context.Context, operation
 		)
 		return diags
 	}
+	// The Task no longer references the Secret once no step sets sensitive_env
+	if secret == nil && removeSecret {
+		if err := deleteEnvSecret(ctx, operations, task.GetNamespace(), task.GetName()); err != nil {
+			diags.AddError(
+				"Error deleting Secret",
+				fmt.Sprintf("Task updated successfully but the sensitive env Secret could not be deleted: %s", err.Error()),
+			)
+		}
+	}
+	// The Task no longer names the ServiceAccount, or the scope changed
+	if err := deleteRBAC(ctx, operations, staleRBAC); err != nil {
+		diags.AddError(
+			"Error deleting RBAC objects",
+			fmt.Sprintf("Task updated successfully but the action's previous RBAC objects could not be deleted: %s", err.Error()),
+		)
+	}
 	return diags
 }
 
@@ -661,18 +1053,16 @@ func (r *TektonActionAWSResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	resp.Diagnostics.Append(r.deleteResources(ctx, operations, tektonPipelinesNamespace, state.TaskName.ValueString(), state.StepActionName.ValueString())...)
+	resp.Diagnostics.Append(r.deleteResources(ctx, operations, namespaceOrDefault(state.Namespace), state.TaskName.ValueString(), state.StepActionName.ValueString(), hasSensitiveEnv(ctx, state.Steps),
+		rbacRefs(ctx, state.RBAC, namespaceOrDefault(state.Namespace), state.TaskName.ValueString()))...)
 }
 
-// deleteResources attempts to delete both the Task and the StepAction, using
-// best-effort semantics — if one fails, the other is still attempted, and both
-// errors are aggregated into the returned diagnostics. Combined with the
-// idempotent DeleteResource (which treats NotFound as success), this means
-// destroy retries are safe.
-//
-// Note: the AWS variant pins the namespace to tektonPipelinesNamespace
-// (the AWS model has no Namespace field).
-func (r *TektonActionAWSResource) deleteResources(ctx context.Context, operations *tekton.ResourceOperations, namespace, taskName, stepActionName string) diag.Diagnostics {
+// deleteResources attempts to delete the Task, the StepAction, the rbac
+// objects and, when hasSecret is set, the sensitive env Secret, using
+// best-effort semantics — if one fails, the others are still attempted, and
+// all errors are aggregated into the returned diagnostics. Combined with the idempotent DeleteResource
+// (which treats NotFound as success), this means destroy retries are safe.
+func (r *TektonActionAWSResource) deleteResources(ctx context.Context, operations *tekton.ResourceOperations, namespace, taskName, stepActionName string, hasSecret bool, rbac []tekton.RBACObject) diag.Diagnostics {
 	var diags diag.Diagnostics
 	taskErr := operations.DeleteResource(ctx, namespace, taskName, "tekton.dev", "v1beta1", "tasks")
 	stepActionErr := operations.DeleteResource(ctx, namespace, stepActionName, "tekton.dev", "v1beta1", "stepactions")
@@ -688,20 +1078,35 @@ func (r *TektonActionAWSResource) deleteResources(ctx context.Context, operation
 			fmt.Sprintf("Could not delete StepAction: %s", stepActionErr.Error()),
 		)
 	}
+	if hasSecret {
+		if err := deleteEnvSecret(ctx, operations, namespace, taskName); err != nil {
+			diags.AddError(
+				"Error deleting Secret",
+				fmt.Sprintf("Could not delete sensitive env Secret: %s", err.Error()),
+			)
+		}
+	}
+	if err := deleteRBAC(ctx, operations, rbac); err != nil {
+		diags.AddError(
+			"Error deleting RBAC objects",
+			fmt.Sprintf("Could not delete the action's ServiceAccount, Role and binding: %s", err.Error()),
+		)
+	}
 	return diags
 }
 
 func (r *TektonActionAWSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: taskName or namespace/taskName (namespace is ignored, always uses tekton-pipelines)
-	// Example: 59f6f855860ddc99a32e2944c96db5fa
+	// Import by identity, or by an ID of the form [namespace/]task_name or
+	// [namespace/]environment_unique_name:facets_resource_name:name
 	// Example: tekton-pipelines/59f6f855860ddc99a32e2944c96db5fa
-
-	taskName := req.ID
-	// Support legacy format namespace/taskName - extract just the task name
-	if strings.Contains(req.ID, "/") {
-		parts := strings.SplitN(req.ID, "/", 2)
-		taskName = parts[1]
+	// Example: production:my-app:Restart API pods
+	target, diags := importActionTarget(ctx, req, r.providerData.defaultNamespace())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	namespace := target.Namespace
+	taskName := target.TaskName
 
 	// Create fresh client for this operation
 	client, _, err := r.getClient()
@@ -713,88 +1118,100 @@ func (r *TektonActionAWSResource) ImportState(ctx context.Context, req resource.
 		return
 	}
 
-	// Verify Task exists
-	gvr := k8sschema.GroupVersionResource{
-		Group:    "tekton.dev",
-		Version:  "v1beta1",
-		Resource: "tasks",
-	}
-
-	task, err := client.Resource(gvr).Namespace(tektonPipelinesNamespace).Get(ctx, taskName, metav1.GetOptions{})
+	task, err := client.Resource(taskGVR).Namespace(namespace).Get(ctx, taskName, metav1.GetOptions{})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error importing resource",
-			fmt.Sprintf("Could not find Task %s/%s: %s", tektonPipelinesNamespace, taskName, err.Error()),
+			fmt.Sprintf("Could not find %s: %s", target, err.Error()),
 		)
 		return
 	}
 
-	// Extract metadata from labels
-	labels, found, _ := unstructured.NestedStringMap(task.Object, "metadata", "labels")
-	if !found {
-		resp.Diagnostics.AddError(
-			"Error importing resource",
-			"Task does not have required labels for import",
-		)
+	// Parse the configuration back from the Task, its StepAction and env Secret
+	imported, diags := importAction(ctx, client, task, tekton.AWSReservedNames(), r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	displayName, hasDisplayName := labels["display_name"]
-	resourceName, hasResourceName := labels["resource_name"]
-	_, hasResourceKind := labels["resource_kind"]
-	_, hasEnvUniqueName := labels["environment_unique_name"]
-
-	if !hasDisplayName || !hasResourceName || !hasResourceKind || !hasEnvUniqueName {
-		resp.Diagnostics.AddError(
-			"Error importing resource",
-			"Task missing required labels: display_name, resource_name, resource_kind, environment_unique_name",
-		)
+	resp.Diagnostics.Append(target.check(imported)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Find StepAction (convention: setup-credentials-{hash})
-	stepActionName := fmt.Sprintf("setup-credentials-%s", taskName)
-
 	// Set state with imported values
 	state := TektonActionAWSResourceModel{
-		ID:                 types.StringValue(fmt.Sprintf("%s/%s", tektonPipelinesNamespace, taskName)),
-		Name:               types.StringValue(displayName),
-		FacetsResourceName: types.StringValue(resourceName),
+		ID:                 types.StringValue(fmt.Sprintf("%s/%s", namespace, taskName)),
+		Name:               imported.Name,
+		Description:        imported.Description,
+		FacetsResourceName: imported.FacetsResourceName,
+		FacetsEnvironment:  imported.FacetsEnvironment,
+		FacetsResource:     imported.FacetsResource,
+		Namespace:          types.StringValue(namespace),
+		Labels:             imported.Labels,
+		Annotations:        imported.Annotations,
+		Steps:              imported.Steps,
+		Params:             imported.Params,
 		TaskName:           types.StringValue(taskName),
-		StepActionName:     types.StringValue(stepActionName),
+		StepActionName:     imported.StepActionName,
+		ClusterID:          imported.ClusterID,
+		CredentialStep:     imported.CredentialStep,
+		RBAC:               types.ObjectNull(actionRBACAttrTypes),
 	}
 
-	// Note: We cannot fully reconstruct facets_environment, facets_resource, steps, params from the Task
-	// User will need to manually specify these in their configuration
-	resp.Diagnostics.AddWarning(
-		"Partial Import",
-		"Only basic fields were imported. You must manually specify: facets_environment, facets_resource, steps, and params in your configuration.",
-	)
+	effectiveLabels, effectiveAnnotations, diags := effectiveMetadataFromObject(ctx, task)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.EffectiveLabels = effectiveLabels
+	state.EffectiveAnnotations = effectiveAnnotations
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, state.Namespace, state.TaskName)...)
+}
+
+// renderObjects builds the StepAction and Task for a plan whose names,
+// namespace and metadata are resolved
+func (r *TektonActionAWSResource) renderObjects(ctx context.Context, plan TektonActionAWSResourceModel, policy tekton.StepPolicy, credentialStep tekton.CredentialStep, metadata *tekton.ResourceMetadata, awsConfig *aws.AWSAuthConfig) (stepAction, task, secret *unstructured.Unstructured, err error) {
+	stepAction, err = tekton.BuildAWSStepAction(
+		plan.StepActionName.ValueString(),
+		plan.TaskName.ValueString(),
+		plan.Namespace.ValueString(),
+		metadata.LabelsAsInterface(),
+		metadata.AnnotationsAsInterface(),
+		awsConfig,
+		credentialStep,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	task = r.buildAWSTask(ctx, plan, policy, credentialStep, metadata.LabelsAsInterface(), metadata.AnnotationsAsInterface())
+	secret = buildEnvSecret(ctx, plan.Steps, plan.Namespace.ValueString(), plan.TaskName.ValueString(), metadata)
+	return stepAction, task, secret, nil
 }
 
 // buildAWSTask creates the Tekton Task for AWS workflows
-func (r *TektonActionAWSResource) buildAWSTask(ctx context.Context, plan TektonActionAWSResourceModel, labels map[string]interface{}) *unstructured.Unstructured {
+func (r *TektonActionAWSResource) buildAWSTask(ctx context.Context, plan TektonActionAWSResourceModel, policy tekton.StepPolicy, credentialStep tekton.CredentialStep, labels, annotations map[string]interface{}) *unstructured.Unstructured {
 	// Build steps
 	var steps []tekton.StepModel
 	plan.Steps.ElementsAs(ctx, &steps, false)
 
 	// First step: setup-credentials (references StepAction, no params needed)
-	tektonSteps := []interface{}{
-		map[string]interface{}{
-			"name": "setup-credentials",
-			"ref": map[string]interface{}{
-				"name": plan.StepActionName.ValueString(),
-			},
+	setupStep := map[string]interface{}{
+		"name": tekton.SetupCredentialsStepName,
+		"ref": map[string]interface{}{
+			"name": plan.StepActionName.ValueString(),
 		},
 	}
+	credentialStep.ApplyTo(setupStep)
+	tektonSteps := []interface{}{setupStep}
 
 	// Add user-defined steps with AWS_CONFIG_FILE env var
 	for _, step := range steps {
-		tektonStep := tekton.BuildStepWithResources(ctx, step)
+		tektonStep := tekton.BuildStepWithResources(ctx, step, policy)
+		tekton.AddSensitiveEnv(tektonStep, step, tekton.EnvSecretName(plan.TaskName.ValueString()))
 		// Inject AWS config file path - AWS SDK will use IRSA + source_profile for authentication
-		tekton.AddEnvVar(tektonStep, "AWS_CONFIG_FILE", "/workspace/.aws/config")
+		tekton.AddEnvVar(tektonStep, tekton.EnvAWSConfigFile, tekton.AWSConfigPath)
 		tektonSteps = append(tektonSteps, tektonStep)
 	}
 
@@ -818,8 +1235,9 @@ func (r *TektonActionAWSResource) buildAWSTask(ctx context.Context, plan TektonA
 
 	return tekton.BuildTask(tekton.TaskSpec{
 		TaskName:    plan.TaskName.ValueString(),
-		Namespace:   tektonPipelinesNamespace,
+		Namespace:   plan.Namespace.ValueString(),
 		Description: description,
 		Labels:      labels,
+		Annotations: annotations,
 	}, tektonSteps, taskParams)
 }
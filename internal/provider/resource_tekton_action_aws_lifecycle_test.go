@@ -31,7 +31,7 @@ func TestAWSCreate_TaskFails_RollsBackStepAction(t *testing.T) {
 	task := testfake.Task(tektonPipelinesNamespace, awsTaskName, nil)
 	ops := tekton.NewResourceOperations(c)
 
-	diags := r.createResources(context.Background(), ops, stepAction, task)
+	diags := r.createResources(context.Background(), ops, stepAction, task, nil, nil)
 	if !diags.HasError() {
 		t.Fatal("expected Task-create error to surface")
 	}
@@ -57,7 +57,7 @@ func TestAWSReadResourceState_StepActionMissing_AsymmetricDriftSurfaced(t *testi
 	r, c := awsResourceWithFake(task)
 
 	state := awsStateForRead(awsReadTestTaskName)
-	remove, diags := r.readResourceState(context.Background(), c, state)
+	_, remove, diags := r.readResourceState(context.Background(), c, state)
 
 	if remove {
 		t.Errorf("expected state retained on asymmetric drift (post-fix), got removeFromState=true")
@@ -91,7 +91,7 @@ func TestAWSUpdate_TaskFails_StepActionUntouched(t *testing.T) {
 	newTask := testfake.Task(tektonPipelinesNamespace, awsTaskName, map[string]string{"v": "2"})
 	ops := tekton.NewResourceOperations(c)
 
-	diags := r.updateResources(context.Background(), ops, newSA, newTask)
+	diags := r.updateResources(context.Background(), ops, newSA, newTask, nil, false, nil, nil)
 	if !diags.HasError() {
 		t.Fatal("expected Task-update error to surface")
 	}
@@ -127,7 +127,7 @@ func TestAWSDelete_TaskFails_StepActionStillAttempted(t *testing.T) {
 	testfake.WithError(c, "delete", testfake.TaskGVR, testfake.ErrForbidden(testfake.TaskGVR, awsTaskName))
 
 	ops := tekton.NewResourceOperations(c)
-	diags := r.deleteResources(context.Background(), ops, tektonPipelinesNamespace, awsTaskName, awsStepActionName)
+	diags := r.deleteResources(context.Background(), ops, tektonPipelinesNamespace, awsTaskName, awsStepActionName, false, nil)
 
 	if !diags.HasError() {
 		t.Fatal("expected Task-delete Forbidden error to surface")
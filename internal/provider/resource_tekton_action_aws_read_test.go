@@ -48,7 +48,7 @@ func TestAWSReadResourceState_TaskHealthy_KeepState(t *testing.T) {
 	task := testfake.Task(tektonPipelinesNamespace, awsReadTestTaskName, nil)
 	r, c := awsResourceWithFake(task)
 
-	remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained for healthy Task, got removeFromState=true")
 	}
@@ -62,7 +62,7 @@ func TestAWSReadResourceState_TaskHealthy_KeepState(t *testing.T) {
 func TestAWSReadResourceState_TaskNotFound_RemoveFromState(t *testing.T) {
 	r, c := awsResourceWithFake()
 
-	remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
 	if !remove {
 		t.Errorf("expected removeFromState=true when Task is genuinely missing, got false")
 	}
@@ -82,7 +82,7 @@ func TestAWSReadResourceState_TaskGet503_StateRetainedAndErrorSurfaced(t *testin
 	r, c := awsResourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrServiceUnavailable("apiserver overloaded"))
 
-	remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on transient 503 (post-fix), got removeFromState=true")
 	}
@@ -98,7 +98,7 @@ func TestAWSReadResourceState_TaskGetForbidden_StateRetainedAndErrorSurfaced(t *
 	r, c := awsResourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrForbidden(testfake.TaskGVR, awsReadTestTaskName))
 
-	remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on Forbidden (post-fix), got removeFromState=true")
 	}
@@ -114,7 +114,7 @@ func TestAWSReadResourceState_TaskGetServerTimeout_StateRetainedAndErrorSurfaced
 	r, c := awsResourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrServerTimeout("read"))
 
-	remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on ServerTimeout (post-fix), got removeFromState=true")
 	}
@@ -130,7 +130,7 @@ func TestAWSReadResourceState_TaskGetContextCanceled_StateRetainedAndErrorSurfac
 	r, c := awsResourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrContextCanceled())
 
-	remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, awsStateForRead(awsReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on context.Canceled (post-fix), got removeFromState=true")
 	}
@@ -154,7 +154,7 @@ func TestAWSReadResourceState_TaskMissing_StepActionPresent_AsymmetricDriftSurfa
 	state := awsStateForRead(awsReadTestTaskName)
 	state.StepActionName = types.StringValue(awsStepActionName)
 
-	remove, diags := r.readResourceState(context.Background(), c, state)
+	_, remove, diags := r.readResourceState(context.Background(), c, state)
 
 	if remove {
 		t.Errorf("expected state retained on asymmetric drift (post-fix), got removeFromState=true")
@@ -187,3 +187,36 @@ func TestAWSResource_FactorySeam_InjectsFakeClient(t *testing.T) {
 		t.Error("getClient returned a different client than the factory produced")
 	}
 }
+
+// --- Namespace -------------------------------------------------------------
+
+// TestAWSReadResourceState_CustomNamespace verifies Read looks up the Task and
+// StepAction in the namespace recorded in state rather than the default.
+func TestAWSReadResourceState_CustomNamespace(t *testing.T) {
+	task := testfake.Task("custom-ns", awsReadTestTaskName, nil)
+	stepAction := testfake.StepAction("custom-ns", "setup-credentials-"+awsReadTestTaskName, nil)
+	r, c := awsResourceWithFake(task, stepAction)
+
+	state := awsStateForRead(awsReadTestTaskName)
+	state.Namespace = types.StringValue("custom-ns")
+	state.StepActionName = types.StringValue("setup-credentials-" + awsReadTestTaskName)
+
+	_, remove, diags := r.readResourceState(context.Background(), c, state)
+	if remove {
+		t.Errorf("expected state retained for objects in custom namespace, got removeFromState=true")
+	}
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
+
+// TestAWSNamespaceOrDefault verifies state written before the namespace
+// attribute existed resolves to tekton-pipelines.
+func TestAWSNamespaceOrDefault(t *testing.T) {
+	if got := namespaceOrDefault(types.StringNull()); got != tektonPipelinesNamespace {
+		t.Errorf("null namespace = %q, want %q", got, tektonPipelinesNamespace)
+	}
+	if got := namespaceOrDefault(types.StringValue("custom-ns")); got != "custom-ns" {
+		t.Errorf("namespace = %q, want custom-ns", got)
+	}
+}
@@ -1,11 +1,14 @@
 package provider
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/facets-cloud/terraform-provider-facets/internal/aws"
 	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
 func TestGenerateAWSResourceNames(t *testing.T) {
@@ -142,7 +145,7 @@ func TestGenerateAssumeRoleScriptWithSourceProfile(t *testing.T) {
 		},
 	}
 
-	script := tekton.GenerateAssumeRoleScript(config)
+	script := tekton.GenerateAssumeRoleScript(config, "abc123")
 
 	// Validate script contains expected elements for source_profile approach
 	if !strings.Contains(script, "#!/bin/bash") {
@@ -212,7 +215,7 @@ func TestGenerateAssumeRoleScriptWithoutExternalID(t *testing.T) {
 		},
 	}
 
-	script := tekton.GenerateAssumeRoleScript(config)
+	script := tekton.GenerateAssumeRoleScript(config, "abc123")
 
 	// Should have role ARN
 	if !strings.Contains(script, "arn:aws:iam::123456789012:role/my-role") {
@@ -246,7 +249,7 @@ func TestGenerateAssumeRoleScriptWithSessionName(t *testing.T) {
 		},
 	}
 
-	script := tekton.GenerateAssumeRoleScript(config)
+	script := tekton.GenerateAssumeRoleScript(config, "abc123")
 
 	// Should have the explicit session name
 	if !strings.Contains(script, "role_session_name = my-custom-session") {
@@ -254,10 +257,59 @@ func TestGenerateAssumeRoleScriptWithSessionName(t *testing.T) {
 	}
 }
 
+// Test script without session name uses one derived from the task name
+func TestGenerateAssumeRoleScriptDefaultSessionName(t *testing.T) {
+	config := &aws.AWSAuthConfig{
+		Region: "us-east-1",
+		AssumeRoleConfig: &aws.AssumeRoleConfig{
+			RoleARN: "arn:aws:iam::123456789012:role/my-role",
+		},
+	}
+
+	script := tekton.GenerateAssumeRoleScript(config, "abc123")
+	if !strings.Contains(script, "role_session_name = terraform-abc123\n") {
+		t.Error("Script missing session name derived from the task name")
+	}
+	if script != tekton.GenerateAssumeRoleScript(config, "abc123") {
+		t.Error("Script should be the same every time it is generated")
+	}
+	if got := tekton.DefaultSessionName(strings.Repeat("a", 63)); len(got) != 64 {
+		t.Errorf("DefaultSessionName() = %q, want 64 characters", got)
+	}
+}
+
 // Test script generation returns empty for nil AssumeRoleConfig
 func TestGenerateScriptWithNilConfig(t *testing.T) {
-	assumeRoleScript := tekton.GenerateAssumeRoleScript(&aws.AWSAuthConfig{})
+	assumeRoleScript := tekton.GenerateAssumeRoleScript(&aws.AWSAuthConfig{}, "abc123")
 	if assumeRoleScript != "" {
 		t.Error("Expected empty script for nil AssumeRoleConfig")
 	}
 }
+
+// TestAWSNamespaceSchemaShape pins the AWS namespace attribute to the same
+// contract as the Kubernetes resource: Optional+Computed with RequiresReplace.
+func TestAWSNamespaceSchemaShape(t *testing.T) {
+	r := NewTektonActionAWSResource()
+	resp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Schema returned errors: %v", resp.Diagnostics)
+	}
+
+	attr, ok := resp.Schema.Attributes["namespace"].(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("namespace attribute should be schema.StringAttribute, got %T", resp.Schema.Attributes["namespace"])
+	}
+	if !attr.IsOptional() || !attr.IsComputed() {
+		t.Error("namespace should be Optional and Computed")
+	}
+	if len(attr.PlanModifiers) == 0 {
+		t.Error("namespace should have plan modifiers (RequiresReplace)")
+	}
+
+	for _, name := range []string{"labels", "annotations"} {
+		if _, ok := resp.Schema.Attributes[name].(schema.MapAttribute); !ok {
+			t.Errorf("%s attribute should be schema.MapAttribute, got %T", name, resp.Schema.Attributes[name])
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
 	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -20,44 +21,79 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 )
 
 var (
-	_ resource.Resource                = &TektonActionKubernetesResource{}
-	_ resource.ResourceWithConfigure   = &TektonActionKubernetesResource{}
-	_ resource.ResourceWithImportState = &TektonActionKubernetesResource{}
+	_ resource.Resource                     = &TektonActionKubernetesResource{}
+	_ resource.ResourceWithConfigure        = &TektonActionKubernetesResource{}
+	_ resource.ResourceWithImportState      = &TektonActionKubernetesResource{}
+	_ resource.ResourceWithConfigValidators = &TektonActionKubernetesResource{}
+	_ resource.ResourceWithModifyPlan       = &TektonActionKubernetesResource{}
+	_ resource.ResourceWithIdentity         = &TektonActionKubernetesResource{}
 )
 
 func NewTektonActionKubernetesResource() resource.Resource {
 	return &TektonActionKubernetesResource{
-		clientFactory: k8s.GetKubernetesClient,
+		clientFactory:    k8s.GetKubernetesClient,
+		discoveryFactory: k8s.GetDiscoveryClient,
 	}
 }
 
 type TektonActionKubernetesResource struct {
-	// No cached client - fresh client created per operation for thread safety.
-	//
-	// clientFactory produces a Kubernetes dynamic client. Defaults to
-	// k8s.GetKubernetesClient in production via NewTektonActionKubernetesResource.
+	providerData *FacetsProviderModel
+
+	// clientFactory produces a Kubernetes dynamic client. Configure replaces
+	// the k8s.GetKubernetesClient default set by NewTektonActionKubernetesResource
+	// with a lookup in the provider's shared, rate-limited client pool.
 	// Tests in the same package may override this field directly to inject a
 	// fake client. Do not access from outside the provider package.
 	clientFactory func() (dynamic.Interface, error)
+	// discoveryFactory produces the discovery client used by the preflight
+	// checks in Create, from the same pool as clientFactory
+	discoveryFactory func() (discovery.DiscoveryInterface, error)
 }
 
 type TektonActionKubernetesResourceModel struct {
-	ID                 types.String `tfsdk:"id"`
-	Name               types.String `tfsdk:"name"`
-	Description        types.String `tfsdk:"description"`
-	FacetsResourceName types.String `tfsdk:"facets_resource_name"`
-	FacetsEnvironment  types.Object `tfsdk:"facets_environment"`
-	FacetsResource     types.Object `tfsdk:"facets_resource"`
-	Namespace          types.String `tfsdk:"namespace"`
-	Labels             types.Map    `tfsdk:"labels"`
-	Steps              types.List   `tfsdk:"steps"`
-	Params             types.List   `tfsdk:"params"`
-	TaskName           types.String `tfsdk:"task_name"`
-	StepActionName     types.String `tfsdk:"step_action_name"`
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Description          types.String `tfsdk:"description"`
+	FacetsResourceName   types.String `tfsdk:"facets_resource_name"`
+	FacetsEnvironment    types.Object `tfsdk:"facets_environment"`
+	FacetsResource       types.Object `tfsdk:"facets_resource"`
+	Namespace            types.String `tfsdk:"namespace"`
+	Labels               types.Map    `tfsdk:"labels"`
+	Annotations          types.Map    `tfsdk:"annotations"`
+	Steps                types.List   `tfsdk:"steps"`
+	Params               types.List   `tfsdk:"params"`
+	CredentialStep       types.Object `tfsdk:"credential_step"`
+	RBAC                 types.Object `tfsdk:"rbac"`
+	KubeconfigDelivery   types.String `tfsdk:"kubeconfig_delivery"`
+	TaskName             types.String `tfsdk:"task_name"`
+	StepActionName       types.String `tfsdk:"step_action_name"`
+	ClusterID            types.String `tfsdk:"cluster_id"`
+	EffectiveLabels      types.Map    `tfsdk:"effective_labels"`
+	EffectiveAnnotations types.Map    `tfsdk:"effective_annotations"`
+	TaskManifest         types.String `tfsdk:"task_manifest"`
+	StepActionManifest   types.String `tfsdk:"step_action_manifest"`
+}
+
+// metadataInput returns the attributes that determine the action's labels and annotations
+func (m TektonActionKubernetesResourceModel) metadataInput() actionMetadataInput {
+	return actionMetadataInput{
+		Name:               m.Name,
+		FacetsResourceName: m.FacetsResourceName,
+		FacetsEnvironment:  m.FacetsEnvironment,
+		FacetsResource:     m.FacetsResource,
+		Labels:             m.Labels,
+		Annotations:        m.Annotations,
+		ClusterID:          m.ClusterID,
+		RBAC:               m.RBAC,
+		TaskName:           m.TaskName,
+		KubeconfigDelivery: m.KubeconfigDelivery,
+		IsCloudAction:      false,
+	}
 }
 
 func (r *TektonActionKubernetesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -128,6 +164,7 @@ func (r *TektonActionKubernetesResource) Schema(ctx context.Context, req resourc
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 				Validators: []validator.String{
@@ -146,6 +183,13 @@ func (r *TektonActionKubernetesResource) Schema(ctx context.Context, req resourc
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"annotations": schema.MapAttribute{
+				Description: "Custom annotations to apply to the Tekton Task and StepAction resources, " +
+					"e.g. for cost or ownership tooling. These are merged with the facets.cloud/* annotations " +
+					"that record the action's identity; the facets.cloud/* annotations take precedence.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
 			"steps": schema.ListNestedAttribute{
 				Description: "List of steps for the Tekton Task",
 				Required:    true,
@@ -206,6 +250,23 @@ func (r *TektonActionKubernetesResource) Schema(ctx context.Context, req resourc
 								},
 							},
 						},
+						"sensitive_env": schema.MapAttribute{
+							Description: "Environment variables with sensitive values, such as tokens. The values are " +
+								"stored in a Kubernetes Secret owned by this resource and referenced from the step " +
+								"with secretKeyRef, so they never appear in the Task. They are still stored in " +
+								"Terraform state, marked sensitive.",
+							Optional:    true,
+							Sensitive:   true,
+							ElementType: types.StringType,
+							Validators: []validator.Map{
+								mapvalidator.KeysAre(
+									stringvalidator.RegexMatches(
+										regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`),
+										"must be a valid environment variable name (uppercase letters, numbers, and underscores, cannot start with a number)",
+									),
+								),
+							},
+						},
 					},
 				},
 			},
@@ -232,6 +293,19 @@ func (r *TektonActionKubernetesResource) Schema(ctx context.Context, req resourc
 					},
 				},
 			},
+			"credential_step": credentialStepResourceAttribute(tekton.KubernetesCredentialStepTools),
+			"rbac":            rbacResourceAttribute(),
+			"kubeconfig_delivery": schema.StringAttribute{
+				Description: "How the Facets runner hands the user's kubeconfig to the credential step. " +
+					"\"param\" (default) passes it base64-encoded in the FACETS_USER_KUBECONFIG param, where anyone " +
+					"who can read the TaskRun can see it. \"secret_workspace\" makes the Task declare a read-only " +
+					"facets-user-kubeconfig workspace, which the runner binds to a short-lived Secret holding the " +
+					"kubeconfig under the key kubeconfig, and the FACETS_USER_KUBECONFIG param is no longer declared.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(tekton.KubeconfigDeliveryParam, tekton.KubeconfigDeliverySecretWorkspace),
+				},
+			},
 			"task_name": schema.StringAttribute{
 				Description: "Generated Tekton Task name (computed from hash of resource_name, environment, and name). " +
 					"This is the actual Kubernetes resource name and may be truncated to 63 characters.",
@@ -242,22 +316,135 @@ func (r *TektonActionKubernetesResource) Schema(ctx context.Context, req resourc
 					"This StepAction automatically configures Kubernetes access for the workflow steps.",
 				Computed: true,
 			},
+			"cluster_id": schema.StringAttribute{
+				Description: "Cluster identifier recorded in the cluster_id label and facets.cloud/cluster-id annotation. " +
+					"Resolved from the provider's cluster_id, derive_cluster_id or the CLUSTER_ID environment variable " +
+					"(in that order), defaulting to \"na\". A change shows up in the plan as an in-place update.",
+				Computed: true,
+			},
+			"effective_labels": schema.MapAttribute{
+				Description: "All labels applied to the Task and StepAction: the provider's default_labels, " +
+					"overridden by labels, overridden by the auto-generated labels.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"effective_annotations": schema.MapAttribute{
+				Description: "All annotations applied to the Task and StepAction: the provider's default_annotations, " +
+					"overridden by annotations, overridden by the facets.cloud/* annotations.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"task_manifest":        manifestResourceAttribute("Task"),
+			"step_action_manifest": manifestResourceAttribute("StepAction"),
 		},
 	}
 }
 
+// IdentitySchema identifies an action by the namespace and name of its Task,
+// so import blocks can name it with provider::facets::task_name
+func (r *TektonActionKubernetesResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = actionIdentitySchema()
+}
+
+// ConfigValidators rejects reserved and duplicate step, param and env var names
+// and invalid step compute resources
+func (r *TektonActionKubernetesResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		actionNamesValidator{reserved: tekton.KubernetesReservedNames()},
+		computeResourcesValidator{},
+	}
+}
+
+// ModifyPlan resolves cluster_id, the default namespace and the merged
+// effective_labels/effective_annotations from the provider configuration, so
+// changes to provider defaults show up in the plan instead of being applied
+// silently. It then renders the planned objects as task_manifest and
+// step_action_manifest and evaluates the provider's policies against them.
+func (r *TektonActionKubernetesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyActionPlan(ctx, req, resp, r.providerData, r.dynamicClient, false)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan TektonActionKubernetesResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(stateNames(ctx, req.State, &plan.TaskName, &plan.StepActionName)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	stepAction, task, diags := r.planObjects(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || task == nil {
+		return
+	}
+	resp.Diagnostics.Append(planManifests(ctx, &resp.Plan, stepAction, task)...)
+	if r.providerData.hasPolicies() {
+		resp.Diagnostics.Append(evaluatePolicies(ctx, r.providerData, actionTypeKubernetes, stepAction, task, false)...)
+	}
+}
+
+// planObjects renders the planned StepAction and Task. Both are nil while
+// anything the objects depend on is unknown; Create and Update render them
+// again.
+func (r *TektonActionKubernetesResource) planObjects(ctx context.Context, plan TektonActionKubernetesResourceModel) (stepAction, task *unstructured.Unstructured, diags diag.Diagnostics) {
+	if !valuesKnown(ctx, plan.Description, plan.Namespace, plan.Steps, plan.Params, plan.KubeconfigDelivery) {
+		return nil, nil, diags
+	}
+	policy, known := r.providerData.stepPolicy(ctx)
+	if !known {
+		return nil, nil, diags
+	}
+	credentialStep, known, d := r.providerData.credentialStep(ctx, plan.CredentialStep)
+	diags.Append(d...)
+	if diags.HasError() || !known {
+		return nil, nil, diags
+	}
+	metadata, known, d := buildActionMetadata(ctx, plan.metadataInput(), r.providerData)
+	diags.Append(d...)
+	if diags.HasError() || !known {
+		return nil, nil, diags
+	}
+	if plan.TaskName.IsNull() || plan.TaskName.IsUnknown() {
+		names, d := plannedNames(ctx, plan.metadataInput())
+		diags.Append(d...)
+		if diags.HasError() {
+			return nil, nil, diags
+		}
+		plan.TaskName = types.StringValue(names.TaskName)
+		plan.StepActionName = types.StringValue(names.StepActionName)
+	}
+
+	stepAction, task, _ = r.renderObjects(ctx, plan, policy, credentialStep, metadata)
+	return stepAction, task, diags
+}
+
 func (r *TektonActionKubernetesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Client will be created lazily when needed during CRUD operations.
 	// This allows terraform validate to pass without requiring a kubeconfig.
+	if req.ProviderData != nil {
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Provider Data Type",
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
+			)
+			return
+		}
+		r.providerData = providerData.Model
+		r.clientFactory = kubernetesClientFactory(providerData)
+		r.discoveryFactory = kubernetesDiscoveryFactory(providerData)
+	}
 }
 
-// getClient returns a fresh Kubernetes client and operations for each call.
-// This pattern matches terraform-provider-helm best practices - no cached state,
-// thread-safe, and avoids stale client issues.
+// getClient returns a Kubernetes client and operations for each call.
+// The client comes from the provider's client pool, which is keyed by the
+// effective connection config and safe for concurrent use, so resources
+// targeting the same cluster share one transport and rate limiter.
 //
-// In production, clientFactory is k8s.GetKubernetesClient (set by
-// NewTektonActionKubernetesResource). In tests, the field can be overridden
-// to inject a fake dynamic.Interface for unit-testing CRUD lifecycle paths.
+// In production, clientFactory is built from the provider data in Configure.
+// Without provider data it stays k8s.GetKubernetesClient (set by
+// NewTektonActionKubernetesResource). In tests, the field can be overridden to
+// inject a fake dynamic.Interface for unit-testing CRUD lifecycle paths.
 func (r *TektonActionKubernetesResource) getClient() (dynamic.Interface, *tekton.ResourceOperations, error) {
 	factory := r.clientFactory
 	if factory == nil {
@@ -273,6 +460,12 @@ func (r *TektonActionKubernetesResource) getClient() (dynamic.Interface, *tekton
 	return client, tekton.NewResourceOperations(client), nil
 }
 
+// dynamicClient returns a fresh Kubernetes client without the operations wrapper
+func (r *TektonActionKubernetesResource) dynamicClient() (dynamic.Interface, error) {
+	client, _, err := r.getClient()
+	return client, err
+}
+
 func (r *TektonActionKubernetesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan TektonActionKubernetesResourceModel
 
@@ -282,7 +475,7 @@ func (r *TektonActionKubernetesResource) Create(ctx context.Context, req resourc
 	}
 
 	// Create fresh client for this operation
-	_, operations, err := r.getClient()
+	client, operations, err := r.getClient()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Kubernetes Client",
@@ -293,7 +486,17 @@ func (r *TektonActionKubernetesResource) Create(ctx context.Context, req resourc
 
 	// Set defaults
 	if plan.Namespace.IsNull() || plan.Namespace.ValueString() == "" {
-		plan.Namespace = types.StringValue("tekton-pipelines")
+		plan.Namespace = types.StringValue(r.providerData.defaultNamespace())
+	}
+
+	// Check the cluster prerequisites before the first write, so a missing
+	// Tekton installation or namespace is reported as such. Render mode has no
+	// cluster to check.
+	if !r.providerData.renderMode() {
+		resp.Diagnostics.Append(preflightAction(ctx, r.providerData, r.discoveryFactory, client, plan.Namespace.ValueString(), plan.RBAC)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	// Extract environment unique_name from environment object
@@ -303,13 +506,6 @@ func (r *TektonActionKubernetesResource) Create(ctx context.Context, req resourc
 		return
 	}
 
-	// Extract resource_kind from facets_resource object
-	var facetsRes tekton.FacetsResourceModel
-	resp.Diagnostics.Append(plan.FacetsResource.As(ctx, &facetsRes, basetypes.ObjectAsOptions{})...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
 	// Generate names using hash for uniqueness
 	names := tekton.GenerateNames(
 		plan.FacetsResourceName.ValueString(),
@@ -320,47 +516,82 @@ func (r *TektonActionKubernetesResource) Create(ctx context.Context, req resourc
 	plan.StepActionName = types.StringValue(names.StepActionName)
 	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", plan.Namespace.ValueString(), names.TaskName))
 
-	// Extract custom labels
-	customLabels := make(map[string]string)
-	if !plan.Labels.IsNull() {
-		resp.Diagnostics.Append(plan.Labels.ElementsAs(ctx, &customLabels, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	// Cluster ID is resolved during plan (ModifyPlan); only resolve again if it was unknown then
+	clusterID, err := plannedClusterID(ctx, plan.ClusterID, r.providerData, r.dynamicClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Cluster ID",
+			err.Error(),
+		)
+		return
 	}
+	plan.ClusterID = types.StringValue(clusterID)
 
-	// Create metadata
-	metadata := tekton.NewResourceMetadata(
-		plan.Name.ValueString(),
-		plan.FacetsResourceName.ValueString(),
-		facetsRes.Kind.ValueString(),
-		facetsEnv.UniqueName.ValueString(),
-		false, // cloud_action: false for Kubernetes actions
-		customLabels,
-	)
+	// Merge provider defaults, custom labels/annotations and auto-generated metadata
+	metadata, known, diags := buildActionMetadata(ctx, plan.metadataInput(), r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !known {
+		resp.Diagnostics.AddError(
+			"Unable to Build Metadata",
+			"Labels, annotations or identity attributes are still unknown during apply.",
+		)
+		return
+	}
+	plan.EffectiveLabels, diags = stringMapValue(ctx, metadata.Labels())
+	resp.Diagnostics.Append(diags...)
+	plan.EffectiveAnnotations, diags = stringMapValue(ctx, metadata.Annotations())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Build StepAction
-	stepAction := tekton.BuildKubernetesStepAction(
-		plan.StepActionName.ValueString(),
-		plan.Namespace.ValueString(),
-		metadata.LabelsAsInterface(),
-	)
+	// Enforce the provider's step policy; steps without resources get its defaults
+	policy, diags := applyStepPolicy(ctx, plan.Steps, r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resolve the credential step image and pull policy
+	credentialStep, diags := applyCredentialStep(ctx, plan.CredentialStep, r.providerData, policy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Build StepAction and Task, then check them against the provider's policies
+	stepAction, task, secret := r.renderObjects(ctx, plan, policy, credentialStep, metadata)
+	resp.Diagnostics.Append(evaluatePolicies(ctx, r.providerData, actionTypeKubernetes, stepAction, task, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.StepActionManifest, plan.TaskManifest, diags = actionManifests(stepAction, task)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Build Task
-	task := r.buildTask(ctx, plan, metadata.LabelsAsInterface())
+	// Build the action's own ServiceAccount, Role and binding, when it has rbac
+	rbacObjects, diags := buildRBACObjects(ctx, plan.RBAC, plan.Namespace.ValueString(), plan.TaskName.ValueString(), metadata)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(r.createResources(ctx, operations, stepAction, task)...)
+	resp.Diagnostics.Append(r.createResources(ctx, operations, stepAction, task, secret, rbacObjects)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, plan.Namespace, plan.TaskName)...)
 }
 
-// createResources creates the StepAction and Task in cluster. Returns
+// createResources creates the sensitive env Secret and the rbac objects (when
+// there are any), the StepAction and the Task in cluster. Returns
 // diagnostics. Extracted from Create so unit tests can exercise the
 // orphan-on-Task-fail path with a fake dynamic client.
 //
@@ -369,9 +600,32 @@ func (r *TektonActionKubernetesResource) Create(ctx context.Context, req resourc
 // (which is idempotent on NotFound per fix #11). If rollback itself fails,
 // a warning is surfaced alongside the original Task-create error so the
 // operator knows manual cleanup may be required.
-func (r *TektonActionKubernetesResource) createResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task *unstructured.Unstructured) diag.Diagnostics {
+func (r *TektonActionKubernetesResource) createResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task, secret *unstructured.Unstructured, rbac []tekton.RBACObject) diag.Diagnostics {
 	var diags diag.Diagnostics
+	// The sensitive env Secret comes first, so the Task never references a
+	// Secret that does not exist
+	if secret != nil {
+		if err := applyEnvSecret(ctx, operations, secret); err != nil {
+			diags.AddError(
+				"Error creating Secret",
+				fmt.Sprintf("Could not create sensitive env Secret: %s", err.Error()),
+			)
+			return diags
+		}
+	}
+	// The ServiceAccount the Task is annotated with exists before the Task
+	if err := applyRBAC(ctx, operations, rbac); err != nil {
+		diags.Append(rollbackRBAC(ctx, operations, rbac)...)
+		diags.Append(rollbackEnvSecret(ctx, operations, secret)...)
+		diags.AddError(
+			"Error creating RBAC objects",
+			fmt.Sprintf("Could not create the action's ServiceAccount, Role and binding: %s", err.Error()),
+		)
+		return diags
+	}
 	if err := operations.CreateResource(ctx, stepAction, "tekton.dev", "v1beta1", "stepactions"); err != nil {
+		diags.Append(rollbackRBAC(ctx, operations, rbac)...)
+		diags.Append(rollbackEnvSecret(ctx, operations, secret)...)
 		diags.AddError(
 			"Error creating StepAction",
 			fmt.Sprintf("Could not create StepAction: %s", err.Error()),
@@ -394,6 +648,8 @@ func (r *TektonActionKubernetesResource) createResources(ctx context.Context, op
 					stepAction.GetNamespace(), stepAction.GetName(), rollbackErr.Error()),
 			)
 		}
+		diags.Append(rollbackRBAC(ctx, operations, rbac)...)
+		diags.Append(rollbackEnvSecret(ctx, operations, secret)...)
 		diags.AddError(
 			"Error creating Task",
 			fmt.Sprintf("Could not create Task: %s", err.Error()),
@@ -410,6 +666,9 @@ func (r *TektonActionKubernetesResource) Read(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// Set the identity up front so state written before identity support
+	// gets one even when the action is removed below
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, state.Namespace, state.TaskName)...)
 
 	// Create fresh client for this operation
 	client, _, err := r.getClient()
@@ -421,7 +680,7 @@ func (r *TektonActionKubernetesResource) Read(ctx context.Context, req resource.
 		return
 	}
 
-	remove, diags := r.readResourceState(ctx, client, state)
+	task, remove, diags := r.readResourceState(ctx, client, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -431,6 +690,30 @@ func (r *TektonActionKubernetesResource) Read(ctx context.Context, req resource.
 		return
 	}
 
+	if task != nil {
+		// Backfill the cluster ID for state written before the attribute existed,
+		// so upgrading does not plan a spurious cluster_id change
+		if state.ClusterID.IsNull() {
+			state.ClusterID = clusterIDFromTask(task)
+		}
+
+		// Refresh the labels and annotations actually set on the Task, so
+		// out-of-band changes show up as drift against effective_labels
+		state.EffectiveLabels, state.EffectiveAnnotations, diags = effectiveMetadataFromObject(ctx, task)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Clear sensitive_env values the Secret no longer holds, so the next
+		// plan writes them again
+		state.Steps, diags = refreshSensitiveEnv(ctx, client, state.Namespace.ValueString(), state.TaskName.ValueString(), state.Steps)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -438,6 +721,9 @@ func (r *TektonActionKubernetesResource) Read(ctx context.Context, req resource.
 // Action resource. Returns whether Read should clear state from the response,
 // and any diagnostics to surface to the operator.
 //
+// The Task is returned when it exists so Read can refresh the metadata it
+// records without fetching it again.
+//
 // Extracted from Read to enable unit testing against a fake dynamic.Interface
 // without constructing tfsdk.State / tfsdk.ReadRequest plumbing.
 //
@@ -446,21 +732,22 @@ func (r *TektonActionKubernetesResource) Read(ctx context.Context, req resource.
 // RBAC, timeout, context cancellation) surface as diagnostics and retain
 // state. Both Task and StepAction are checked; asymmetric in-cluster drift
 // (one present, one missing) surfaces a warning and retains state.
-func (r *TektonActionKubernetesResource) readResourceState(ctx context.Context, client dynamic.Interface, state TektonActionKubernetesResourceModel) (removeFromState bool, diags diag.Diagnostics) {
+func (r *TektonActionKubernetesResource) readResourceState(ctx context.Context, client dynamic.Interface, state TektonActionKubernetesResourceModel) (task *unstructured.Unstructured, removeFromState bool, diags diag.Diagnostics) {
 	taskGVR := k8sschema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "tasks"}
 	stepActionGVR := k8sschema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "stepactions"}
 
 	taskExists := true
-	_, err := client.Resource(taskGVR).Namespace(state.Namespace.ValueString()).Get(ctx, state.TaskName.ValueString(), metav1.GetOptions{})
+	task, err := client.Resource(taskGVR).Namespace(state.Namespace.ValueString()).Get(ctx, state.TaskName.ValueString(), metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			task = nil
 			taskExists = false
 		} else {
 			diags.AddError(
 				"Error reading Task",
 				fmt.Sprintf("Could not read Task %s/%s: %s", state.Namespace.ValueString(), state.TaskName.ValueString(), err.Error()),
 			)
-			return false, diags
+			return task, false, diags
 		}
 	}
 
@@ -474,14 +761,14 @@ func (r *TektonActionKubernetesResource) readResourceState(ctx context.Context,
 				"Error reading StepAction",
 				fmt.Sprintf("Could not read StepAction %s/%s: %s", state.Namespace.ValueString(), state.StepActionName.ValueString(), err.Error()),
 			)
-			return false, diags
+			return task, false, diags
 		}
 	}
 
 	switch {
 	case !taskExists && !stepActionExists:
 		// Both genuinely deleted — clean removal from state.
-		return true, diags
+		return nil, true, diags
 	case taskExists != stepActionExists:
 		// Asymmetric drift — refuse to silently mutate state.
 		diags.AddWarning(
@@ -494,10 +781,10 @@ func (r *TektonActionKubernetesResource) readResourceState(ctx context.Context,
 				state.ID.ValueString(), taskExists, stepActionExists,
 			),
 		)
-		return false, diags
+		return task, false, diags
 	default:
 		// Both present and healthy.
-		return false, diags
+		return task, false, diags
 	}
 }
 
@@ -528,57 +815,86 @@ func (r *TektonActionKubernetesResource) Update(ctx context.Context, req resourc
 	plan.TaskName = state.TaskName
 	plan.ID = state.ID
 
-	// Extract environment unique_name from environment object
-	var facetsEnv tekton.FacetsEnvironmentModel
-	resp.Diagnostics.Append(plan.FacetsEnvironment.As(ctx, &facetsEnv, basetypes.ObjectAsOptions{})...)
+	// Cluster ID is resolved during plan (ModifyPlan); only resolve again if it was unknown then
+	clusterID, err := plannedClusterID(ctx, plan.ClusterID, r.providerData, r.dynamicClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Cluster ID",
+			err.Error(),
+		)
+		return
+	}
+	plan.ClusterID = types.StringValue(clusterID)
+
+	// Merge provider defaults, custom labels/annotations and auto-generated metadata
+	metadata, known, diags := buildActionMetadata(ctx, plan.metadataInput(), r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !known {
+		resp.Diagnostics.AddError(
+			"Unable to Build Metadata",
+			"Labels, annotations or identity attributes are still unknown during apply.",
+		)
+		return
+	}
+	plan.EffectiveLabels, diags = stringMapValue(ctx, metadata.Labels())
+	resp.Diagnostics.Append(diags...)
+	plan.EffectiveAnnotations, diags = stringMapValue(ctx, metadata.Annotations())
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Extract resource_kind from facets_resource object
-	var facetsRes tekton.FacetsResourceModel
-	resp.Diagnostics.Append(plan.FacetsResource.As(ctx, &facetsRes, basetypes.ObjectAsOptions{})...)
+	// Enforce the provider's step policy; steps without resources get its defaults
+	policy, diags := applyStepPolicy(ctx, plan.Steps, r.providerData)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Extract custom labels
-	customLabels := make(map[string]string)
-	if !plan.Labels.IsNull() {
-		resp.Diagnostics.Append(plan.Labels.ElementsAs(ctx, &customLabels, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	// Resolve the credential step image and pull policy
+	credentialStep, diags := applyCredentialStep(ctx, plan.CredentialStep, r.providerData, policy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Create metadata
-	metadata := tekton.NewResourceMetadata(
-		plan.Name.ValueString(),
-		plan.FacetsResourceName.ValueString(),
-		facetsRes.Kind.ValueString(),
-		facetsEnv.UniqueName.ValueString(),
-		false, // cloud_action: false for Kubernetes actions
-		customLabels,
-	)
+	// Build StepAction and Task, then check them against the provider's policies
+	stepAction, task, secret := r.renderObjects(ctx, plan, policy, credentialStep, metadata)
+	resp.Diagnostics.Append(evaluatePolicies(ctx, r.providerData, actionTypeKubernetes, stepAction, task, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.StepActionManifest, plan.TaskManifest, diags = actionManifests(stepAction, task)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Build StepAction and Task
-	stepAction := tekton.BuildKubernetesStepAction(
-		plan.StepActionName.ValueString(),
-		plan.Namespace.ValueString(),
-		metadata.LabelsAsInterface(),
-	)
-	task := r.buildTask(ctx, plan, metadata.LabelsAsInterface())
+	// Build the action's own ServiceAccount, Role and binding, and find the
+	// objects of the previous rbac that are no longer needed
+	rbacObjects, diags := buildRBACObjects(ctx, plan.RBAC, plan.Namespace.ValueString(), plan.TaskName.ValueString(), metadata)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	staleRBAC := tekton.StaleRBAC(rbacRefs(ctx, state.RBAC, state.Namespace.ValueString(), state.TaskName.ValueString()), rbacObjects)
 
-	resp.Diagnostics.Append(r.updateResources(ctx, operations, stepAction, task)...)
+	resp.Diagnostics.Append(r.updateResources(ctx, operations, stepAction, task, secret, hasSensitiveEnv(ctx, state.Steps), rbacObjects, staleRBAC)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, plan.Namespace, plan.TaskName)...)
 }
 
-// updateResources updates the Task and StepAction in cluster. Returns
-// diagnostics. Extracted from Update so unit tests can exercise the
+// updateResources updates the sensitive env Secret, the rbac objects, the
+// Task and the StepAction in cluster. It then deletes the Secret when
+// removeSecret is set and no step uses sensitive_env anymore, and the
+// staleRBAC objects. Returns diagnostics. Extracted from Update so unit tests can exercise the
 // ordering invariant with a fake dynamic client.
 //
 // Task-first ordering rationale: updating Task before StepAction ensures that
@@ -588,8 +904,26 @@ func (r *TektonActionKubernetesResource) Update(ctx context.Context, req resourc
 // functional because the Task references the StepAction by immutable ref.name;
 // the old StepAction spec still resolves. The operator re-runs to retry the
 // StepAction update only.
-func (r *TektonActionKubernetesResource) updateResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task *unstructured.Unstructured) diag.Diagnostics {
+func (r *TektonActionKubernetesResource) updateResources(ctx context.Context, operations *tekton.ResourceOperations, stepAction, task, secret *unstructured.Unstructured, removeSecret bool, rbac, staleRBAC []tekton.RBACObject) diag.Diagnostics {
 	var diags diag.Diagnostics
+	// Write the sensitive env Secret before the Task that references its keys
+	if secret != nil {
+		if err := applyEnvSecret(ctx, operations, secret); err != nil {
+			diags.AddError(
+				"Error updating Secret",
+				fmt.Sprintf("Could not update sensitive env Secret: %s", err.Error()),
+			)
+			return diags
+		}
+	}
+	// Write the ServiceAccount and its Role before the Task that names it
+	if err := applyRBAC(ctx, operations, rbac); err != nil {
+		diags.AddError(
+			"Error updating RBAC objects",
+			fmt.Sprintf("Could not update the action's ServiceAccount, Role and binding: %s", err.Error()),
+		)
+		return diags
+	}
 	// Update Task FIRST. If it fails (validation, RBAC, webhook), the StepAction
 	// is never touched and the cluster remains in a coherent pre-Update state.
 	if err := operations.UpdateResource(ctx, task, "tekton.dev", "v1beta1", "tasks"); err != nil {
@@ -611,6 +945,22 @@ func (r *TektonActionKubernetesResource) updateResources(ctx context.Context, op
 		)
 		return diags
 	}
+	// The Task no longer references the Secret once no step sets sensitive_env
+	if secret == nil && removeSecret {
+		if err := deleteEnvSecret(ctx, operations, task.GetNamespace(), task.GetName()); err != nil {
+			diags.AddError(
+				"Error deleting Secret",
+				fmt.Sprintf("Task updated successfully but the sensitive env Secret could not be deleted: %s", err.Error()),
+			)
+		}
+	}
+	// The Task no longer names the ServiceAccount, or the scope changed
+	if err := deleteRBAC(ctx, operations, staleRBAC); err != nil {
+		diags.AddError(
+			"Error deleting RBAC objects",
+			fmt.Sprintf("Task updated successfully but the action's previous RBAC objects could not be deleted: %s", err.Error()),
+		)
+	}
 	return diags
 }
 
@@ -632,15 +982,16 @@ func (r *TektonActionKubernetesResource) Delete(ctx context.Context, req resourc
 		return
 	}
 
-	resp.Diagnostics.Append(r.deleteResources(ctx, operations, state.Namespace.ValueString(), state.TaskName.ValueString(), state.StepActionName.ValueString())...)
+	resp.Diagnostics.Append(r.deleteResources(ctx, operations, state.Namespace.ValueString(), state.TaskName.ValueString(), state.StepActionName.ValueString(), hasSensitiveEnv(ctx, state.Steps),
+		rbacRefs(ctx, state.RBAC, state.Namespace.ValueString(), state.TaskName.ValueString()))...)
 }
 
-// deleteResources attempts to delete both the Task and the StepAction, using
-// best-effort semantics — if one fails, the other is still attempted, and both
-// errors are aggregated into the returned diagnostics. Combined with the
-// idempotent DeleteResource (which treats NotFound as success), this means
-// destroy retries are safe.
-func (r *TektonActionKubernetesResource) deleteResources(ctx context.Context, operations *tekton.ResourceOperations, namespace, taskName, stepActionName string) diag.Diagnostics {
+// deleteResources attempts to delete the Task, the StepAction, the rbac
+// objects and, when hasSecret is set, the sensitive env Secret, using
+// best-effort semantics — if one fails, the others are still attempted, and
+// all errors are aggregated into the returned diagnostics. Combined with the idempotent DeleteResource
+// (which treats NotFound as success), this means destroy retries are safe.
+func (r *TektonActionKubernetesResource) deleteResources(ctx context.Context, operations *tekton.ResourceOperations, namespace, taskName, stepActionName string, hasSecret bool, rbac []tekton.RBACObject) diag.Diagnostics {
 	var diags diag.Diagnostics
 	taskErr := operations.DeleteResource(ctx, namespace, taskName, "tekton.dev", "v1beta1", "tasks")
 	stepActionErr := operations.DeleteResource(ctx, namespace, stepActionName, "tekton.dev", "v1beta1", "stepactions")
@@ -656,24 +1007,35 @@ func (r *TektonActionKubernetesResource) deleteResources(ctx context.Context, op
 			fmt.Sprintf("Could not delete StepAction: %s", stepActionErr.Error()),
 		)
 	}
+	if hasSecret {
+		if err := deleteEnvSecret(ctx, operations, namespace, taskName); err != nil {
+			diags.AddError(
+				"Error deleting Secret",
+				fmt.Sprintf("Could not delete sensitive env Secret: %s", err.Error()),
+			)
+		}
+	}
+	if err := deleteRBAC(ctx, operations, rbac); err != nil {
+		diags.AddError(
+			"Error deleting RBAC objects",
+			fmt.Sprintf("Could not delete the action's ServiceAccount, Role and binding: %s", err.Error()),
+		)
+	}
 	return diags
 }
 
 func (r *TektonActionKubernetesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: namespace/taskName
+	// Import by identity, or by an ID of the form [namespace/]task_name or
+	// [namespace/]environment_unique_name:facets_resource_name:name
 	// Example: tekton-pipelines/59f6f855860ddc99a32e2944c96db5fa
-
-	idParts := regexp.MustCompile(`^([^/]+)/([^/]+)$`).FindStringSubmatch(req.ID)
-	if len(idParts) != 3 {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import ID in format: namespace/taskName, got: %s", req.ID),
-		)
+	// Example: production:my-app:Restart API pods
+	target, diags := importActionTarget(ctx, req, r.providerData.defaultNamespace())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	namespace := idParts[1]
-	taskName := idParts[2]
+	namespace := target.Namespace
+	taskName := target.TaskName
 
 	// Create fresh client for this operation
 	client, _, err := r.getClient()
@@ -685,105 +1047,126 @@ func (r *TektonActionKubernetesResource) ImportState(ctx context.Context, req re
 		return
 	}
 
-	// Verify Task exists
-	gvr := k8sschema.GroupVersionResource{
-		Group:    "tekton.dev",
-		Version:  "v1beta1",
-		Resource: "tasks",
-	}
-
-	task, err := client.Resource(gvr).Namespace(namespace).Get(ctx, taskName, metav1.GetOptions{})
+	task, err := client.Resource(taskGVR).Namespace(namespace).Get(ctx, taskName, metav1.GetOptions{})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error importing resource",
-			fmt.Sprintf("Could not find Task %s/%s: %s", namespace, taskName, err.Error()),
+			fmt.Sprintf("Could not find %s: %s", target, err.Error()),
 		)
 		return
 	}
 
-	// Extract metadata from labels
-	labels, found, _ := unstructured.NestedStringMap(task.Object, "metadata", "labels")
-	if !found {
-		resp.Diagnostics.AddError(
-			"Error importing resource",
-			"Task does not have required labels for import",
-		)
+	// Parse the configuration back from the Task, its StepAction and env Secret
+	imported, diags := importAction(ctx, client, task, tekton.KubernetesReservedNames(), r.providerData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	displayName, hasDisplayName := labels["display_name"]
-	resourceName, hasResourceName := labels["resource_name"]
-	_, hasResourceKind := labels["resource_kind"]
-	_, hasEnvUniqueName := labels["environment_unique_name"]
-
-	if !hasDisplayName || !hasResourceName || !hasResourceKind || !hasEnvUniqueName {
-		resp.Diagnostics.AddError(
-			"Error importing resource",
-			"Task missing required labels: display_name, resource_name, resource_kind, environment_unique_name",
-		)
+	resp.Diagnostics.Append(target.check(imported)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Find StepAction (convention: setup-credentials-{hash})
-	stepActionName := fmt.Sprintf("setup-credentials-%s", taskName)
-
 	// Set state with imported values
 	state := TektonActionKubernetesResourceModel{
 		ID:                 types.StringValue(fmt.Sprintf("%s/%s", namespace, taskName)),
-		Name:               types.StringValue(displayName),
-		FacetsResourceName: types.StringValue(resourceName),
+		Name:               imported.Name,
+		Description:        imported.Description,
+		FacetsResourceName: imported.FacetsResourceName,
+		FacetsEnvironment:  imported.FacetsEnvironment,
+		FacetsResource:     imported.FacetsResource,
 		Namespace:          types.StringValue(namespace),
+		Labels:             imported.Labels,
+		Annotations:        imported.Annotations,
+		Steps:              imported.Steps,
+		Params:             imported.Params,
 		TaskName:           types.StringValue(taskName),
-		StepActionName:     types.StringValue(stepActionName),
+		StepActionName:     imported.StepActionName,
+		ClusterID:          imported.ClusterID,
+		CredentialStep:     imported.CredentialStep,
+		RBAC:               types.ObjectNull(actionRBACAttrTypes),
+		KubeconfigDelivery: types.StringNull(),
 	}
 
-	// Note: We cannot fully reconstruct facets_environment, facets_resource, steps, params from the Task
-	// User will need to manually specify these in their configuration
-	resp.Diagnostics.AddWarning(
-		"Partial Import",
-		"Only basic fields were imported. You must manually specify: facets_environment, facets_resource, steps, and params in your configuration.",
-	)
+	effectiveLabels, effectiveAnnotations, diags := effectiveMetadataFromObject(ctx, task)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.EffectiveLabels = effectiveLabels
+	state.EffectiveAnnotations = effectiveAnnotations
+	if task.GetAnnotations()[tekton.AnnotationKubeconfigDelivery] == tekton.KubeconfigDeliverySecretWorkspace {
+		state.KubeconfigDelivery = types.StringValue(tekton.KubeconfigDeliverySecretWorkspace)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setActionIdentity(ctx, resp.Identity, state.Namespace, state.TaskName)...)
+}
+
+// renderObjects builds the StepAction and Task for a plan whose names,
+// namespace and metadata are resolved
+func (r *TektonActionKubernetesResource) renderObjects(ctx context.Context, plan TektonActionKubernetesResourceModel, policy tekton.StepPolicy, credentialStep tekton.CredentialStep, metadata *tekton.ResourceMetadata) (stepAction, task, secret *unstructured.Unstructured) {
+	stepAction = tekton.BuildKubernetesStepAction(
+		plan.StepActionName.ValueString(),
+		plan.Namespace.ValueString(),
+		metadata.LabelsAsInterface(),
+		metadata.AnnotationsAsInterface(),
+		credentialStep,
+		plan.KubeconfigDelivery.ValueString(),
+	)
+	task = r.buildTask(ctx, plan, policy, credentialStep, metadata.LabelsAsInterface(), metadata.AnnotationsAsInterface())
+	secret = buildEnvSecret(ctx, plan.Steps, plan.Namespace.ValueString(), plan.TaskName.ValueString(), metadata)
+	return stepAction, task, secret
 }
 
 // buildTask creates the Tekton Task for Kubernetes workflows
-func (r *TektonActionKubernetesResource) buildTask(ctx context.Context, plan TektonActionKubernetesResourceModel, labels map[string]interface{}) *unstructured.Unstructured {
+func (r *TektonActionKubernetesResource) buildTask(ctx context.Context, plan TektonActionKubernetesResourceModel, policy tekton.StepPolicy, credentialStep tekton.CredentialStep, labels, annotations map[string]interface{}) *unstructured.Unstructured {
 	// Build steps
 	var steps []tekton.StepModel
 	plan.Steps.ElementsAs(ctx, &steps, false)
 
-	tektonSteps := []interface{}{
-		map[string]interface{}{
-			"name": "setup-credentials",
-			"ref": map[string]interface{}{
-				"name": plan.StepActionName.ValueString(),
-			},
-			"params": []interface{}{
-				map[string]interface{}{
-					"name":  "FACETS_USER_KUBECONFIG",
-					"value": "$(params.FACETS_USER_KUBECONFIG)",
-				},
-			},
+	// The kubeconfig reaches the credential step through the
+	// FACETS_USER_KUBECONFIG param, or through a workspace bound to a Secret
+	secretWorkspace := plan.KubeconfigDelivery.ValueString() == tekton.KubeconfigDeliverySecretWorkspace
+	kubeconfigParam := map[string]interface{}{
+		"name":  tekton.ParamFacetsUserKubeconfig,
+		"value": "$(params." + tekton.ParamFacetsUserKubeconfig + ")",
+	}
+	if secretWorkspace {
+		kubeconfigParam = map[string]interface{}{
+			"name":  tekton.ParamFacetsUserKubeconfigPath,
+			"value": tekton.KubeconfigWorkspacePath(),
+		}
+	}
+	setupStep := map[string]interface{}{
+		"name": tekton.SetupCredentialsStepName,
+		"ref": map[string]interface{}{
+			"name": plan.StepActionName.ValueString(),
 		},
+		"params": []interface{}{kubeconfigParam},
 	}
+	credentialStep.ApplyTo(setupStep)
+	tektonSteps := []interface{}{setupStep}
 
 	for _, step := range steps {
-		tektonStep := tekton.BuildStepWithResources(ctx, step)
-		tekton.AddEnvVar(tektonStep, "KUBECONFIG", "/workspace/.kube/config")
+		tektonStep := tekton.BuildStepWithResources(ctx, step, policy)
+		tekton.AddSensitiveEnv(tektonStep, step, tekton.EnvSecretName(plan.TaskName.ValueString()))
+		tekton.AddEnvVar(tektonStep, tekton.EnvKubeconfig, tekton.KubeconfigPath)
 		tektonSteps = append(tektonSteps, tektonStep)
 	}
 
 	// Build params
 	taskParams := []interface{}{
 		map[string]interface{}{
-			"name": "FACETS_USER_EMAIL",
+			"name": tekton.ParamFacetsUserEmail,
 			"type": "string",
 		},
-		map[string]interface{}{
-			"name": "FACETS_USER_KUBECONFIG",
+	}
+	if !secretWorkspace {
+		taskParams = append(taskParams, map[string]interface{}{
+			"name": tekton.ParamFacetsUserKubeconfig,
 			"type": "string",
-		},
+		})
 	}
 
 	// Add user-defined params
@@ -803,5 +1186,7 @@ func (r *TektonActionKubernetesResource) buildTask(ctx context.Context, plan Tek
 		Namespace:   plan.Namespace.ValueString(),
 		Description: plan.Description.ValueString(),
 		Labels:      labels,
+		Annotations: annotations,
+		Workspaces:  tekton.KubeconfigWorkspaces(plan.KubeconfigDelivery.ValueString()),
 	}, tektonSteps, taskParams)
 }
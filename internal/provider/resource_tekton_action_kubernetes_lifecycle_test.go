@@ -37,7 +37,7 @@ func TestK8sCreate_TaskFails_RollsBackStepAction(t *testing.T) {
 	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
 	ops := tekton.NewResourceOperations(c)
 
-	diags := r.createResources(context.Background(), ops, stepAction, task)
+	diags := r.createResources(context.Background(), ops, stepAction, task, nil, nil)
 	if !diags.HasError() {
 		t.Fatal("expected Task-create error to surface in diagnostics")
 	}
@@ -66,7 +66,7 @@ func TestK8sCreate_StepActionFails_NoOrphan(t *testing.T) {
 	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
 	ops := tekton.NewResourceOperations(c)
 
-	diags := r.createResources(context.Background(), ops, stepAction, task)
+	diags := r.createResources(context.Background(), ops, stepAction, task, nil, nil)
 	if !diags.HasError() {
 		t.Fatal("expected StepAction-create error to surface")
 	}
@@ -97,7 +97,7 @@ func TestK8sReadResourceState_StepActionMissing_AsymmetricDriftSurfaced(t *testi
 	r, c := resourceWithFake(task)
 
 	state := stateForRead(k8sReadTestNamespace, k8sReadTestTaskName)
-	remove, diags := r.readResourceState(context.Background(), c, state)
+	_, remove, diags := r.readResourceState(context.Background(), c, state)
 
 	if remove {
 		t.Errorf("expected state retained on asymmetric drift (post-fix), got removeFromState=true")
@@ -132,7 +132,7 @@ func TestK8sUpdate_TaskFails_StepActionUntouched(t *testing.T) {
 	newTask := testfake.Task(k8sReadTestNamespace, k8sTaskName, map[string]string{"v": "2"})
 	ops := tekton.NewResourceOperations(c)
 
-	diags := r.updateResources(context.Background(), ops, newSA, newTask)
+	diags := r.updateResources(context.Background(), ops, newSA, newTask, nil, false, nil, nil)
 	if !diags.HasError() {
 		t.Fatal("expected Task-update error to surface")
 	}
@@ -169,7 +169,7 @@ func TestK8sCreate_TaskFailsAndRollbackFails_BothDiagnosticsSurface(t *testing.T
 	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
 	ops := tekton.NewResourceOperations(c)
 
-	diags := r.createResources(context.Background(), ops, stepAction, task)
+	diags := r.createResources(context.Background(), ops, stepAction, task, nil, nil)
 
 	if !diags.HasError() {
 		t.Fatal("expected Task-create error to surface")
@@ -207,7 +207,7 @@ func TestK8sDelete_TaskFails_StepActionStillAttempted(t *testing.T) {
 	testfake.WithError(c, "delete", testfake.TaskGVR, testfake.ErrForbidden(testfake.TaskGVR, k8sTaskName))
 
 	ops := tekton.NewResourceOperations(c)
-	diags := r.deleteResources(context.Background(), ops, k8sReadTestNamespace, k8sTaskName, k8sStepActionName)
+	diags := r.deleteResources(context.Background(), ops, k8sReadTestNamespace, k8sTaskName, k8sStepActionName, false, nil)
 
 	if !diags.HasError() {
 		t.Fatal("expected Task-delete Forbidden error to surface")
@@ -66,7 +66,7 @@ func TestK8sReadResourceState_TaskHealthy_KeepState(t *testing.T) {
 	task := testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, nil)
 	r, c := resourceWithFake(task)
 
-	remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained for healthy Task, got removeFromState=true")
 	}
@@ -80,7 +80,7 @@ func TestK8sReadResourceState_TaskHealthy_KeepState(t *testing.T) {
 func TestK8sReadResourceState_TaskNotFound_RemoveFromState(t *testing.T) {
 	r, c := resourceWithFake() // empty cluster
 
-	remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
 	if !remove {
 		t.Errorf("expected removeFromState=true when Task is genuinely missing, got false")
 	}
@@ -100,13 +100,14 @@ func TestK8sReadResourceState_TaskNotFound_RemoveFromState(t *testing.T) {
 // Passes once issue #9 fix lands.
 //
 // See: https://github.com/Facets-cloud/terraform-provider-facets/issues/9
-//      RCA §9.1 (broader-framing reframe)
+//
+//	RCA §9.1 (broader-framing reframe)
 func TestK8sReadResourceState_TaskGet503_StateRetainedAndErrorSurfaced(t *testing.T) {
 	task := testfake.Task(k8sReadTestNamespace, k8sReadTestTaskName, nil)
 	r, c := resourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrServiceUnavailable("apiserver overloaded"))
 
-	remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on transient 503 (post-fix), got removeFromState=true")
 	}
@@ -123,7 +124,7 @@ func TestK8sReadResourceState_TaskGetForbidden_StateRetainedAndErrorSurfaced(t *
 	r, c := resourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrForbidden(testfake.TaskGVR, k8sReadTestTaskName))
 
-	remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on Forbidden (post-fix), got removeFromState=true")
 	}
@@ -140,7 +141,7 @@ func TestK8sReadResourceState_TaskGetServerTimeout_StateRetainedAndErrorSurfaced
 	r, c := resourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrServerTimeout("read"))
 
-	remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on ServerTimeout (post-fix), got removeFromState=true")
 	}
@@ -157,7 +158,7 @@ func TestK8sReadResourceState_TaskGetContextCanceled_StateRetainedAndErrorSurfac
 	r, c := resourceWithFake(task)
 	testfake.WithError(c, "get", testfake.TaskGVR, testfake.ErrContextCanceled())
 
-	remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
+	_, remove, diags := r.readResourceState(context.Background(), c, stateForRead(k8sReadTestNamespace, k8sReadTestTaskName))
 	if remove {
 		t.Errorf("expected state retained on context.Canceled (post-fix), got removeFromState=true")
 	}
@@ -183,7 +184,7 @@ func TestK8sReadResourceState_TaskMissing_StepActionPresent_AsymmetricDriftSurfa
 	state := stateForRead(k8sReadTestNamespace, k8sReadTestTaskName)
 	state.StepActionName = types.StringValue(k8sStepActionName)
 
-	remove, diags := r.readResourceState(context.Background(), c, state)
+	_, remove, diags := r.readResourceState(context.Background(), c, state)
 
 	if remove {
 		t.Errorf("expected state retained on asymmetric drift (post-fix), got removeFromState=true")
@@ -35,10 +35,10 @@ func TestGenerateResourceNames(t *testing.T) {
 			expectedHashLen: 32, // MD5 hex = 32 chars
 		},
 		{
-			name:         "long names",
-			resourceName: "very-long-application-name-that-exceeds-kubernetes-limits",
-			envName:      "production-environment-with-long-name",
-			displayName:  "comprehensive-test-action-with-long-display-name",
+			name:            "long names",
+			resourceName:    "very-long-application-name-that-exceeds-kubernetes-limits",
+			envName:         "production-environment-with-long-name",
+			displayName:     "comprehensive-test-action-with-long-display-name",
 			expectedHashLen: 32,
 		},
 	}
@@ -254,7 +254,11 @@ func TestBuildStepAction(t *testing.T) {
 		"cluster_id":              "test-cluster",
 	}
 
-	stepAction := tekton.BuildKubernetesStepAction(stepActionName, namespace, labels)
+	annotations := map[string]interface{}{
+		tekton.AnnotationDisplayName: "Test Action",
+	}
+
+	stepAction := tekton.BuildKubernetesStepAction(stepActionName, namespace, labels, annotations, tekton.CredentialStep{}, "")
 
 	// Check basic structure
 	if stepAction.GetAPIVersion() != "tekton.dev/v1beta1" {
@@ -283,10 +287,15 @@ func TestBuildStepAction(t *testing.T) {
 		t.Errorf("label display_name = %v, want test-action", stepLabels["display_name"])
 	}
 
+	// Check annotations are set
+	if got := stepAction.GetAnnotations()[tekton.AnnotationDisplayName]; got != "Test Action" {
+		t.Errorf("annotation %s = %q, want %q", tekton.AnnotationDisplayName, got, "Test Action")
+	}
+
 	// Check spec contains required fields
 	image, found, _ := unstructured.NestedString(stepAction.Object, "spec", "image")
-	if !found || image == "" {
-		t.Error("spec.image not found or empty")
+	if !found || image != tekton.DefaultCredentialStepImage {
+		t.Errorf("spec.image = %q, want %q", image, tekton.DefaultCredentialStepImage)
 	}
 
 	script, found, _ := unstructured.NestedString(stepAction.Object, "spec", "script")
@@ -304,14 +313,78 @@ func TestBuildStepAction(t *testing.T) {
 	}
 }
 
+// TestRenderObjects_KubeconfigDelivery tests the StepAction and Task of
+// both kubeconfig deliveries
+func TestRenderObjects_KubeconfigDelivery(t *testing.T) {
+	r := &TektonActionKubernetesResource{}
+	plan := testPlanWithImage("bitnami/kubectl:1.30")
+	plan.TaskName = types.StringValue(k8sReadTestTaskName)
+	plan.StepActionName = types.StringValue("setup-credentials-" + k8sReadTestTaskName)
+
+	paramNames := func(obj *unstructured.Unstructured, fields ...string) []string {
+		params, _, _ := unstructured.NestedSlice(obj.Object, fields...)
+		var names []string
+		for _, p := range params {
+			names = append(names, p.(map[string]interface{})["name"].(string))
+		}
+		return names
+	}
+
+	stepAction, task, _ := r.renderObjects(context.Background(), plan, tekton.StepPolicy{}, tekton.CredentialStep{}, &tekton.ResourceMetadata{})
+	if got := paramNames(task, "spec", "params"); fmt.Sprint(got) != "[FACETS_USER_EMAIL FACETS_USER_KUBECONFIG]" {
+		t.Errorf("param delivery Task params = %v", got)
+	}
+	if _, found, _ := unstructured.NestedSlice(task.Object, "spec", "workspaces"); found {
+		t.Error("param delivery Task should not declare workspaces")
+	}
+	if got := paramNames(stepAction, "spec", "params"); fmt.Sprint(got) != "[FACETS_USER_KUBECONFIG]" {
+		t.Errorf("param delivery StepAction params = %v", got)
+	}
+
+	plan.KubeconfigDelivery = types.StringValue(tekton.KubeconfigDeliverySecretWorkspace)
+	stepAction, task, _ = r.renderObjects(context.Background(), plan, tekton.StepPolicy{}, tekton.CredentialStep{}, &tekton.ResourceMetadata{})
+	if got := paramNames(task, "spec", "params"); fmt.Sprint(got) != "[FACETS_USER_EMAIL]" {
+		t.Errorf("secret_workspace Task params = %v, want only FACETS_USER_EMAIL", got)
+	}
+	workspaces, _, _ := unstructured.NestedSlice(task.Object, "spec", "workspaces")
+	if len(workspaces) != 1 {
+		t.Fatalf("secret_workspace Task workspaces = %v, want one", workspaces)
+	}
+	workspace := workspaces[0].(map[string]interface{})
+	if workspace["name"] != tekton.WorkspaceFacetsUserKubeconfig || workspace["readOnly"] != true {
+		t.Errorf("workspace = %v, want read-only %s", workspace, tekton.WorkspaceFacetsUserKubeconfig)
+	}
+
+	steps, _, _ := unstructured.NestedSlice(task.Object, "spec", "steps")
+	setupParams := steps[0].(map[string]interface{})["params"].([]interface{})
+	want := map[string]interface{}{
+		"name":  tekton.ParamFacetsUserKubeconfigPath,
+		"value": "$(workspaces.facets-user-kubeconfig.path)/kubeconfig",
+	}
+	if len(setupParams) != 1 || fmt.Sprint(setupParams[0]) != fmt.Sprint(want) {
+		t.Errorf("setup step params = %v, want [%v]", setupParams, want)
+	}
+
+	if got := paramNames(stepAction, "spec", "params"); fmt.Sprint(got) != "[FACETS_USER_KUBECONFIG_PATH]" {
+		t.Errorf("secret_workspace StepAction params = %v", got)
+	}
+	script, _, _ := unstructured.NestedString(stepAction.Object, "spec", "script")
+	if !regexp.MustCompile(`(?m)^cp "\$FACETS_USER_KUBECONFIG_PATH" /workspace/\.kube/config$`).MatchString(script) {
+		t.Errorf("secret_workspace script does not copy the kubeconfig:\n%s", script)
+	}
+	if regexp.MustCompile(`base64`).MatchString(script) {
+		t.Errorf("secret_workspace script should not decode a param:\n%s", script)
+	}
+}
+
 // TestExtractMetadataFromObject tests metadata extraction logic
 func TestExtractMetadataFromObject(t *testing.T) {
 	tests := []struct {
-		name          string
-		object        map[string]interface{}
-		expectError   bool
-		expectedNS    string
-		expectedName  string
+		name         string
+		object       map[string]interface{}
+		expectError  bool
+		expectedNS   string
+		expectedName string
 	}{
 		{
 			name: "valid metadata",
@@ -419,13 +492,13 @@ func TestValidateNamespaceFormat(t *testing.T) {
 		{"a", true},
 		{"123", true},
 		{"a-b-c-d-e-f", true},
-		{"UPPERCASE", false},      // uppercase not allowed
-		{"-start-hyphen", false},  // cannot start with hyphen
-		{"end-hyphen-", false},    // cannot end with hyphen
-		{"under_score", false},    // underscores not allowed
-		{"has space", false},      // spaces not allowed
-		{"special@char", false},   // special chars not allowed
-		{"", false},               // empty not allowed
+		{"UPPERCASE", false},     // uppercase not allowed
+		{"-start-hyphen", false}, // cannot start with hyphen
+		{"end-hyphen-", false},   // cannot end with hyphen
+		{"under_score", false},   // underscores not allowed
+		{"has space", false},     // spaces not allowed
+		{"special@char", false},  // special chars not allowed
+		{"", false},              // empty not allowed
 	}
 
 	// Kubernetes DNS-1123 label regex
@@ -454,12 +527,12 @@ func TestValidateEnvVarName(t *testing.T) {
 		{"_PRIVATE", true},
 		{"__DOUBLE", true},
 		{"A", true},
-		{"lowercase", false},    // lowercase not allowed
-		{"123START", false},     // cannot start with number
-		{"HAS-DASH", false},     // dashes not allowed
-		{"HAS SPACE", false},    // spaces not allowed
-		{"HAS.DOT", false},      // dots not allowed
-		{"", false},             // empty not allowed
+		{"lowercase", false}, // lowercase not allowed
+		{"123START", false},  // cannot start with number
+		{"HAS-DASH", false},  // dashes not allowed
+		{"HAS SPACE", false}, // spaces not allowed
+		{"HAS.DOT", false},   // dots not allowed
+		{"", false},          // empty not allowed
 	}
 
 	envVarRegex := regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
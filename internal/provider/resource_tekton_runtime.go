@@ -0,0 +1,535 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/k8s"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	_ resource.Resource                = &TektonRuntimeResource{}
+	_ resource.ResourceWithConfigure   = &TektonRuntimeResource{}
+	_ resource.ResourceWithImportState = &TektonRuntimeResource{}
+	_ resource.ResourceWithModifyPlan  = &TektonRuntimeResource{}
+)
+
+// NewTektonRuntimeResource creates a new runtime resource
+func NewTektonRuntimeResource() resource.Resource {
+	return &TektonRuntimeResource{
+		clientFactory: k8s.GetKubernetesClient,
+	}
+}
+
+// TektonRuntimeResource manages the cluster setup every action depends on:
+// the namespace, the runner ServiceAccount and Tekton's enable-step-actions flag
+type TektonRuntimeResource struct {
+	providerData *FacetsProviderModel
+	// clientFactory produces a Kubernetes dynamic client. Configure replaces
+	// the k8s.GetKubernetesClient default set by NewTektonRuntimeResource
+	// with a lookup in the provider's shared, rate-limited client pool.
+	// Tests in the same package may override this field directly to inject a
+	// fake client. Do not access from outside the provider package.
+	clientFactory func() (dynamic.Interface, error)
+}
+
+// TektonRuntimeResourceModel represents the resource data model
+type TektonRuntimeResourceModel struct {
+	ID                        types.String `tfsdk:"id"`
+	Namespace                 types.String `tfsdk:"namespace"`
+	ServiceAccountName        types.String `tfsdk:"service_account_name"`
+	ServiceAccountAnnotations types.Map    `tfsdk:"service_account_annotations"`
+	ImagePullSecrets          types.List   `tfsdk:"image_pull_secrets"`
+	EnableStepActions         types.Bool   `tfsdk:"enable_step_actions"`
+	TektonNamespace           types.String `tfsdk:"tekton_namespace"`
+}
+
+func (r *TektonRuntimeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tekton_runtime"
+}
+
+func (r *TektonRuntimeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	dnsLabel := []validator.String{
+		stringvalidator.RegexMatches(
+			regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`),
+			"must be a valid Kubernetes name (lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character)",
+		),
+		stringvalidator.LengthAtMost(63),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Manages the cluster setup Facets actions depend on: the namespace for Tasks and StepActions, " +
+			"the ServiceAccount the Facets runner starts TaskRuns as, and the enable-step-actions flag in Tekton's " +
+			"feature-flags ConfigMap. Changes made outside Terraform show up as drift.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Resource identifier in the format namespace/service_account_name",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace for Tasks, StepActions and the runner ServiceAccount. Created when it does not exist. " +
+					"Defaults to the provider's default_namespace, or \"tekton-pipelines\". Changing this forces recreation of the resource.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: dnsLabel,
+			},
+			"service_account_name": schema.StringAttribute{
+				Description: "Name of the ServiceAccount the Facets runner starts TaskRuns as. Changing this creates the new " +
+					"ServiceAccount, then deletes the old one.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(tekton.DefaultRunnerServiceAccount),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.LengthAtMost(253),
+				},
+			},
+			"service_account_annotations": schema.MapAttribute{
+				Description: "Annotations of the ServiceAccount, such as the cloud identity annotations " +
+					"eks.amazonaws.com/role-arn (IRSA), iam.gke.io/gcp-service-account or azure.workload.identity/client-id",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"image_pull_secrets": schema.ListAttribute{
+				Description: "Names of the Secrets in the namespace used to pull private step images",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"enable_step_actions": schema.BoolAttribute{
+				Description: "Value of the enable-step-actions flag in Tekton's feature-flags ConfigMap. Defaults to true, " +
+					"which every action needs. The flag is left as is when the resource is destroyed.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"tekton_namespace": schema.StringAttribute{
+				Description: "Namespace Tekton Pipelines is installed in, which holds the feature-flags ConfigMap. " +
+					"Defaults to the provider's tekton_namespace, or \"tekton-pipelines\".",
+				Optional:   true,
+				Computed:   true,
+				Validators: dnsLabel,
+			},
+		},
+	}
+}
+
+// ModifyPlan defaults namespace to the provider's default_namespace and
+// replaces the resource when that default changes, and defaults
+// tekton_namespace to the provider's tekton_namespace. The id is unknown when
+// service_account_name changes. It rejects render mode,
+// since the runtime updates Tekton's feature-flags ConfigMap, which only the
+// cluster holds.
+func (r *TektonRuntimeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	if r.providerData.renderMode() {
+		resp.Diagnostics.AddError("Render Mode Not Supported",
+			"facets_tekton_runtime updates Tekton's feature-flags ConfigMap in the cluster, which output_mode \"render\" "+
+				"does not connect to. Manage the runtime namespace, ServiceAccount and feature flags with your GitOps tooling instead.")
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var planServiceAccount, stateServiceAccount types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("service_account_name"), &planServiceAccount)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("service_account_name"), &stateServiceAccount)...)
+		if !planServiceAccount.Equal(stateServiceAccount) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+		}
+	}
+
+	var configTektonNamespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tekton_namespace"), &configTektonNamespace)...)
+	if configTektonNamespace.IsNull() && (r.providerData == nil || !r.providerData.TektonNamespace.IsUnknown()) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tekton_namespace"), types.StringValue(r.providerData.tektonNamespace()))...)
+	}
+
+	var configNamespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &configNamespace)...)
+	if resp.Diagnostics.HasError() || !configNamespace.IsNull() {
+		return
+	}
+	if r.providerData != nil && r.providerData.DefaultNamespace.IsUnknown() {
+		return
+	}
+
+	namespace := r.providerData.defaultNamespace()
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("namespace"), types.StringValue(namespace))...)
+	if !req.State.Raw.IsNull() {
+		var stateNamespace types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("namespace"), &stateNamespace)...)
+		if !stateNamespace.IsNull() && stateNamespace.ValueString() != namespace {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("namespace"))
+		}
+	}
+}
+
+func (r *TektonRuntimeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Client will be created lazily when needed during CRUD operations.
+	// This allows terraform validate to pass without requiring a kubeconfig.
+	if req.ProviderData != nil {
+		providerData, ok := req.ProviderData.(*FacetsProviderData)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Provider Data Type",
+				fmt.Sprintf("Expected *FacetsProviderData, got: %T", req.ProviderData),
+			)
+			return
+		}
+		r.providerData = providerData.Model
+		r.clientFactory = kubernetesClientFactory(providerData)
+	}
+}
+
+// getClient returns a Kubernetes client and operations for each call. See
+// TektonActionKubernetesResource.getClient.
+func (r *TektonRuntimeResource) getClient() (dynamic.Interface, *tekton.ResourceOperations, error) {
+	factory := r.clientFactory
+	if factory == nil {
+		factory = k8s.GetKubernetesClient
+	}
+	client, err := factory()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	return client, tekton.NewResourceOperations(client), nil
+}
+
+func (r *TektonRuntimeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TektonRuntimeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, operations, err := r.getClient()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			err.Error(),
+		)
+		return
+	}
+
+	if plan.Namespace.IsNull() || plan.Namespace.IsUnknown() || plan.Namespace.ValueString() == "" {
+		plan.Namespace = types.StringValue(r.providerData.defaultNamespace())
+	}
+	if plan.TektonNamespace.IsUnknown() {
+		plan.TektonNamespace = types.StringValue(r.providerData.tektonNamespace())
+	}
+	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", plan.Namespace.ValueString(), plan.ServiceAccountName.ValueString()))
+
+	resp.Diagnostics.Append(ensureRuntimeNamespace(ctx, client, plan.Namespace.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(applyRuntime(ctx, client, operations, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *TektonRuntimeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state TektonRuntimeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, _, err := r.getClient()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			err.Error(),
+		)
+		return
+	}
+
+	refreshed, remove, diags := readRuntime(ctx, client, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if remove {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &refreshed)...)
+}
+
+func (r *TektonRuntimeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan TektonRuntimeResourceModel
+	var state TektonRuntimeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, operations, err := r.getClient()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			err.Error(),
+		)
+		return
+	}
+
+	// namespace forces replacement, so it is unchanged
+	plan.Namespace = state.Namespace
+	if plan.TektonNamespace.IsUnknown() {
+		plan.TektonNamespace = types.StringValue(r.providerData.tektonNamespace())
+	}
+	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", plan.Namespace.ValueString(), plan.ServiceAccountName.ValueString()))
+
+	resp.Diagnostics.Append(applyRuntime(ctx, client, operations, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// A renamed ServiceAccount is created first, so TaskRuns always have one to run as
+	if plan.ServiceAccountName.ValueString() != state.ServiceAccountName.ValueString() {
+		resp.Diagnostics.Append(deleteRuntime(ctx, operations, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *TektonRuntimeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state TektonRuntimeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, operations, err := r.getClient()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Kubernetes Client",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(deleteRuntime(ctx, operations, state)...)
+}
+
+func (r *TektonRuntimeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: namespace/service_account_name
+	idParts := regexp.MustCompile(`^([^/]+)/([^/]+)$`).FindStringSubmatch(req.ID)
+	if len(idParts) != 3 {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID in format: namespace/service_account_name, got: %s", req.ID),
+		)
+		return
+	}
+
+	// Read fills in the rest. Only the annotations the configuration sets are
+	// tracked, so none are imported.
+	state := TektonRuntimeResourceModel{
+		ID:                        types.StringValue(req.ID),
+		Namespace:                 types.StringValue(idParts[1]),
+		ServiceAccountName:        types.StringValue(idParts[2]),
+		ServiceAccountAnnotations: types.MapNull(types.StringType),
+		ImagePullSecrets:          types.ListNull(types.StringType),
+		EnableStepActions:         types.BoolValue(true),
+		TektonNamespace:           types.StringValue(r.providerData.tektonNamespace()),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ensureRuntimeNamespace creates the namespace unless it already exists
+func ensureRuntimeNamespace(ctx context.Context, client dynamic.Interface, namespace string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	_, err := client.Resource(tekton.NamespaceGVR).Get(ctx, namespace, metav1.GetOptions{})
+	if err == nil {
+		return diags
+	}
+	if !apierrors.IsNotFound(err) {
+		diags.AddError(
+			"Error reading Namespace",
+			fmt.Sprintf("Could not read namespace %s: %s", namespace, err.Error()),
+		)
+		return diags
+	}
+	if _, err := client.Resource(tekton.NamespaceGVR).Create(ctx, tekton.BuildRuntimeNamespace(namespace), metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		diags.AddError(
+			"Error creating Namespace",
+			fmt.Sprintf("Could not create namespace %s: %s", namespace, err.Error()),
+		)
+	}
+	return diags
+}
+
+// applyRuntime creates or updates the runner ServiceAccount and sets the
+// enable-step-actions flag, keeping the other feature flags
+func applyRuntime(ctx context.Context, client dynamic.Interface, operations *tekton.ResourceOperations, plan TektonRuntimeResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	annotations, _ := mergeStringMaps(plan.ServiceAccountAnnotations, types.MapNull(types.StringType))
+	var imagePullSecrets []string
+	if !plan.ImagePullSecrets.IsNull() {
+		diags.Append(plan.ImagePullSecrets.ElementsAs(ctx, &imagePullSecrets, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+	serviceAccount := tekton.BuildRunnerServiceAccount(plan.ServiceAccountName.ValueString(), plan.Namespace.ValueString(), annotations, imagePullSecrets)
+	if err := operations.CreateResource(ctx, serviceAccount, tekton.ServiceAccountGVR.Group, tekton.ServiceAccountGVR.Version, tekton.ServiceAccountGVR.Resource); err != nil {
+		diags.AddError(
+			"Error applying ServiceAccount",
+			fmt.Sprintf("Could not create or update ServiceAccount %s/%s: %s", plan.Namespace.ValueString(), plan.ServiceAccountName.ValueString(), err.Error()),
+		)
+		return diags
+	}
+
+	tektonNamespace := plan.TektonNamespace.ValueString()
+	featureFlags, err := client.Resource(tekton.ConfigMapGVR).Namespace(tektonNamespace).Get(ctx, tekton.FeatureFlagsConfigMap, metav1.GetOptions{})
+	if err != nil {
+		detail := fmt.Sprintf("Could not read ConfigMap %s/%s: %s", tektonNamespace, tekton.FeatureFlagsConfigMap, err.Error())
+		if apierrors.IsNotFound(err) {
+			detail += ". Check that Tekton Pipelines is installed and that tekton_namespace is the namespace it is installed in."
+		}
+		diags.AddError("Error reading Tekton feature flags", detail)
+		return diags
+	}
+	value := strconv.FormatBool(plan.EnableStepActions.ValueBool())
+	if current, _ := tekton.FeatureFlag(featureFlags, tekton.FeatureFlagEnableStepActions); current == value {
+		return diags
+	}
+	tekton.SetFeatureFlag(featureFlags, tekton.FeatureFlagEnableStepActions, value)
+	if _, err := client.Resource(tekton.ConfigMapGVR).Namespace(tektonNamespace).Update(ctx, featureFlags, metav1.UpdateOptions{}); err != nil {
+		diags.AddError(
+			"Error updating Tekton feature flags",
+			fmt.Sprintf("Could not set %s in ConfigMap %s/%s: %s", tekton.FeatureFlagEnableStepActions, tektonNamespace, tekton.FeatureFlagsConfigMap, err.Error()),
+		)
+	}
+	return diags
+}
+
+// readRuntime refreshes state from the cluster. The resource is removed from
+// state when the namespace or the ServiceAccount is gone. Only the
+// ServiceAccount annotations in state are compared, so annotations added by
+// controllers do not show as drift.
+func readRuntime(ctx context.Context, client dynamic.Interface, state TektonRuntimeResourceModel) (TektonRuntimeResourceModel, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	namespace := state.Namespace.ValueString()
+
+	if _, err := client.Resource(tekton.NamespaceGVR).Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return state, true, diags
+		}
+		diags.AddError(
+			"Error reading Namespace",
+			fmt.Sprintf("Could not read namespace %s: %s", namespace, err.Error()),
+		)
+		return state, false, diags
+	}
+
+	serviceAccount, err := client.Resource(tekton.ServiceAccountGVR).Namespace(namespace).Get(ctx, state.ServiceAccountName.ValueString(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return state, true, diags
+		}
+		diags.AddError(
+			"Error reading ServiceAccount",
+			fmt.Sprintf("Could not read ServiceAccount %s/%s: %s", namespace, state.ServiceAccountName.ValueString(), err.Error()),
+		)
+		return state, false, diags
+	}
+
+	if !state.ServiceAccountAnnotations.IsNull() {
+		current := serviceAccount.GetAnnotations()
+		tracked := make(map[string]attr.Value, len(state.ServiceAccountAnnotations.Elements()))
+		for k := range state.ServiceAccountAnnotations.Elements() {
+			if v, ok := current[k]; ok {
+				tracked[k] = types.StringValue(v)
+			}
+		}
+		annotations, d := types.MapValue(types.StringType, tracked)
+		diags.Append(d...)
+		state.ServiceAccountAnnotations = annotations
+	}
+
+	if secrets := tekton.ImagePullSecrets(serviceAccount); len(secrets) > 0 {
+		imagePullSecrets, d := types.ListValueFrom(ctx, types.StringType, secrets)
+		diags.Append(d...)
+		state.ImagePullSecrets = imagePullSecrets
+	} else {
+		state.ImagePullSecrets = types.ListNull(types.StringType)
+	}
+
+	tektonNamespace := state.TektonNamespace.ValueString()
+	featureFlags, err := client.Resource(tekton.ConfigMapGVR).Namespace(tektonNamespace).Get(ctx, tekton.FeatureFlagsConfigMap, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		// Releases that enable StepActions by default omit the flag, as in the preflight
+		value, found := tekton.FeatureFlag(featureFlags, tekton.FeatureFlagEnableStepActions)
+		state.EnableStepActions = types.BoolValue(!found || value == "true")
+	case apierrors.IsNotFound(err):
+		// Without the ConfigMap the flag is not set; the next apply reports why
+		state.EnableStepActions = types.BoolValue(false)
+	default:
+		diags.AddError(
+			"Error reading Tekton feature flags",
+			fmt.Sprintf("Could not read ConfigMap %s/%s: %s", tektonNamespace, tekton.FeatureFlagsConfigMap, err.Error()),
+		)
+	}
+	return state, false, diags
+}
+
+// deleteRuntime deletes the ServiceAccount in state. The namespace is kept,
+// even when this resource created it: deleting it would delete every action
+// in it. The feature flag is left as is, since other Tekton users may rely on it.
+func deleteRuntime(ctx context.Context, operations *tekton.ResourceOperations, state TektonRuntimeResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	namespace := state.Namespace.ValueString()
+	if err := operations.DeleteResource(ctx, namespace, state.ServiceAccountName.ValueString(), tekton.ServiceAccountGVR.Group, tekton.ServiceAccountGVR.Version, tekton.ServiceAccountGVR.Resource); err != nil {
+		diags.AddError(
+			"Error deleting ServiceAccount",
+			fmt.Sprintf("Could not delete ServiceAccount %s/%s: %s", namespace, state.ServiceAccountName.ValueString(), err.Error()),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const runtimeTestNamespace = "facets-actions"
+
+func testRuntimeModel() TektonRuntimeResourceModel {
+	return TektonRuntimeResourceModel{
+		ID:                        types.StringValue(runtimeTestNamespace + "/" + tekton.DefaultRunnerServiceAccount),
+		Namespace:                 types.StringValue(runtimeTestNamespace),
+		ServiceAccountName:        types.StringValue(tekton.DefaultRunnerServiceAccount),
+		ServiceAccountAnnotations: testStringMap(map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/runner"}),
+		ImagePullSecrets:          types.ListValueMust(types.StringType, []attr.Value{types.StringValue("registry")}),
+		EnableStepActions:         types.BoolValue(true),
+		TektonNamespace:           types.StringValue(tektonPipelinesNamespace),
+	}
+}
+
+func TestTektonRuntime_ApplyAndDelete(t *testing.T) {
+	ctx := context.Background()
+	client := testfake.NewClient(testfake.ConfigMap(tektonPipelinesNamespace, tekton.FeatureFlagsConfigMap, map[string]string{"enable-api-fields": "beta"}))
+	operations := tekton.NewResourceOperations(client)
+	model := testRuntimeModel()
+
+	if diags := ensureRuntimeNamespace(ctx, client, runtimeTestNamespace); diags.HasError() {
+		t.Fatalf("ensureRuntimeNamespace() diags = %v", diags)
+	}
+	if diags := applyRuntime(ctx, client, operations, model); diags.HasError() {
+		t.Fatalf("applyRuntime() diags = %v", diags)
+	}
+
+	serviceAccount, err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Get(ctx, tekton.DefaultRunnerServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("ServiceAccount not created: %v", err)
+	}
+	if got := serviceAccount.GetAnnotations()["eks.amazonaws.com/role-arn"]; got == "" {
+		t.Error("ServiceAccount is missing the role-arn annotation")
+	}
+	if got := tekton.ImagePullSecrets(serviceAccount); len(got) != 1 || got[0] != "registry" {
+		t.Errorf("imagePullSecrets = %v, want [registry]", got)
+	}
+	featureFlags, _ := client.Resource(tekton.ConfigMapGVR).Namespace(tektonPipelinesNamespace).Get(ctx, tekton.FeatureFlagsConfigMap, metav1.GetOptions{})
+	if got, _ := tekton.FeatureFlag(featureFlags, tekton.FeatureFlagEnableStepActions); got != "true" {
+		t.Errorf("%s = %q, want true", tekton.FeatureFlagEnableStepActions, got)
+	}
+	if got, _ := tekton.FeatureFlag(featureFlags, "enable-api-fields"); got != "beta" {
+		t.Errorf("enable-api-fields = %q, other flags should be kept", got)
+	}
+
+	if diags := deleteRuntime(ctx, operations, model); diags.HasError() {
+		t.Fatalf("deleteRuntime() diags = %v", diags)
+	}
+	if _, err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Get(ctx, tekton.DefaultRunnerServiceAccount, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("ServiceAccount should be deleted, got err = %v", err)
+	}
+	// Deleting the namespace would delete every action in it
+	if _, err := client.Resource(tekton.NamespaceGVR).Get(ctx, runtimeTestNamespace, metav1.GetOptions{}); err != nil {
+		t.Errorf("namespace created by the resource should be kept, got err = %v", err)
+	}
+	featureFlags, _ = client.Resource(tekton.ConfigMapGVR).Namespace(tektonPipelinesNamespace).Get(ctx, tekton.FeatureFlagsConfigMap, metav1.GetOptions{})
+	if got, _ := tekton.FeatureFlag(featureFlags, tekton.FeatureFlagEnableStepActions); got != "true" {
+		t.Errorf("%s = %q, the flag should be left as is", tekton.FeatureFlagEnableStepActions, got)
+	}
+}
+
+// TestTektonRuntime_RenameServiceAccount checks a new service_account_name is
+// an in-place update that creates the new ServiceAccount and deletes the old one
+func TestTektonRuntime_RenameServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	client := testfake.NewClient(
+		testfake.Namespace(runtimeTestNamespace, "uid"),
+		testfake.ConfigMap(tektonPipelinesNamespace, tekton.FeatureFlagsConfigMap, nil),
+	)
+	r := &TektonRuntimeResource{clientFactory: func() (dynamic.Interface, error) { return client, nil }}
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	model := testRuntimeModel()
+	if diags := applyRuntime(ctx, client, tekton.NewResourceOperations(client), model); diags.HasError() {
+		t.Fatalf("applyRuntime() diags = %v", diags)
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	state.Set(ctx, model)
+	model.ServiceAccountName = types.StringValue("facets-runner")
+	model.ID = types.StringUnknown()
+	plan := tfsdk.Plan{Schema: schemaResp.Schema}
+	plan.Set(ctx, model)
+
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Update(ctx, resource.UpdateRequest{Plan: plan, State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() diags = %v", resp.Diagnostics)
+	}
+
+	if _, err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Get(ctx, "facets-runner", metav1.GetOptions{}); err != nil {
+		t.Errorf("new ServiceAccount not created: %v", err)
+	}
+	if _, err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Get(ctx, tekton.DefaultRunnerServiceAccount, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("old ServiceAccount should be deleted, got err = %v", err)
+	}
+	var id types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &id)
+	if want := runtimeTestNamespace + "/facets-runner"; id.ValueString() != want {
+		t.Errorf("id = %s, want %s", id, want)
+	}
+}
+
+func TestTektonRuntime_MissingFeatureFlags(t *testing.T) {
+	ctx := context.Background()
+	client := testfake.NewClient(testfake.Namespace(runtimeTestNamespace, "uid"))
+
+	diags := applyRuntime(ctx, client, tekton.NewResourceOperations(client), testRuntimeModel())
+	if !diags.HasError() || diags[0].Summary() != "Error reading Tekton feature flags" {
+		t.Fatalf("applyRuntime() diags = %v, want an error about the feature flags", diags)
+	}
+}
+
+func TestTektonRuntime_ReadDrift(t *testing.T) {
+	ctx := context.Background()
+	client := testfake.NewClient(
+		testfake.Namespace(runtimeTestNamespace, "uid"),
+		testfake.ConfigMap(tektonPipelinesNamespace, tekton.FeatureFlagsConfigMap, map[string]string{tekton.FeatureFlagEnableStepActions: "false"}),
+	)
+	serviceAccount := tekton.BuildRunnerServiceAccount(tekton.DefaultRunnerServiceAccount, runtimeTestNamespace,
+		map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/other", "controller.example.com/managed": "true"}, nil)
+	if _, err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, remove, diags := readRuntime(ctx, client, testRuntimeModel())
+	if diags.HasError() || remove {
+		t.Fatalf("readRuntime() remove = %v, diags = %v", remove, diags)
+	}
+	if refreshed.EnableStepActions.ValueBool() {
+		t.Error("enable_step_actions should be refreshed to false")
+	}
+	if !refreshed.ImagePullSecrets.IsNull() {
+		t.Errorf("image_pull_secrets = %v, want null", refreshed.ImagePullSecrets)
+	}
+	annotations := refreshed.ServiceAccountAnnotations.Elements()
+	if len(annotations) != 1 || annotations["eks.amazonaws.com/role-arn"] != types.StringValue("arn:aws:iam::123456789012:role/other") {
+		t.Errorf("service_account_annotations = %v, want only the changed role-arn", annotations)
+	}
+
+	if err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Delete(ctx, tekton.DefaultRunnerServiceAccount, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, remove, diags := readRuntime(ctx, client, testRuntimeModel()); diags.HasError() || !remove {
+		t.Errorf("readRuntime() remove = %v, diags = %v, want removal when the ServiceAccount is gone", remove, diags)
+	}
+}
+
+func TestTektonRuntime_ReadMissingFeatureFlag(t *testing.T) {
+	ctx := context.Background()
+	client := testfake.NewClient(
+		testfake.Namespace(runtimeTestNamespace, "uid"),
+		testfake.ConfigMap(tektonPipelinesNamespace, tekton.FeatureFlagsConfigMap, map[string]string{"enable-api-fields": "beta"}),
+	)
+	model := testRuntimeModel()
+	if _, err := client.Resource(tekton.ServiceAccountGVR).Namespace(runtimeTestNamespace).Create(ctx,
+		tekton.BuildRunnerServiceAccount(tekton.DefaultRunnerServiceAccount, runtimeTestNamespace, nil, nil), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Releases that enable StepActions by default omit the flag
+	refreshed, _, diags := readRuntime(ctx, client, model)
+	if diags.HasError() || !refreshed.EnableStepActions.ValueBool() {
+		t.Errorf("readRuntime() enable_step_actions = %s, diags = %v, want true", refreshed.EnableStepActions, diags)
+	}
+}
+
+func TestTektonRuntime_ImportState(t *testing.T) {
+	ctx := context.Background()
+	r := &TektonRuntimeResource{providerData: &FacetsProviderModel{TektonNamespace: types.StringValue("openshift-pipelines")}}
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: runtimeTestNamespace + "/facets-runner"}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("ImportState() diags = %v", resp.Diagnostics)
+	}
+	var state TektonRuntimeResourceModel
+	resp.State.Get(ctx, &state)
+	if state.Namespace.ValueString() != runtimeTestNamespace || state.ServiceAccountName.ValueString() != "facets-runner" {
+		t.Errorf("namespace = %s, service_account_name = %s", state.Namespace, state.ServiceAccountName)
+	}
+	if state.TektonNamespace.ValueString() != "openshift-pipelines" {
+		t.Errorf("tekton_namespace = %s, want the provider's openshift-pipelines", state.TektonNamespace)
+	}
+}
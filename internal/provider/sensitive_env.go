@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var secretGVR = k8sschema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// hasSensitiveEnv reports whether any step sets sensitive_env, i.e. whether
+// the action owns an env Secret
+func hasSensitiveEnv(ctx context.Context, steps types.List) bool {
+	var stepModels []tekton.StepModel
+	if steps.IsNull() || steps.IsUnknown() || steps.ElementsAs(ctx, &stepModels, false).HasError() {
+		return false
+	}
+	for _, step := range stepModels {
+		if !step.SensitiveEnv.IsNull() && len(step.SensitiveEnv.Elements()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildEnvSecret returns the Secret holding the sensitive_env values of the
+// steps, or nil when no step sets sensitive_env
+func buildEnvSecret(ctx context.Context, steps types.List, namespace, taskName string, metadata *tekton.ResourceMetadata) *unstructured.Unstructured {
+	var stepModels []tekton.StepModel
+	steps.ElementsAs(ctx, &stepModels, false)
+
+	data := tekton.SensitiveEnvData(stepModels)
+	if len(data) == 0 {
+		return nil
+	}
+	return tekton.BuildEnvSecret(
+		tekton.EnvSecretName(taskName),
+		namespace,
+		metadata.LabelsAsInterface(),
+		metadata.AnnotationsAsInterface(),
+		data,
+	)
+}
+
+// applyEnvSecret creates the env Secret, or updates it when it already exists
+func applyEnvSecret(ctx context.Context, operations *tekton.ResourceOperations, secret *unstructured.Unstructured) error {
+	return operations.CreateResource(ctx, secret, secretGVR.Group, secretGVR.Version, secretGVR.Resource)
+}
+
+// deleteEnvSecret deletes a Task's env Secret. NotFound is not an error.
+func deleteEnvSecret(ctx context.Context, operations *tekton.ResourceOperations, namespace, taskName string) error {
+	return operations.DeleteResource(ctx, namespace, tekton.EnvSecretName(taskName), secretGVR.Group, secretGVR.Version, secretGVR.Resource)
+}
+
+// rollbackEnvSecret deletes a Secret created earlier in a failed Create,
+// warning when that fails too
+func rollbackEnvSecret(ctx context.Context, operations *tekton.ResourceOperations, secret *unstructured.Unstructured) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if secret == nil {
+		return diags
+	}
+	if err := operations.DeleteResource(ctx, secret.GetNamespace(), secret.GetName(), secretGVR.Group, secretGVR.Version, secretGVR.Resource); err != nil {
+		diags.AddWarning(
+			"Rollback of orphaned Secret failed",
+			fmt.Sprintf("Could not clean up Secret %s/%s: %s. Manual cleanup may be required.",
+				secret.GetNamespace(), secret.GetName(), err.Error()),
+		)
+	}
+	return diags
+}
+
+// refreshSensitiveEnv compares the sensitive_env values in state with the
+// env Secret. Steps whose values are missing from the Secret or differ are
+// returned with a null sensitive_env, so the next plan shows an update that
+// writes the Secret again. Secrets are only read when state has sensitive_env.
+func refreshSensitiveEnv(ctx context.Context, client dynamic.Interface, namespace, taskName string, steps types.List) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !hasSensitiveEnv(ctx, steps) {
+		return steps, diags
+	}
+
+	current := map[string]string{}
+	secretName := tekton.EnvSecretName(taskName)
+	secret, err := client.Resource(secretGVR).Namespace(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		current = tekton.SecretData(secret)
+	case !apierrors.IsNotFound(err):
+		diags.AddError(
+			"Error reading Secret",
+			fmt.Sprintf("Could not read Secret %s/%s: %s", namespace, secretName, err.Error()),
+		)
+		return steps, diags
+	}
+
+	elems := make([]attr.Value, 0, len(steps.Elements()))
+	for _, elem := range steps.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok {
+			return steps, diags
+		}
+		var step tekton.StepModel
+		diags.Append(obj.As(ctx, &step, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return steps, diags
+		}
+
+		inSync := true
+		for name, value := range step.SensitiveEnv.Elements() {
+			v, ok := value.(types.String)
+			got, found := current[tekton.SensitiveEnvKey(step.Name.ValueString(), name)]
+			if !ok || !found || got != v.ValueString() {
+				inSync = false
+				break
+			}
+		}
+		if !inSync {
+			attrs := obj.Attributes()
+			attrs["sensitive_env"] = types.MapNull(types.StringType)
+			obj, d := types.ObjectValue(tekton.StepObjectType.AttrTypes, attrs)
+			diags.Append(d...)
+			elem = obj
+		}
+		elems = append(elems, elem)
+	}
+	if diags.HasError() {
+		return steps, diags
+	}
+
+	refreshed, d := types.ListValue(tekton.StepObjectType, elems)
+	diags.Append(d...)
+	return refreshed, diags
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testStepsWithSensitiveEnv returns a steps list with a single step named
+// deploy whose sensitive_env holds values
+func testStepsWithSensitiveEnv(values map[string]string) types.List {
+	sensitiveEnv := types.MapNull(types.StringType)
+	if values != nil {
+		sensitiveEnv = testStringMap(values)
+	}
+	step := types.ObjectValueMust(tekton.StepObjectType.AttrTypes, map[string]attr.Value{
+		"name":          types.StringValue("deploy"),
+		"image":         types.StringValue("bitnami/kubectl:1.30"),
+		"script":        types.StringValue("kubectl rollout restart deployment/api"),
+		"resources":     types.ObjectNull(tekton.ComputeResourcesObjectType.AttrTypes),
+		"env":           types.ListNull(tekton.EnvVarObjectType),
+		"sensitive_env": sensitiveEnv,
+	})
+	return types.ListValueMust(tekton.StepObjectType, []attr.Value{step})
+}
+
+func TestBuildEnvSecret(t *testing.T) {
+	metadata := &tekton.ResourceMetadata{}
+	if secret := buildEnvSecret(context.Background(), testStepsWithSensitiveEnv(nil), k8sReadTestNamespace, k8sTaskName, metadata); secret != nil {
+		t.Errorf("expected no Secret without sensitive_env, got %v", secret.Object)
+	}
+
+	secret := buildEnvSecret(context.Background(), testStepsWithSensitiveEnv(map[string]string{"API_TOKEN": "s3cret"}), k8sReadTestNamespace, k8sTaskName, metadata)
+	if secret == nil {
+		t.Fatal("expected a Secret")
+	}
+	if secret.GetName() != tekton.EnvSecretName(k8sTaskName) || secret.GetNamespace() != k8sReadTestNamespace {
+		t.Errorf("Secret = %s/%s", secret.GetNamespace(), secret.GetName())
+	}
+	if got := tekton.SecretData(secret)["deploy.API_TOKEN"]; got != "s3cret" {
+		t.Errorf("Secret data deploy.API_TOKEN = %q, want s3cret", got)
+	}
+}
+
+func TestK8sCreate_TaskFails_RollsBackSecret(t *testing.T) {
+	r, c := resourceWithFake()
+	testfake.WithError(c, "create", testfake.TaskGVR, testfake.ErrInternalServer("etcd unavailable"))
+
+	secret := testfake.Secret(k8sReadTestNamespace, tekton.EnvSecretName(k8sTaskName), map[string]string{"deploy.API_TOKEN": "s3cret"})
+	stepAction := testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
+	ops := tekton.NewResourceOperations(c)
+
+	if diags := r.createResources(context.Background(), ops, stepAction, task, secret, nil); !diags.HasError() {
+		t.Fatal("expected Task-create error to surface in diagnostics")
+	}
+	_, err := c.Resource(testfake.SecretGVR).Namespace(k8sReadTestNamespace).Get(context.Background(), secret.GetName(), metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected Secret rolled back (NotFound) after Task-create failed, got err=%v", err)
+	}
+}
+
+func TestK8sUpdate_RemovesUnusedSecret(t *testing.T) {
+	secretName := tekton.EnvSecretName(k8sTaskName)
+	r, c := resourceWithFake(
+		testfake.Task(k8sReadTestNamespace, k8sTaskName, nil),
+		testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil),
+		testfake.Secret(k8sReadTestNamespace, secretName, map[string]string{"deploy.API_TOKEN": "s3cret"}),
+	)
+	ops := tekton.NewResourceOperations(c)
+
+	stepAction := testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil)
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, nil)
+	if diags := r.updateResources(context.Background(), ops, stepAction, task, nil, true, nil, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	_, err := c.Resource(testfake.SecretGVR).Namespace(k8sReadTestNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected Secret deleted once no step uses sensitive_env, got err=%v", err)
+	}
+}
+
+func TestK8sUpdate_WritesSecretBeforeTask(t *testing.T) {
+	r, c := resourceWithFake(
+		testfake.Task(k8sReadTestNamespace, k8sTaskName, nil),
+		testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil),
+	)
+	testfake.WithError(c, "create", testfake.SecretGVR, testfake.ErrForbidden(testfake.SecretGVR, tekton.EnvSecretName(k8sTaskName)))
+	ops := tekton.NewResourceOperations(c)
+
+	secret := testfake.Secret(k8sReadTestNamespace, tekton.EnvSecretName(k8sTaskName), map[string]string{"deploy.API_TOKEN": "s3cret"})
+	task := testfake.Task(k8sReadTestNamespace, k8sTaskName, map[string]string{"updated": "true"})
+	diags := r.updateResources(context.Background(), ops, testfake.StepAction(k8sReadTestNamespace, k8sStepActionName, nil), task, secret, false, nil, nil)
+	if !diags.HasError() {
+		t.Fatal("expected Secret error to surface in diagnostics")
+	}
+
+	current, err := c.Resource(testfake.TaskGVR).Namespace(k8sReadTestNamespace).Get(context.Background(), k8sTaskName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get Task: %v", err)
+	}
+	if current.GetLabels()["updated"] == "true" {
+		t.Error("Task must not reference a Secret that could not be written")
+	}
+}
+
+func TestRefreshSensitiveEnv(t *testing.T) {
+	secretName := tekton.EnvSecretName(k8sTaskName)
+	steps := testStepsWithSensitiveEnv(map[string]string{"API_TOKEN": "s3cret"})
+
+	tests := map[string]struct {
+		secretValues map[string]string
+		wantCleared  bool
+	}{
+		"in sync":        {secretValues: map[string]string{"deploy.API_TOKEN": "s3cret"}},
+		"value changed":  {secretValues: map[string]string{"deploy.API_TOKEN": "rotated"}, wantCleared: true},
+		"key removed":    {secretValues: map[string]string{}, wantCleared: true},
+		"secret deleted": {wantCleared: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := testfake.NewClient()
+			if tt.secretValues != nil {
+				c = testfake.NewClient(testfake.Secret(k8sReadTestNamespace, secretName, tt.secretValues))
+			}
+
+			got, diags := refreshSensitiveEnv(context.Background(), c, k8sReadTestNamespace, k8sTaskName, steps)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if cleared := !got.Equal(steps); cleared != tt.wantCleared {
+				t.Errorf("sensitive_env cleared = %v, want %v", cleared, tt.wantCleared)
+			}
+			if tt.wantCleared && hasSensitiveEnv(context.Background(), got) {
+				t.Error("expected sensitive_env to be null after refresh")
+			}
+		})
+	}
+}
+
+func TestRefreshSensitiveEnv_TransientErrorKeepsState(t *testing.T) {
+	c := testfake.NewClient()
+	testfake.WithError(c, "get", testfake.SecretGVR, testfake.ErrServerTimeout("get"))
+	steps := testStepsWithSensitiveEnv(map[string]string{"API_TOKEN": "s3cret"})
+
+	got, diags := refreshSensitiveEnv(context.Background(), c, k8sReadTestNamespace, k8sTaskName, steps)
+	if !diags.HasError() {
+		t.Error("expected a transient error to surface")
+	}
+	if !got.Equal(steps) {
+		t.Error("steps must be unchanged on a transient error")
+	}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// stepPolicy returns the provider's default_step_resources, max_step_resources
+// and allowed_image_registries as a tekton.StepPolicy. known is false when any
+// of them is still unknown. Safe to call on a nil provider model, which yields
+// the zero policy.
+func (m *FacetsProviderModel) stepPolicy(ctx context.Context) (policy tekton.StepPolicy, known bool) {
+	if m == nil {
+		return policy, true
+	}
+	if m.AllowedImageRegistries.IsUnknown() {
+		return policy, false
+	}
+
+	var defaultsKnown, maxKnown bool
+	policy.DefaultResources, defaultsKnown = resourceListFromObject(ctx, m.DefaultStepResources)
+	policy.MaxResources, maxKnown = resourceListFromObject(ctx, m.MaxStepResources)
+	if !defaultsKnown || !maxKnown {
+		return policy, false
+	}
+
+	for _, elem := range m.AllowedImageRegistries.Elements() {
+		registry, ok := elem.(types.String)
+		if !ok || registry.IsUnknown() {
+			return policy, false
+		}
+		if !registry.IsNull() {
+			policy.AllowedImageRegistries = append(policy.AllowedImageRegistries, registry.ValueString())
+		}
+	}
+	return policy, true
+}
+
+// resourceListFromObject converts a requests/limits object into a
+// tekton.ResourceList. known is false when the object, either map or any of
+// their values is unknown.
+func resourceListFromObject(ctx context.Context, obj types.Object) (list tekton.ResourceList, known bool) {
+	if obj.IsNull() {
+		return list, true
+	}
+	if obj.IsUnknown() {
+		return list, false
+	}
+
+	var computeRes tekton.ComputeResourcesModel
+	if diags := obj.As(ctx, &computeRes, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return list, false
+	}
+	requests, requestsKnown := mergeStringMaps(types.MapNull(types.StringType), computeRes.Requests)
+	limits, limitsKnown := mergeStringMaps(types.MapNull(types.StringType), computeRes.Limits)
+	if !requestsKnown || !limitsKnown {
+		return list, false
+	}
+	return tekton.ResourceList{Requests: requests, Limits: limits}, true
+}
+
+// applyStepPolicy returns the policy used to render the steps at apply time,
+// after enforcing it. The policy is normally checked during plan already; it
+// is checked again here in case it was unknown then.
+func applyStepPolicy(ctx context.Context, steps types.List, providerData *FacetsProviderModel) (tekton.StepPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	policy, known := providerData.stepPolicy(ctx)
+	if !known {
+		diags.AddError(
+			"Unable to Apply Step Policy",
+			"The provider's default_step_resources, max_step_resources or allowed_image_registries are still unknown during apply.",
+		)
+		return policy, diags
+	}
+	diags.Append(tekton.ValidateStepPolicy(ctx, steps, policy)...)
+	return policy, diags
+}
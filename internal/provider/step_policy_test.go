@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testResourcesObject(requests, limits types.Map) types.Object {
+	return types.ObjectValueMust(tekton.ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+		"requests": requests,
+		"limits":   limits,
+	})
+}
+
+func TestProviderModelStepPolicy(t *testing.T) {
+	m := &FacetsProviderModel{
+		DefaultStepResources: testResourcesObject(
+			testStringMap(map[string]string{"cpu": "100m"}),
+			types.MapNull(types.StringType),
+		),
+		MaxStepResources: types.ObjectNull(tekton.ComputeResourcesObjectType.AttrTypes),
+		AllowedImageRegistries: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("ghcr.io"),
+		}),
+	}
+
+	policy, known := m.stepPolicy(context.Background())
+	if !known {
+		t.Fatal("expected policy to be known")
+	}
+	want := tekton.StepPolicy{
+		DefaultResources:       tekton.ResourceList{Requests: map[string]string{"cpu": "100m"}, Limits: map[string]string{}},
+		AllowedImageRegistries: []string{"ghcr.io"},
+	}
+	if !reflect.DeepEqual(policy, want) {
+		t.Errorf("stepPolicy() = %+v, want %+v", policy, want)
+	}
+
+	var nilModel *FacetsProviderModel
+	if policy, known := nilModel.stepPolicy(context.Background()); !known || !reflect.DeepEqual(policy, tekton.StepPolicy{}) {
+		t.Errorf("stepPolicy() on nil model = %+v, %v; want zero policy", policy, known)
+	}
+}
+
+func TestProviderModelStepPolicy_Unknown(t *testing.T) {
+	tests := map[string]*FacetsProviderModel{
+		"unknown registries": {AllowedImageRegistries: types.ListUnknown(types.StringType)},
+		"unknown registry": {AllowedImageRegistries: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringUnknown(),
+		})},
+		"unknown max": {MaxStepResources: types.ObjectUnknown(tekton.ComputeResourcesObjectType.AttrTypes)},
+		"unknown default quantity": {DefaultStepResources: testResourcesObject(
+			types.MapValueMust(types.StringType, map[string]attr.Value{"cpu": types.StringUnknown()}),
+			types.MapNull(types.StringType),
+		)},
+	}
+
+	for name, m := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, known := m.stepPolicy(context.Background()); known {
+				t.Error("expected policy to be unknown")
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// Where the Tekton components record themselves
+const (
+	// Group of the Tekton Pipelines API
+	Group = "tekton.dev"
+	// TriggersGroup is served when Tekton Triggers is installed
+	TriggersGroup = "triggers.tekton.dev"
+
+	// PipelinesInfoConfigMap holds the installed Tekton Pipelines version
+	// under the key "version", in the namespace Tekton Pipelines is installed in
+	PipelinesInfoConfigMap = "pipelines-info"
+	// PipelinesControllerDeployment is the Tekton Pipelines controller,
+	// labelled with its version
+	PipelinesControllerDeployment = "tekton-pipelines-controller"
+	// ResultsAPIDeployment is installed with Tekton Results, in the namespace
+	// Tekton Pipelines is installed in
+	ResultsAPIDeployment = "tekton-results-api"
+	// ChainsNamespace and ChainsControllerDeployment locate Tekton Chains
+	ChainsNamespace            = "tekton-chains"
+	ChainsControllerDeployment = "tekton-chains-controller"
+)
+
+// DeploymentGVR is the GVR of the Deployments the capabilities are read from
+var DeploymentGVR = k8sschema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+// Capabilities describes the Tekton installation of a cluster. Values the
+// Terraform identity may not read are nil.
+type Capabilities struct {
+	// PipelinesVersion is the Tekton Pipelines version, e.g. "v0.62.1", or
+	// empty when Tekton Pipelines is not installed or the version is unknown
+	PipelinesVersion string
+	// TaskVersions, StepActionVersions and PipelineVersions list the served
+	// tekton.dev versions of each resource, preferred version first
+	TaskVersions       []string
+	StepActionVersions []string
+	PipelineVersions   []string
+	// FeatureFlags is the data of the feature-flags ConfigMap
+	FeatureFlags map[string]string
+
+	TriggersInstalled bool
+	ResultsInstalled  *bool
+	ChainsInstalled   *bool
+}
+
+// DiscoverCapabilities reads the capabilities of the Tekton installation in
+// tektonNamespace. NotFound means a component is not installed; Forbidden
+// leaves the value nil. Other errors are returned.
+func DiscoverCapabilities(ctx context.Context, disc discovery.DiscoveryInterface, client dynamic.Interface, tektonNamespace string) (*Capabilities, error) {
+	capabilities := &Capabilities{}
+
+	groups, err := disc.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API groups: %w", err)
+	}
+	for _, group := range groups.Groups {
+		switch group.Name {
+		case TriggersGroup:
+			capabilities.TriggersInstalled = true
+		case Group:
+			for _, version := range group.Versions {
+				resources, err := disc.ServerResourcesForGroupVersion(version.GroupVersion)
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("failed to list resources of %s: %w", version.GroupVersion, err)
+				}
+				for _, r := range resources.APIResources {
+					switch r.Name {
+					case "tasks":
+						capabilities.TaskVersions = append(capabilities.TaskVersions, version.Version)
+					case "stepactions":
+						capabilities.StepActionVersions = append(capabilities.StepActionVersions, version.Version)
+					case "pipelines":
+						capabilities.PipelineVersions = append(capabilities.PipelineVersions, version.Version)
+					}
+				}
+			}
+		}
+	}
+
+	featureFlags, err := getOptional(ctx, client, ConfigMapGVR, tektonNamespace, FeatureFlagsConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	if featureFlags != nil {
+		capabilities.FeatureFlags, _, _ = unstructured.NestedStringMap(featureFlags.Object, "data")
+	}
+
+	if capabilities.PipelinesVersion, err = pipelinesVersion(ctx, client, tektonNamespace); err != nil {
+		return nil, err
+	}
+	if capabilities.ResultsInstalled, err = deploymentExists(ctx, client, tektonNamespace, ResultsAPIDeployment); err != nil {
+		return nil, err
+	}
+	if capabilities.ChainsInstalled, err = deploymentExists(ctx, client, ChainsNamespace, ChainsControllerDeployment); err != nil {
+		return nil, err
+	}
+	return capabilities, nil
+}
+
+// pipelinesVersion reads the Tekton Pipelines version from the pipelines-info
+// ConfigMap, falling back to the controller Deployment's labels
+func pipelinesVersion(ctx context.Context, client dynamic.Interface, tektonNamespace string) (string, error) {
+	info, err := getOptional(ctx, client, ConfigMapGVR, tektonNamespace, PipelinesInfoConfigMap)
+	if err != nil {
+		return "", err
+	}
+	if info != nil {
+		if version, _, _ := unstructured.NestedString(info.Object, "data", "version"); version != "" {
+			return version, nil
+		}
+	}
+
+	controller, err := getOptional(ctx, client, DeploymentGVR, tektonNamespace, PipelinesControllerDeployment)
+	if err != nil || controller == nil {
+		return "", err
+	}
+	labels := controller.GetLabels()
+	for _, key := range []string{"app.kubernetes.io/version", "pipeline.tekton.dev/release", "version"} {
+		if version := labels[key]; version != "" {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+// deploymentExists reports whether a Deployment exists, or nil when it may not be read
+func deploymentExists(ctx context.Context, client dynamic.Interface, namespace, name string) (*bool, error) {
+	_, err := client.Resource(DeploymentGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	exists := err == nil
+	switch {
+	case err == nil, apierrors.IsNotFound(err):
+		return &exists, nil
+	case apierrors.IsForbidden(err):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to read Deployment %s/%s: %w", namespace, name, err)
+	}
+}
+
+// getOptional gets an object, returning nil when it does not exist or may not be read
+func getOptional(ctx context.Context, client dynamic.Interface, gvr k8sschema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return obj, nil
+	case apierrors.IsNotFound(err), apierrors.IsForbidden(err):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to read %s %s/%s: %w", gvr.Resource, namespace, name, err)
+	}
+}
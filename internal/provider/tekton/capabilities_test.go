@@ -0,0 +1,81 @@
+package tekton
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// deployment returns a Deployment with the given labels
+func deployment(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+	}}
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestDiscoverCapabilities(t *testing.T) {
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "tekton.dev/v1", APIResources: []metav1.APIResource{{Name: "tasks"}, {Name: "pipelines"}}},
+		{GroupVersion: "tekton.dev/v1beta1", APIResources: []metav1.APIResource{{Name: "tasks"}, {Name: "stepactions"}, {Name: "pipelines"}}},
+		{GroupVersion: "triggers.tekton.dev/v1beta1", APIResources: []metav1.APIResource{{Name: "eventlisteners"}}},
+	}}}
+	client := testfake.NewClient(
+		testfake.ConfigMap("tekton-pipelines", PipelinesInfoConfigMap, map[string]string{"version": "v0.62.1"}),
+		testfake.ConfigMap("tekton-pipelines", FeatureFlagsConfigMap, map[string]string{FeatureFlagEnableStepActions: "true"}),
+		deployment(ChainsNamespace, ChainsControllerDeployment, nil),
+	)
+
+	got, err := DiscoverCapabilities(context.Background(), disc, client, "tekton-pipelines")
+	if err != nil {
+		t.Fatalf("DiscoverCapabilities() error = %v", err)
+	}
+	yes, no := true, false
+	want := &Capabilities{
+		PipelinesVersion:   "v0.62.1",
+		TaskVersions:       []string{"v1", "v1beta1"},
+		StepActionVersions: []string{"v1beta1"},
+		PipelineVersions:   []string{"v1", "v1beta1"},
+		FeatureFlags:       map[string]string{FeatureFlagEnableStepActions: "true"},
+		TriggersInstalled:  true,
+		ResultsInstalled:   &no,
+		ChainsInstalled:    &yes,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverCapabilities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiscoverCapabilities_NotInstalled(t *testing.T) {
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	client := testfake.NewClient()
+	testfake.WithError(client, "get", DeploymentGVR, testfake.ErrForbidden(DeploymentGVR, ChainsControllerDeployment))
+
+	got, err := DiscoverCapabilities(context.Background(), disc, client, "tekton-pipelines")
+	if err != nil {
+		t.Fatalf("DiscoverCapabilities() error = %v", err)
+	}
+	if got.PipelinesVersion != "" || got.TaskVersions != nil || got.FeatureFlags != nil || got.TriggersInstalled {
+		t.Errorf("DiscoverCapabilities() = %+v, want nothing installed", got)
+	}
+	if got.ResultsInstalled != nil || got.ChainsInstalled != nil {
+		t.Error("Deployments that may not be read should be reported as unknown")
+	}
+}
+
+func TestPipelinesVersion_FromControllerLabels(t *testing.T) {
+	client := testfake.NewClient(deployment("tekton-pipelines", PipelinesControllerDeployment, map[string]string{"app.kubernetes.io/version": "v0.59.0"}))
+	got, err := pipelinesVersion(context.Background(), client, "tekton-pipelines")
+	if err != nil || got != "v0.59.0" {
+		t.Errorf("pipelinesVersion() = %q, %v, want v0.59.0", got, err)
+	}
+}
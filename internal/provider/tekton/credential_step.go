@@ -0,0 +1,89 @@
+package tekton
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultCredentialStepImage is the image the credential StepAction runs when
+// neither the resource nor the provider overrides it
+const DefaultCredentialStepImage = "facetscloud/actions-base-image:v1.0.0"
+
+// Tools an override credential step image must provide on its PATH. The
+// StepAction script checks for them first and fails with a clear error when
+// one is missing.
+var (
+	KubernetesCredentialStepTools = []string{"bash", "mkdir", "base64"}
+	AWSCredentialStepTools        = []string{"bash", "mkdir", "cat", "chmod", "aws"}
+)
+
+// CredentialStep configures the image of the credential setup step
+type CredentialStep struct {
+	// Image defaults to DefaultCredentialStepImage
+	Image string
+	// ImagePullPolicy is Always, IfNotPresent or Never; empty leaves it to Kubernetes
+	ImagePullPolicy string
+}
+
+// ImageOrDefault returns the configured image or DefaultCredentialStepImage
+func (c CredentialStep) ImageOrDefault() string {
+	if c.Image == "" {
+		return DefaultCredentialStepImage
+	}
+	return c.Image
+}
+
+// ApplyTo sets the pull policy on the Task step that references the
+// credential StepAction. StepActions have no imagePullPolicy field, so the
+// policy is set on the referencing step, which Tekton keeps when it resolves
+// the reference.
+func (c CredentialStep) ApplyTo(step map[string]interface{}) {
+	if c.ImagePullPolicy != "" {
+		step["imagePullPolicy"] = c.ImagePullPolicy
+	}
+}
+
+// MirrorImage returns image pulled through mirror instead of its own
+// registry, e.g. "harbor.internal/dockerhub" turns
+// "facetscloud/actions-base-image:v1.0.0" into
+// "harbor.internal/dockerhub/facetscloud/actions-base-image:v1.0.0".
+// Docker Hub official images keep their library/ prefix.
+func MirrorImage(image, mirror string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+	if mirror == "" {
+		return image
+	}
+
+	first, rest, found := strings.Cut(image, "/")
+	switch {
+	case found && (strings.ContainsAny(first, ".:") || first == "localhost"):
+		image = rest
+	case !found:
+		image = "library/" + image
+	}
+	return mirror + "/" + image
+}
+
+// toolCheckScript returns bash that fails the step when any of tools is not
+// on the PATH, so an override image that breaks the contract fails with a
+// clear message instead of a half-written credentials file
+func toolCheckScript(tools []string) string {
+	return fmt.Sprintf(`for tool in %s; do
+    if ! command -v "$tool" >/dev/null 2>&1; then
+        echo "ERROR: credential step image is missing required tool: $tool" >&2
+        exit 1
+    fi
+done
+`, strings.Join(tools, " "))
+}
+
+// withToolCheck inserts toolCheckScript after the script's "set -e" line
+func withToolCheck(script string, tools []string) string {
+	const preamble = "set -e\n"
+	i := strings.Index(script, preamble)
+	if i < 0 {
+		return script
+	}
+	i += len(preamble)
+	return script[:i] + toolCheckScript(tools) + script[i:]
+}
@@ -0,0 +1,69 @@
+package tekton
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMirrorImage(t *testing.T) {
+	tests := []struct {
+		image, mirror, want string
+	}{
+		{DefaultCredentialStepImage, "harbor.internal/dockerhub", "harbor.internal/dockerhub/facetscloud/actions-base-image:v1.0.0"},
+		{DefaultCredentialStepImage, "harbor.internal/dockerhub/", "harbor.internal/dockerhub/facetscloud/actions-base-image:v1.0.0"},
+		{"alpine:3.20", "harbor.internal", "harbor.internal/library/alpine:3.20"},
+		{"ghcr.io/facets-cloud/tools@sha256:abc", "harbor.internal/ghcr", "harbor.internal/ghcr/facets-cloud/tools@sha256:abc"},
+		{"localhost:5000/tools:v1", "harbor.internal", "harbor.internal/tools:v1"},
+		{DefaultCredentialStepImage, "", DefaultCredentialStepImage},
+	}
+
+	for _, tt := range tests {
+		if got := MirrorImage(tt.image, tt.mirror); got != tt.want {
+			t.Errorf("MirrorImage(%q, %q) = %q, want %q", tt.image, tt.mirror, got, tt.want)
+		}
+	}
+}
+
+func TestBuildKubernetesStepAction_CredentialStep(t *testing.T) {
+	stepAction := BuildKubernetesStepAction("setup-credentials-abc", "tekton-pipelines", nil, nil, CredentialStep{
+		Image:           "harbor.internal/facets/actions-base-image:v1.0.0",
+		ImagePullPolicy: "IfNotPresent",
+	}, "")
+
+	image, _, _ := unstructured.NestedString(stepAction.Object, "spec", "image")
+	if image != "harbor.internal/facets/actions-base-image:v1.0.0" {
+		t.Errorf("spec.image = %q", image)
+	}
+	script, _, _ := unstructured.NestedString(stepAction.Object, "spec", "script")
+	if !strings.HasPrefix(script, "#!/bin/bash\nset -e\nfor tool in bash mkdir base64; do") {
+		t.Errorf("script does not start with the tool check:\n%s", script)
+	}
+	if _, found, _ := unstructured.NestedString(stepAction.Object, "spec", "imagePullPolicy"); found {
+		t.Error("imagePullPolicy is not a StepAction field and must be set on the referencing step")
+	}
+}
+
+func TestCredentialStepApplyTo(t *testing.T) {
+	step := map[string]interface{}{"name": SetupCredentialsStepName}
+	CredentialStep{}.ApplyTo(step)
+	if _, ok := step["imagePullPolicy"]; ok {
+		t.Error("empty pull policy must not be rendered")
+	}
+
+	CredentialStep{ImagePullPolicy: "Always"}.ApplyTo(step)
+	if step["imagePullPolicy"] != "Always" {
+		t.Errorf("imagePullPolicy = %v, want Always", step["imagePullPolicy"])
+	}
+}
+
+func TestWithToolCheck(t *testing.T) {
+	if got := withToolCheck("", AWSCredentialStepTools); got != "" {
+		t.Errorf("empty script must stay empty, got %q", got)
+	}
+	got := withToolCheck("#!/bin/bash\nset -e\necho done\n", []string{"bash", "aws"})
+	if !strings.Contains(got, "for tool in bash aws; do") || !strings.HasSuffix(got, "done\necho done\n") {
+		t.Errorf("unexpected script:\n%s", got)
+	}
+}
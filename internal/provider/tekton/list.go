@@ -0,0 +1,94 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/dynamic"
+)
+
+// ListPageSize is the page size of List calls, so large namespaces are read
+// in several requests rather than one
+const ListPageSize = 100
+
+// ActionSelector selects actions by their Facets labels. Empty fields match
+// any value.
+type ActionSelector struct {
+	EnvUniqueName string
+	ResourceKind  string
+	ClusterID     string
+	// CloudAction selects AWS actions when true and Kubernetes actions when
+	// false
+	CloudAction *bool
+	// MatchLabels are further labels the actions must carry, e.g. custom labels
+	MatchLabels map[string]string
+}
+
+// LabelSelector returns the label selector of the actions. It always requires
+// the identity labels every action carries, so other Tasks in the namespace
+// are not selected. Values are sanitized like the labels themselves.
+func (s ActionSelector) LabelSelector() (labels.Selector, error) {
+	selector := labels.NewSelector()
+	for _, key := range []string{LabelDisplayName, LabelResourceName, LabelResourceKind, LabelEnvUniqueName} {
+		requirement, err := labels.NewRequirement(key, selection.Exists, nil)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*requirement)
+	}
+
+	equals := make(map[string]string, len(s.MatchLabels)+4)
+	for k, v := range s.MatchLabels {
+		equals[k] = v
+	}
+	if s.EnvUniqueName != "" {
+		equals[LabelEnvUniqueName] = SanitizeLabelValue(s.EnvUniqueName)
+	}
+	if s.ResourceKind != "" {
+		equals[LabelResourceKind] = SanitizeLabelValue(s.ResourceKind)
+	}
+	if s.ClusterID != "" {
+		equals[LabelClusterID] = SanitizeLabelValue(s.ClusterID)
+	}
+	if s.CloudAction != nil {
+		equals[LabelCloudAction] = formatBool(*s.CloudAction)
+	}
+
+	keys := make([]string, 0, len(equals))
+	for k := range equals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		requirement, err := labels.NewRequirement(key, selection.Equals, []string{equals[key]})
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %s=%s: %w", key, equals[key], err)
+		}
+		selector = selector.Add(*requirement)
+	}
+	return selector, nil
+}
+
+// ListAll lists the objects of a GVR in a namespace matching labelSelector,
+// following continue tokens page by page
+func ListAll(ctx context.Context, client dynamic.Interface, gvr k8sschema.GroupVersionResource, namespace, labelSelector string) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	opts := metav1.ListOptions{LabelSelector: labelSelector, Limit: ListPageSize}
+	for {
+		list, err := client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s in namespace %s: %w", gvr.Resource, namespace, err)
+		}
+		items = append(items, list.Items...)
+		if list.GetContinue() == "" {
+			return items, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
@@ -0,0 +1,84 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestActionSelector_LabelSelector(t *testing.T) {
+	cloud := false
+	tests := []struct {
+		name     string
+		selector ActionSelector
+		want     string
+	}{
+		{
+			name: "identity labels only",
+			want: "display_name,environment_unique_name,resource_kind,resource_name",
+		},
+		{
+			name: "filters",
+			selector: ActionSelector{
+				EnvUniqueName: "prod eu",
+				ResourceKind:  "service",
+				CloudAction:   &cloud,
+				MatchLabels:   map[string]string{"team": "platform"},
+			},
+			want: "cloud_action=false,display_name,environment_unique_name,environment_unique_name=" +
+				SanitizeLabelValue("prod eu") + ",resource_kind,resource_kind=service,resource_name,team=platform",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.selector.LabelSelector()
+			if err != nil {
+				t.Fatalf("LabelSelector() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("LabelSelector() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionSelector_InvalidLabel(t *testing.T) {
+	selector := ActionSelector{MatchLabels: map[string]string{"bad key!": "x"}}
+	if _, err := selector.LabelSelector(); err == nil {
+		t.Error("LabelSelector() error = nil, want an invalid label error")
+	}
+}
+
+func TestListAll_FollowsContinueTokens(t *testing.T) {
+	client := testfake.NewClient()
+	var requests []metav1.ListOptions
+	client.PrependReactor("list", testfake.TaskGVR.Resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+		opts := action.(clienttesting.ListActionImpl).ListOptions
+		requests = append(requests, opts)
+		page := len(requests)
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "tekton.dev/v1beta1", "kind": "TaskList"}}
+		list.Items = []unstructured.Unstructured{*testfake.Task("ns", fmt.Sprintf("task-%d", page), map[string]string{"a": "b"})}
+		if page < 3 {
+			list.SetContinue(fmt.Sprintf("token-%d", page))
+		}
+		return true, list, nil
+	})
+
+	items, err := ListAll(context.Background(), client, testfake.TaskGVR, "ns", "a=b")
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if len(items) != 3 || items[2].GetName() != "task-3" {
+		t.Errorf("ListAll() = %d items, want task-1 to task-3", len(items))
+	}
+	// The fake client records the label selector but not Limit or Continue
+	if len(requests) != 3 || requests[1].LabelSelector != "a=b" {
+		t.Errorf("ListAll() made %d requests %+v, want 3 with the label selector", len(requests), requests)
+	}
+}
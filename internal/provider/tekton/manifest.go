@@ -0,0 +1,57 @@
+package tekton
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Redacted replaces sensitive values in rendered manifests
+const Redacted = "(sensitive)"
+
+// Manifest renders an object as YAML for review in a plan. Keys are sorted, so
+// equal objects always render equally. Env vars read from the Secret
+// secretName, i.e. an action's sensitive_env, are redacted: the names of
+// sensitive_env variables are as sensitive as their values.
+func Manifest(obj *unstructured.Unstructured, secretName string) (string, error) {
+	// A JSON round trip copies the object, including the map[string]string
+	// values rendered objects hold, which a deep copy panics on
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	var copied map[string]interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return "", fmt.Errorf("failed to decode %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	redactSecretRefs(copied, secretName)
+
+	manifest, err := yaml.Marshal(copied)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s %s as YAML: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	return string(manifest), nil
+}
+
+// redactSecretRefs walks value and redacts the name and key of every env var
+// that reads from the Secret secretName
+func redactSecretRefs(value interface{}, secretName string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if name, _, _ := unstructured.NestedString(v, "valueFrom", "secretKeyRef", "name"); name == secretName {
+			v["name"] = Redacted
+			_ = unstructured.SetNestedField(v, Redacted, "valueFrom", "secretKeyRef", "key")
+			return
+		}
+		for _, field := range v {
+			redactSecretRefs(field, secretName)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactSecretRefs(item, secretName)
+		}
+	}
+}
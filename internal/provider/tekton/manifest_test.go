@@ -0,0 +1,66 @@
+package tekton
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestManifest(t *testing.T) {
+	task := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1beta1",
+		"kind":       "Task",
+		"metadata":   map[string]interface{}{"name": "abc", "namespace": "ns"},
+		"spec": map[string]interface{}{
+			"steps": []interface{}{
+				map[string]interface{}{
+					"name":  "deploy",
+					"image": "bitnami/kubectl:1.30",
+					"computeResources": map[string]interface{}{
+						"requests": map[string]string{"memory": "128Mi", "cpu": "100m"},
+					},
+					"env": []interface{}{
+						map[string]interface{}{"name": "PLAIN", "value": "visible"},
+						map[string]interface{}{"name": "API_TOKEN", "valueFrom": map[string]interface{}{
+							"secretKeyRef": map[string]interface{}{"name": EnvSecretName("abc"), "key": "deploy.API_TOKEN"},
+						}},
+						map[string]interface{}{"name": "OTHER", "valueFrom": map[string]interface{}{
+							"secretKeyRef": map[string]interface{}{"name": "user-secret", "key": "token"},
+						}},
+					},
+				},
+			},
+		},
+	}}
+
+	got, err := Manifest(task, EnvSecretName("abc"))
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+	for _, want := range []string{
+		"kind: Task\n",
+		"- name: PLAIN\n      value: visible\n",
+		"- name: " + Redacted + "\n      valueFrom:\n        secretKeyRef:\n          key: " + Redacted + "\n          name: abc-env\n",
+		"key: token\n",
+		"cpu: 100m\n        memory: 128Mi\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Manifest() = \n%s\nwant it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "API_TOKEN") {
+		t.Errorf("Manifest() = \n%s\nleaks a sensitive_env name", got)
+	}
+
+	// The object itself is left as is
+	step := task.Object["spec"].(map[string]interface{})["steps"].([]interface{})[0].(map[string]interface{})
+	if name := step["env"].([]interface{})[1].(map[string]interface{})["name"]; name != "API_TOKEN" {
+		t.Errorf("Manifest() modified the object, env name = %v", name)
+	}
+
+	again, _ := Manifest(task, EnvSecretName("abc"))
+	if again != got {
+		t.Error("Manifest() is not deterministic")
+	}
+}
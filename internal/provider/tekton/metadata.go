@@ -1,12 +1,60 @@
 package tekton
 
 import (
+	"crypto/sha256"
 	"fmt"
-	"os"
+	"regexp"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
+// Label keys set on every Task and StepAction. Values are sanitized with
+// SanitizeLabelValue and may therefore differ from the configured values.
+const (
+	LabelDisplayName   = "display_name"
+	LabelResourceName  = "resource_name"
+	LabelResourceKind  = "resource_kind"
+	LabelEnvUniqueName = "environment_unique_name"
+	LabelClusterID     = "cluster_id"
+	LabelCloudAction   = "cloud_action"
+)
+
+// Annotation keys holding the exact, unsanitized identity values. Labels are
+// limited to 63 label-safe characters; annotations are not, so these are the
+// source of truth when reading an object back (e.g. during import).
+const (
+	AnnotationDisplayName   = "facets.cloud/display-name"
+	AnnotationResourceName  = "facets.cloud/resource-name"
+	AnnotationResourceKind  = "facets.cloud/resource-kind"
+	AnnotationEnvUniqueName = "facets.cloud/environment-unique-name"
+	AnnotationClusterID     = "facets.cloud/cluster-id"
+)
+
+// Annotation keys that tell the Facets runner how to start a TaskRun for the
+// action. They are only set when an action departs from the default.
+const (
+	// AnnotationKubeconfigDelivery is set to KubeconfigDeliverySecretWorkspace
+	// when the runner must bind the user kubeconfig Secret to the
+	// WorkspaceFacetsUserKubeconfig workspace instead of passing the
+	// FACETS_USER_KUBECONFIG param
+	AnnotationKubeconfigDelivery = "facets.cloud/kubeconfig-delivery"
+	// AnnotationServiceAccount names the ServiceAccount the runner must run
+	// the TaskRun as, when the action has its own rbac
+	AnnotationServiceAccount = "facets.cloud/service-account"
+)
+
+// DefaultClusterID is the cluster_id label value used when no cluster ID is
+// configured on the provider, derived, or set in the CLUSTER_ID environment variable
+const DefaultClusterID = "na"
+
+// labelHashLength is the number of hex characters of the SHA-256 suffix
+// appended to label values that had to be sanitized
+const labelHashLength = 8
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
 // ResourceMetadata contains the metadata for a Tekton resource
 type ResourceMetadata struct {
 	DisplayName   string
@@ -16,24 +64,32 @@ type ResourceMetadata struct {
 	ClusterID     string
 	IsCloudAction bool
 	CustomLabels  map[string]string
+	// CustomAnnotations are merged with the facets.cloud/* annotations, with
+	// the facets.cloud/* annotations taking precedence
+	CustomAnnotations map[string]string
+	// RunnerAnnotations are facets.cloud/* annotations for the Facets runner,
+	// such as AnnotationKubeconfigDelivery. They take precedence over
+	// CustomAnnotations.
+	RunnerAnnotations map[string]string
 }
 
-// NewResourceMetadata creates ResourceMetadata with cluster ID from environment
-// customLabels are merged with auto-generated labels, with auto-generated taking precedence
-func NewResourceMetadata(displayName, resourceName, resourceKind, envUniqueName string, isCloudAction bool, customLabels map[string]string) *ResourceMetadata {
-	clusterID := os.Getenv("CLUSTER_ID")
+// NewResourceMetadata creates ResourceMetadata. An empty clusterID falls back
+// to DefaultClusterID. customLabels and customAnnotations are merged with
+// auto-generated labels and annotations, with auto-generated taking precedence
+func NewResourceMetadata(displayName, resourceName, resourceKind, envUniqueName, clusterID string, isCloudAction bool, customLabels, customAnnotations map[string]string) *ResourceMetadata {
 	if clusterID == "" {
-		clusterID = "na"
+		clusterID = DefaultClusterID
 	}
 
 	return &ResourceMetadata{
-		DisplayName:   displayName,
-		ResourceName:  resourceName,
-		ResourceKind:  resourceKind,
-		EnvUniqueName: envUniqueName,
-		ClusterID:     clusterID,
-		IsCloudAction: isCloudAction,
-		CustomLabels:  customLabels,
+		DisplayName:       displayName,
+		ResourceName:      resourceName,
+		ResourceKind:      resourceKind,
+		EnvUniqueName:     envUniqueName,
+		ClusterID:         clusterID,
+		IsCloudAction:     isCloudAction,
+		CustomLabels:      customLabels,
+		CustomAnnotations: customAnnotations,
 	}
 }
 
@@ -48,16 +104,140 @@ func (m *ResourceMetadata) Labels() map[string]string {
 	}
 
 	// Then, add auto-generated labels (these take precedence)
-	labels["display_name"] = m.DisplayName
-	labels["resource_name"] = m.ResourceName
-	labels["resource_kind"] = m.ResourceKind
-	labels["environment_unique_name"] = m.EnvUniqueName
-	labels["cluster_id"] = m.ClusterID
-	labels["cloud_action"] = formatBool(m.IsCloudAction)
+	labels[LabelDisplayName] = SanitizeLabelValue(m.DisplayName)
+	labels[LabelResourceName] = SanitizeLabelValue(m.ResourceName)
+	labels[LabelResourceKind] = SanitizeLabelValue(m.ResourceKind)
+	labels[LabelEnvUniqueName] = SanitizeLabelValue(m.EnvUniqueName)
+	labels[LabelClusterID] = SanitizeLabelValue(m.ClusterID)
+	labels[LabelCloudAction] = formatBool(m.IsCloudAction)
 
 	return labels
 }
 
+// Annotations returns Kubernetes annotations for this resource, recording the
+// exact identity values that may have been altered in Labels.
+// Custom annotations are included, with the facets.cloud/* annotations taking precedence
+func (m *ResourceMetadata) Annotations() map[string]string {
+	annotations := make(map[string]string)
+
+	for k, v := range m.CustomAnnotations {
+		annotations[k] = v
+	}
+	for k, v := range m.RunnerAnnotations {
+		annotations[k] = v
+	}
+
+	annotations[AnnotationDisplayName] = m.DisplayName
+	annotations[AnnotationResourceName] = m.ResourceName
+	annotations[AnnotationResourceKind] = m.ResourceKind
+	annotations[AnnotationEnvUniqueName] = m.EnvUniqueName
+	annotations[AnnotationClusterID] = m.ClusterID
+
+	return annotations
+}
+
+// AnnotationsAsInterface returns annotations as map[string]interface{} for unstructured objects
+func (m *ResourceMetadata) AnnotationsAsInterface() map[string]interface{} {
+	annotations := m.Annotations()
+	result := make(map[string]interface{}, len(annotations))
+	for k, v := range annotations {
+		result[k] = v
+	}
+	return result
+}
+
+// SanitizeLabelValue turns an arbitrary string into a valid Kubernetes label
+// value. Values that are already valid are returned unchanged, so existing
+// objects keep their labels. Otherwise invalid characters are replaced with
+// '-', the value is trimmed to start and end with an alphanumeric character,
+// truncated, and suffixed with a short hash of the original so that distinct
+// inputs (e.g. "a b" and "a-b") never map to the same label value.
+func SanitizeLabelValue(value string) string {
+	if len(k8svalidation.IsValidLabelValue(value)) == 0 {
+		return value
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(value)))[:labelHashLength]
+
+	sanitized := invalidLabelValueChars.ReplaceAllString(value, "-")
+	maxPrefix := k8svalidation.LabelValueMaxLength - labelHashLength - 1
+	if len(sanitized) > maxPrefix {
+		sanitized = sanitized[:maxPrefix]
+	}
+	sanitized = strings.TrimFunc(sanitized, func(r rune) bool {
+		return !isAlphanumeric(r)
+	})
+
+	if sanitized == "" {
+		return hash
+	}
+	return sanitized + "-" + hash
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// MetadataFromObject reconstructs the ResourceMetadata of a Task or StepAction
+// created by this provider. Identity values are read from the facets.cloud/*
+// annotations, falling back to the labels for objects created before the
+// annotations existed. Labels that are not auto-generated are returned as
+// CustomLabels; annotations outside facets.cloud/* are returned as
+// CustomAnnotations.
+func MetadataFromObject(obj *unstructured.Unstructured) (*ResourceMetadata, error) {
+	labels := obj.GetLabels()
+	annotations := obj.GetAnnotations()
+
+	value := func(annotationKey, labelKey string) (string, bool) {
+		if v, ok := annotations[annotationKey]; ok {
+			return v, true
+		}
+		v, ok := labels[labelKey]
+		return v, ok
+	}
+
+	displayName, hasDisplayName := value(AnnotationDisplayName, LabelDisplayName)
+	resourceName, hasResourceName := value(AnnotationResourceName, LabelResourceName)
+	resourceKind, hasResourceKind := value(AnnotationResourceKind, LabelResourceKind)
+	envUniqueName, hasEnvUniqueName := value(AnnotationEnvUniqueName, LabelEnvUniqueName)
+	clusterID, _ := value(AnnotationClusterID, LabelClusterID)
+
+	if !hasDisplayName || !hasResourceName || !hasResourceKind || !hasEnvUniqueName {
+		return nil, fmt.Errorf("%s %s/%s missing required labels: %s, %s, %s, %s",
+			obj.GetKind(), obj.GetNamespace(), obj.GetName(),
+			LabelDisplayName, LabelResourceName, LabelResourceKind, LabelEnvUniqueName)
+	}
+
+	customLabels := make(map[string]string)
+	for k, v := range labels {
+		switch k {
+		case LabelDisplayName, LabelResourceName, LabelResourceKind, LabelEnvUniqueName, LabelClusterID, LabelCloudAction:
+			continue
+		}
+		customLabels[k] = v
+	}
+
+	customAnnotations := make(map[string]string)
+	for k, v := range annotations {
+		switch k {
+		case AnnotationDisplayName, AnnotationResourceName, AnnotationResourceKind, AnnotationEnvUniqueName, AnnotationClusterID:
+			continue
+		}
+		customAnnotations[k] = v
+	}
+
+	return &ResourceMetadata{
+		DisplayName:       displayName,
+		ResourceName:      resourceName,
+		ResourceKind:      resourceKind,
+		EnvUniqueName:     envUniqueName,
+		ClusterID:         clusterID,
+		IsCloudAction:     labels[LabelCloudAction] == "true",
+		CustomLabels:      customLabels,
+		CustomAnnotations: customAnnotations,
+	}, nil
+}
+
 // LabelsAsInterface returns labels as map[string]interface{} for unstructured objects
 func (m *ResourceMetadata) LabelsAsInterface() map[string]interface{} {
 	labels := m.Labels()
@@ -0,0 +1,183 @@
+package tekton
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestSanitizeLabelValue_ValidUnchanged(t *testing.T) {
+	for _, v := range []string{"", "my-action", "restart_api.v2", "A1", strings.Repeat("a", 63)} {
+		if got := SanitizeLabelValue(v); got != v {
+			t.Errorf("SanitizeLabelValue(%q) = %q, want unchanged", v, got)
+		}
+	}
+}
+
+func TestSanitizeLabelValue_InvalidProducesValidLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		wantPrefix string
+	}{
+		{"spaces", "Restart API pods", "Restart-API-pods-"},
+		{"too long", strings.Repeat("a", 100), strings.Repeat("a", 54) + "-"},
+		{"leading and trailing symbols", "  (deploy)  ", "deploy-"},
+		{"slashes", "team/app", "team-app-"},
+		{"only symbols", "!!!", ""},
+		{"long with trailing separator at cut", strings.Repeat("a", 53) + " b" + strings.Repeat("c", 20), strings.Repeat("a", 53) + "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeLabelValue(tt.value)
+			if errs := k8svalidation.IsValidLabelValue(got); len(errs) > 0 {
+				t.Fatalf("SanitizeLabelValue(%q) = %q is not a valid label value: %v", tt.value, got, errs)
+			}
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("SanitizeLabelValue(%q) = %q, want prefix %q", tt.value, got, tt.wantPrefix)
+			}
+			if got != SanitizeLabelValue(tt.value) {
+				t.Errorf("SanitizeLabelValue(%q) is not deterministic", tt.value)
+			}
+		})
+	}
+}
+
+func TestSanitizeLabelValue_DistinctInputsDoNotCollide(t *testing.T) {
+	a := SanitizeLabelValue("restart api")
+	b := SanitizeLabelValue("restart/api")
+	if a == b {
+		t.Errorf("distinct inputs collided: %q", a)
+	}
+	if SanitizeLabelValue("restart-api") == a {
+		t.Errorf("sanitized value must not collide with an already-valid value")
+	}
+}
+
+func TestResourceMetadata_LabelsSanitizedAnnotationsExact(t *testing.T) {
+	m := &ResourceMetadata{
+		DisplayName:   "Restart API pods",
+		ResourceName:  "my-app",
+		ResourceKind:  "service",
+		EnvUniqueName: strings.Repeat("env", 30),
+		ClusterID:     "cluster-01",
+	}
+
+	labels := m.Labels()
+	for k, v := range labels {
+		if errs := k8svalidation.IsValidLabelValue(v); len(errs) > 0 {
+			t.Errorf("label %s=%q is not a valid label value: %v", k, v, errs)
+		}
+	}
+	if labels[LabelResourceName] != "my-app" {
+		t.Errorf("valid label value changed: %q", labels[LabelResourceName])
+	}
+
+	annotations := m.Annotations()
+	if annotations[AnnotationDisplayName] != "Restart API pods" {
+		t.Errorf("annotation %s = %q, want exact display name", AnnotationDisplayName, annotations[AnnotationDisplayName])
+	}
+	if annotations[AnnotationEnvUniqueName] != m.EnvUniqueName {
+		t.Errorf("annotation %s = %q, want exact environment name", AnnotationEnvUniqueName, annotations[AnnotationEnvUniqueName])
+	}
+}
+
+func TestMetadataFromObject_PrefersAnnotations(t *testing.T) {
+	m := &ResourceMetadata{
+		DisplayName:   "Restart API pods",
+		ResourceName:  "my-app",
+		ResourceKind:  "service",
+		EnvUniqueName: "production",
+		ClusterID:     "cluster-01",
+		IsCloudAction: true,
+		CustomLabels:  map[string]string{"team": "platform"},
+	}
+	obj := testfake.Task(testNamespace, "task-1", m.Labels())
+	obj.SetAnnotations(m.Annotations())
+
+	got, err := MetadataFromObject(obj)
+	if err != nil {
+		t.Fatalf("MetadataFromObject failed: %v", err)
+	}
+	if got.DisplayName != "Restart API pods" {
+		t.Errorf("DisplayName = %q, want exact value from annotation", got.DisplayName)
+	}
+	if got.ClusterID != "cluster-01" || !got.IsCloudAction {
+		t.Errorf("ClusterID/IsCloudAction = %q/%v, want cluster-01/true", got.ClusterID, got.IsCloudAction)
+	}
+	if len(got.CustomLabels) != 1 || got.CustomLabels["team"] != "platform" {
+		t.Errorf("CustomLabels = %v, want only team=platform", got.CustomLabels)
+	}
+}
+
+func TestMetadataFromObject_FallsBackToLabels(t *testing.T) {
+	obj := testfake.Task(testNamespace, "task-1", map[string]string{
+		LabelDisplayName:   "legacy-action",
+		LabelResourceName:  "my-app",
+		LabelResourceKind:  "service",
+		LabelEnvUniqueName: "production",
+	})
+
+	got, err := MetadataFromObject(obj)
+	if err != nil {
+		t.Fatalf("MetadataFromObject failed: %v", err)
+	}
+	if got.DisplayName != "legacy-action" || got.EnvUniqueName != "production" {
+		t.Errorf("got %+v, want values from labels", got)
+	}
+}
+
+func TestMetadataFromObject_MissingIdentity(t *testing.T) {
+	obj := testfake.Task(testNamespace, "task-1", map[string]string{LabelDisplayName: "x"})
+	if _, err := MetadataFromObject(obj); err == nil {
+		t.Fatal("expected error for Task without identity labels")
+	}
+}
+
+func TestResourceMetadata_CustomAnnotationsPrecedence(t *testing.T) {
+	m := NewResourceMetadata("my-action", "my-app", "service", "production", "", false, nil, map[string]string{
+		"cost-center":         "engineering",
+		AnnotationDisplayName: "override-attempt",
+	})
+
+	annotations := m.Annotations()
+	if annotations["cost-center"] != "engineering" {
+		t.Errorf("custom annotation missing, got %v", annotations)
+	}
+	if annotations[AnnotationDisplayName] != "my-action" {
+		t.Errorf("%s = %q, want facets.cloud annotation to take precedence", AnnotationDisplayName, annotations[AnnotationDisplayName])
+	}
+}
+
+func TestNewResourceMetadata_ClusterID(t *testing.T) {
+	t.Setenv("CLUSTER_ID", "from-env")
+
+	m := NewResourceMetadata("my-action", "my-app", "service", "production", "", false, nil, nil)
+	if m.ClusterID != DefaultClusterID {
+		t.Errorf("ClusterID = %q, want %q (the environment must not be consulted)", m.ClusterID, DefaultClusterID)
+	}
+
+	m = NewResourceMetadata("my-action", "my-app", "service", "production", "prod/eu-west-1", false, nil, nil)
+	if got := m.Annotations()[AnnotationClusterID]; got != "prod/eu-west-1" {
+		t.Errorf("%s = %q, want exact cluster ID", AnnotationClusterID, got)
+	}
+	if got := m.Labels()[LabelClusterID]; got == "prod/eu-west-1" {
+		t.Errorf("%s label = %q, want a sanitized value", LabelClusterID, got)
+	}
+
+	obj := testfake.Task(testNamespace, "task-1", m.Labels())
+	obj.SetAnnotations(m.Annotations())
+	got, err := MetadataFromObject(obj)
+	if err != nil {
+		t.Fatalf("MetadataFromObject failed: %v", err)
+	}
+	if got.ClusterID != "prod/eu-west-1" {
+		t.Errorf("ClusterID = %q, want exact value from annotation", got.ClusterID)
+	}
+	if _, ok := got.CustomAnnotations[AnnotationClusterID]; ok {
+		t.Errorf("%s must not be returned as a custom annotation", AnnotationClusterID)
+	}
+}
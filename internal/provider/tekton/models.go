@@ -1,6 +1,7 @@
 package tekton
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -22,11 +23,12 @@ type ParamModel struct {
 
 // StepModel represents a Tekton Task step
 type StepModel struct {
-	Name      types.String `tfsdk:"name"`
-	Image     types.String `tfsdk:"image"`
-	Script    types.String `tfsdk:"script"`
-	Resources types.Object `tfsdk:"resources"`
-	Env       types.List   `tfsdk:"env"`
+	Name         types.String `tfsdk:"name"`
+	Image        types.String `tfsdk:"image"`
+	Script       types.String `tfsdk:"script"`
+	Resources    types.Object `tfsdk:"resources"`
+	Env          types.List   `tfsdk:"env"`
+	SensitiveEnv types.Map    `tfsdk:"sensitive_env"`
 }
 
 // ComputeResourcesModel represents compute resources for a step
@@ -40,3 +42,39 @@ type EnvVarModel struct {
 	Name  types.String `tfsdk:"name"`
 	Value types.String `tfsdk:"value"`
 }
+
+// EnvVarObjectType is the Terraform object type of an env list element
+var EnvVarObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":  types.StringType,
+		"value": types.StringType,
+	},
+}
+
+// ComputeResourcesObjectType is the Terraform object type of a step's resources
+var ComputeResourcesObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"requests": types.MapType{ElemType: types.StringType},
+		"limits":   types.MapType{ElemType: types.StringType},
+	},
+}
+
+// StepObjectType is the Terraform object type of a steps list element
+var StepObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":          types.StringType,
+		"image":         types.StringType,
+		"script":        types.StringType,
+		"resources":     ComputeResourcesObjectType,
+		"env":           types.ListType{ElemType: EnvVarObjectType},
+		"sensitive_env": types.MapType{ElemType: types.StringType},
+	},
+}
+
+// ParamObjectType is the Terraform object type of a params list element
+var ParamObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name": types.StringType,
+		"type": types.StringType,
+	},
+}
@@ -7,8 +7,9 @@ import (
 
 // ResourceNames holds the generated names for a Tekton resource
 type ResourceNames struct {
-	TaskName       string
-	StepActionName string
+	TaskName           string
+	StepActionName     string
+	ServiceAccountName string
 }
 
 // GenerateNames creates deterministic names for Task and StepAction
@@ -32,7 +33,35 @@ func GenerateNames(resourceName, envName, displayName string) *ResourceNames {
 	}
 
 	return &ResourceNames{
-		TaskName:       taskName,
-		StepActionName: stepActionName,
+		TaskName:           taskName,
+		StepActionName:     stepActionName,
+		ServiceAccountName: ServiceAccountName(taskName),
 	}
 }
+
+// ServiceAccountName returns the name of the ServiceAccount, Role and
+// RoleBinding generated for an action's rbac. It is derived from the Task
+// name, which carries the action's hash.
+func ServiceAccountName(taskName string) string {
+	return "facets-action-" + taskName
+}
+
+// maxObjectNameLength is the longest name the apiserver accepts for RBAC objects
+const maxObjectNameLength = 253
+
+// ClusterRBACName returns the name of the ClusterRole and ClusterRoleBinding
+// generated for an action's rbac. Cluster-scoped names must be unique across
+// namespaces, so the namespace is part of the name. The parts are joined with
+// "-" rather than ":" so rendered manifests get portable file names. Names
+// that would be too long use the MD5 hash of the namespace instead.
+func ClusterRBACName(namespace, serviceAccountName string) string {
+	name := "facets-" + namespace + "-" + serviceAccountName
+	if len(name) > maxObjectNameLength {
+		name = fmt.Sprintf("facets-%x-%s", md5.Sum([]byte(namespace)), serviceAccountName)
+	}
+	if len(name) > maxObjectNameLength {
+		// Keep the end, which carries the action's hash
+		name = name[len(name)-maxObjectNameLength:]
+	}
+	return name
+}
@@ -0,0 +1,121 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// GroupVersion of the Tasks and StepActions the provider creates
+const GroupVersion = "tekton.dev/v1beta1"
+
+// PreflightTarget is where an action is about to be created
+type PreflightTarget struct {
+	// Namespace of the Task and StepAction
+	Namespace string
+	// ServiceAccount the Facets runner starts TaskRuns as. Empty when the
+	// action creates its own ServiceAccount, which is then not checked.
+	ServiceAccount string
+	// TektonNamespace holds Tekton's feature-flags ConfigMap
+	TektonNamespace string
+}
+
+// Preflight checks the cluster prerequisites of an action before anything is
+// written, returning one error per missing prerequisite: the Task and
+// StepAction APIs, the enable-step-actions feature flag, the namespace and the
+// runner ServiceAccount. A missing enable-step-actions flag is not reported,
+// since newer Tekton releases enable StepActions without it. Checks that fail for other reasons, e.g. Forbidden
+// when the Terraform identity may not read namespaces, are skipped rather than
+// reported, since they do not show a prerequisite is missing.
+func Preflight(ctx context.Context, disc discovery.ServerResourcesInterface, client dynamic.Interface, target PreflightTarget) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(checkTektonAPIs(ctx, disc, client, target.TektonNamespace)...)
+
+	_, err := client.Resource(NamespaceGVR).Get(ctx, target.Namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		diags.AddError(
+			"Namespace not found",
+			fmt.Sprintf("Namespace %q does not exist. Create it first, e.g. with the facets_tekton_runtime resource, "+
+				"or set namespace to the namespace Tekton actions run in.", target.Namespace),
+		)
+		// The ServiceAccount cannot exist either
+		return diags
+	}
+
+	if target.ServiceAccount == "" {
+		return diags
+	}
+	_, err = client.Resource(ServiceAccountGVR).Namespace(target.Namespace).Get(ctx, target.ServiceAccount, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		diags.AddError(
+			"Runner ServiceAccount not found",
+			fmt.Sprintf("ServiceAccount %s/%s, which the Facets runner starts TaskRuns as, does not exist, so the action "+
+				"could not run. Create it, e.g. with the facets_tekton_runtime resource, or give the action an rbac "+
+				"block so it gets its own ServiceAccount.", target.Namespace, target.ServiceAccount),
+		)
+	}
+	return diags
+}
+
+// checkTektonAPIs checks that Tasks and StepActions are served and that
+// StepActions are enabled
+func checkTektonAPIs(ctx context.Context, disc discovery.ServerResourcesInterface, client dynamic.Interface, tektonNamespace string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	resources, err := disc.ServerResourcesForGroupVersion(GroupVersion)
+	switch {
+	case apierrors.IsNotFound(err):
+		diags.AddError(
+			"Tekton Pipelines not installed",
+			fmt.Sprintf("The cluster does not serve %s, so Tasks and StepActions cannot be created. "+
+				"Install Tekton Pipelines v0.54 or later, or check that the provider's kubernetes block targets the right cluster.", GroupVersion),
+		)
+		return diags
+	case err != nil:
+		// Discovery failures other than NotFound are left to the writes
+		// that follow, which report them in context
+		return diags
+	}
+
+	served := make(map[string]bool, len(resources.APIResources))
+	for _, r := range resources.APIResources {
+		served[r.Name] = true
+	}
+	if !served["tasks"] {
+		diags.AddError(
+			"Tekton Tasks not served",
+			fmt.Sprintf("The cluster serves %s but not tasks. Check the Tekton Pipelines installation.", GroupVersion),
+		)
+	}
+	if !served["stepactions"] {
+		diags.AddError(
+			"Tekton StepActions not served",
+			fmt.Sprintf("The cluster serves %s but not stepactions, which every action's setup-credentials step references. "+
+				"Upgrade Tekton Pipelines to v0.54 or later.", GroupVersion),
+		)
+		return diags
+	}
+
+	featureFlags, err := client.Resource(ConfigMapGVR).Namespace(tektonNamespace).Get(ctx, FeatureFlagsConfigMap, metav1.GetOptions{})
+	if err != nil {
+		// Tekton may be installed in another namespace, e.g. openshift-pipelines
+		return diags
+	}
+	// Releases that enable StepActions by default ship without the flag, so
+	// only a flag that is set to something else disables them
+	if value, found := FeatureFlag(featureFlags, FeatureFlagEnableStepActions); found && value != "true" {
+		diags.AddError(
+			"Tekton StepActions disabled",
+			fmt.Sprintf("%s is not \"true\" in ConfigMap %s/%s, so Tekton rejects StepActions. "+
+				"Set it, e.g. with the facets_tekton_runtime resource (enable_step_actions = true).",
+				FeatureFlagEnableStepActions, tektonNamespace, FeatureFlagsConfigMap),
+		)
+	}
+	return diags
+}
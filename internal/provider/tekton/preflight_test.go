@@ -0,0 +1,143 @@
+package tekton
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// fakeDiscovery serves the given tekton.dev/v1beta1 resources, or nothing
+// when none are given
+func fakeDiscovery(resources ...string) *fakediscovery.FakeDiscovery {
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	if len(resources) == 0 {
+		return disc
+	}
+	list := &metav1.APIResourceList{GroupVersion: GroupVersion}
+	for _, r := range resources {
+		list.APIResources = append(list.APIResources, metav1.APIResource{Name: r, Namespaced: true})
+	}
+	disc.Resources = []*metav1.APIResourceList{list}
+	return disc
+}
+
+func testPreflightTarget() PreflightTarget {
+	return PreflightTarget{Namespace: "actions", ServiceAccount: DefaultRunnerServiceAccount, TektonNamespace: "tekton-pipelines"}
+}
+
+func TestPreflight_AllPresent(t *testing.T) {
+	client := testfake.NewClient(
+		testfake.Namespace("actions", "uid"),
+		BuildRunnerServiceAccount(DefaultRunnerServiceAccount, "actions", nil, nil),
+		testfake.ConfigMap("tekton-pipelines", FeatureFlagsConfigMap, map[string]string{FeatureFlagEnableStepActions: "true"}),
+	)
+	if diags := Preflight(context.Background(), fakeDiscovery("tasks", "stepactions"), client, testPreflightTarget()); len(diags) != 0 {
+		t.Errorf("Preflight() = %v, want no diagnostics", diags)
+	}
+}
+
+func TestPreflight_OneDiagnosticPerMissingPrerequisite(t *testing.T) {
+	tests := []struct {
+		name      string
+		discovery *fakediscovery.FakeDiscovery
+		flag      string
+		// flagMissing creates the feature-flags ConfigMap without flag
+		flagMissing bool
+		namespace   bool
+		sa          bool
+		target      func(PreflightTarget) PreflightTarget
+		want        []string
+	}{
+		{
+			name:      "tekton not installed",
+			discovery: fakeDiscovery(),
+			namespace: true, sa: true,
+			want: []string{"Tekton Pipelines not installed"},
+		},
+		{
+			name:      "step actions not served",
+			discovery: fakeDiscovery("tasks"),
+			flag:      "true", namespace: true, sa: true,
+			want: []string{"Tekton StepActions not served"},
+		},
+		{
+			name:      "step actions disabled",
+			discovery: fakeDiscovery("tasks", "stepactions"),
+			flag:      "false", namespace: true, sa: true,
+			want: []string{"Tekton StepActions disabled"},
+		},
+		{
+			name:        "step actions enabled by default",
+			discovery:   fakeDiscovery("tasks", "stepactions"),
+			flagMissing: true, namespace: true, sa: true,
+		},
+		{
+			name:      "namespace missing",
+			discovery: fakeDiscovery("tasks", "stepactions"),
+			flag:      "true",
+			want:      []string{"Namespace not found"},
+		},
+		{
+			name:      "runner service account missing",
+			discovery: fakeDiscovery("tasks", "stepactions"),
+			flag:      "true", namespace: true,
+			want: []string{"Runner ServiceAccount not found"},
+		},
+		{
+			name:      "action with its own service account",
+			discovery: fakeDiscovery("tasks", "stepactions"),
+			flag:      "true", namespace: true,
+			target: func(t PreflightTarget) PreflightTarget {
+				t.ServiceAccount = ""
+				return t
+			},
+		},
+		{
+			name:      "everything missing",
+			discovery: fakeDiscovery("tasks", "stepactions"),
+			flag:      "false",
+			want:      []string{"Tekton StepActions disabled", "Namespace not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if tt.flagMissing {
+				objects = append(objects, testfake.ConfigMap("tekton-pipelines", FeatureFlagsConfigMap, map[string]string{"enable-api-fields": "beta"}))
+			}
+			if tt.flag != "" {
+				objects = append(objects, testfake.ConfigMap("tekton-pipelines", FeatureFlagsConfigMap, map[string]string{FeatureFlagEnableStepActions: tt.flag}))
+			}
+			if tt.namespace {
+				objects = append(objects, testfake.Namespace("actions", "uid"))
+			}
+			if tt.sa {
+				objects = append(objects, BuildRunnerServiceAccount(DefaultRunnerServiceAccount, "actions", nil, nil))
+			}
+			target := testPreflightTarget()
+			if tt.target != nil {
+				target = tt.target(target)
+			}
+
+			diags := Preflight(context.Background(), tt.discovery, testfake.NewClient(objects...), target)
+			var got []string
+			for _, d := range diags {
+				got = append(got, d.Summary())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Preflight() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("diagnostic %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
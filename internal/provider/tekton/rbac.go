@@ -0,0 +1,129 @@
+package tekton
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GVRs of the objects generated for an action's rbac
+var (
+	ServiceAccountGVR     = k8sschema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	RoleGVR               = k8sschema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}
+	RoleBindingGVR        = k8sschema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+	ClusterRoleGVR        = k8sschema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	ClusterRoleBindingGVR = k8sschema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}
+)
+
+// PolicyRule is a rule of an action's Role or ClusterRole
+type PolicyRule struct {
+	APIGroups     []string
+	Resources     []string
+	ResourceNames []string
+	Verbs         []string
+}
+
+// RBACObject is an object generated for an action's rbac, with the resource
+// it is served as
+type RBACObject struct {
+	Resource k8sschema.GroupVersionResource
+	Object   *unstructured.Unstructured
+}
+
+// Key identifies the object across scopes, e.g. to find stale objects after
+// an action switched between namespaced and cluster-scoped rbac
+func (o RBACObject) Key() string {
+	return o.Resource.String() + "/" + o.Object.GetNamespace() + "/" + o.Object.GetName()
+}
+
+// RBACRefs returns the ServiceAccount, Role or ClusterRole, and binding of an
+// action, in creation order, with only their names and namespaces set. They
+// are enough to delete the objects.
+func RBACRefs(serviceAccountName, namespace string, clusterScoped bool) []RBACObject {
+	ref := func(gvr k8sschema.GroupVersionResource, kind, namespace, name string) RBACObject {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(gvr.GroupVersion().String())
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return RBACObject{Resource: gvr, Object: obj}
+	}
+
+	serviceAccount := ref(ServiceAccountGVR, "ServiceAccount", namespace, serviceAccountName)
+	if clusterScoped {
+		name := ClusterRBACName(namespace, serviceAccountName)
+		return []RBACObject{
+			serviceAccount,
+			ref(ClusterRoleGVR, "ClusterRole", "", name),
+			ref(ClusterRoleBindingGVR, "ClusterRoleBinding", "", name),
+		}
+	}
+	return []RBACObject{
+		serviceAccount,
+		ref(RoleGVR, "Role", namespace, serviceAccountName),
+		ref(RoleBindingGVR, "RoleBinding", namespace, serviceAccountName),
+	}
+}
+
+// BuildRBACObjects creates an action's ServiceAccount, a Role (or ClusterRole
+// when clusterScoped) with rules, and the binding between them, in creation
+// order. All of them carry the action's labels and annotations.
+func BuildRBACObjects(serviceAccountName, namespace string, labels, annotations map[string]interface{}, clusterScoped bool, rules []PolicyRule) []RBACObject {
+	objects := RBACRefs(serviceAccountName, namespace, clusterScoped)
+	for _, o := range objects {
+		o.Object.Object["metadata"].(map[string]interface{})["labels"] = labels
+		o.Object.Object["metadata"].(map[string]interface{})["annotations"] = annotations
+	}
+
+	ruleList := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		r := map[string]interface{}{
+			"apiGroups": stringsAsInterface(rule.APIGroups),
+			"resources": stringsAsInterface(rule.Resources),
+			"verbs":     stringsAsInterface(rule.Verbs),
+		}
+		if len(rule.ResourceNames) > 0 {
+			r["resourceNames"] = stringsAsInterface(rule.ResourceNames)
+		}
+		ruleList = append(ruleList, r)
+	}
+
+	role, binding := objects[1].Object, objects[2].Object
+	role.Object["rules"] = ruleList
+	binding.Object["roleRef"] = map[string]interface{}{
+		"apiGroup": RoleGVR.Group,
+		"kind":     role.GetKind(),
+		"name":     role.GetName(),
+	}
+	binding.Object["subjects"] = []interface{}{
+		map[string]interface{}{
+			"kind":      "ServiceAccount",
+			"name":      serviceAccountName,
+			"namespace": namespace,
+		},
+	}
+	return objects
+}
+
+// StaleRBAC returns the objects of previous that are not in current, e.g.
+// the Role and RoleBinding after an action switched to cluster-scoped rbac
+func StaleRBAC(previous, current []RBACObject) []RBACObject {
+	keep := make(map[string]bool, len(current))
+	for _, o := range current {
+		keep[o.Key()] = true
+	}
+	var stale []RBACObject
+	for _, o := range previous {
+		if !keep[o.Key()] {
+			stale = append(stale, o)
+		}
+	}
+	return stale
+}
+
+func stringsAsInterface(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
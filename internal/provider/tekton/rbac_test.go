@@ -0,0 +1,103 @@
+package tekton
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBuildRBACObjects_Namespaced(t *testing.T) {
+	rules := []PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "patch"}}}
+	objects := BuildRBACObjects("facets-action-abc", "tekton-pipelines", map[string]interface{}{"team": "payments"}, nil, false, rules)
+
+	var kinds []string
+	for _, o := range objects {
+		kinds = append(kinds, o.Object.GetKind())
+		if o.Object.GetName() != "facets-action-abc" || o.Object.GetNamespace() != "tekton-pipelines" {
+			t.Errorf("%s = %s/%s", o.Object.GetKind(), o.Object.GetNamespace(), o.Object.GetName())
+		}
+		if o.Object.GetLabels()["team"] != "payments" {
+			t.Errorf("%s labels = %v", o.Object.GetKind(), o.Object.GetLabels())
+		}
+	}
+	if want := []string{"ServiceAccount", "Role", "RoleBinding"}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+
+	gotRules, _, _ := unstructured.NestedSlice(objects[1].Object.Object, "rules")
+	wantRules := []interface{}{map[string]interface{}{
+		"apiGroups": []interface{}{"apps"},
+		"resources": []interface{}{"deployments"},
+		"verbs":     []interface{}{"get", "patch"},
+	}}
+	if !reflect.DeepEqual(gotRules, wantRules) {
+		t.Errorf("rules = %v, want %v", gotRules, wantRules)
+	}
+
+	roleRef, _, _ := unstructured.NestedStringMap(objects[2].Object.Object, "roleRef")
+	if roleRef["kind"] != "Role" || roleRef["name"] != "facets-action-abc" {
+		t.Errorf("roleRef = %v", roleRef)
+	}
+	subjects, _, _ := unstructured.NestedSlice(objects[2].Object.Object, "subjects")
+	wantSubjects := []interface{}{map[string]interface{}{"kind": "ServiceAccount", "name": "facets-action-abc", "namespace": "tekton-pipelines"}}
+	if !reflect.DeepEqual(subjects, wantSubjects) {
+		t.Errorf("subjects = %v, want %v", subjects, wantSubjects)
+	}
+}
+
+func TestBuildRBACObjects_ClusterScoped(t *testing.T) {
+	objects := BuildRBACObjects("facets-action-abc", "tekton-pipelines", nil, nil, true, nil)
+
+	if objects[0].Object.GetKind() != "ServiceAccount" || objects[0].Object.GetNamespace() != "tekton-pipelines" {
+		t.Errorf("ServiceAccount = %v", objects[0].Object.Object)
+	}
+	for _, o := range objects[1:] {
+		if o.Object.GetNamespace() != "" || o.Object.GetName() != "facets-tekton-pipelines-facets-action-abc" {
+			t.Errorf("%s = %q/%q, want cluster-scoped facets-tekton-pipelines-facets-action-abc", o.Object.GetKind(), o.Object.GetNamespace(), o.Object.GetName())
+		}
+	}
+	if objects[1].Resource != ClusterRoleGVR || objects[2].Resource != ClusterRoleBindingGVR {
+		t.Errorf("resources = %v, %v", objects[1].Resource, objects[2].Resource)
+	}
+	roleRef, _, _ := unstructured.NestedStringMap(objects[2].Object.Object, "roleRef")
+	if roleRef["kind"] != "ClusterRole" {
+		t.Errorf("roleRef = %v", roleRef)
+	}
+}
+
+func TestStaleRBAC(t *testing.T) {
+	namespaced := RBACRefs("facets-action-abc", "tekton-pipelines", false)
+	clusterScoped := RBACRefs("facets-action-abc", "tekton-pipelines", true)
+
+	stale := StaleRBAC(namespaced, clusterScoped)
+	var kinds []string
+	for _, o := range stale {
+		kinds = append(kinds, o.Object.GetKind())
+	}
+	if want := []string{"Role", "RoleBinding"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("stale = %v, want %v", kinds, want)
+	}
+
+	if stale := StaleRBAC(namespaced, nil); len(stale) != 3 {
+		t.Errorf("stale without rbac = %d objects, want 3", len(stale))
+	}
+	if stale := StaleRBAC(namespaced, namespaced); len(stale) != 0 {
+		t.Errorf("stale with unchanged rbac = %v, want none", stale)
+	}
+}
+
+func TestClusterRBACName(t *testing.T) {
+	if got, want := ClusterRBACName("ops", "facets-action-abc"), "facets-ops-facets-action-abc"; got != want {
+		t.Errorf("ClusterRBACName() = %q, want %q", got, want)
+	}
+
+	long := ClusterRBACName(strings.Repeat("n", 250), "facets-action-abc")
+	if len(long) > maxObjectNameLength || !strings.HasSuffix(long, "-facets-action-abc") {
+		t.Errorf("ClusterRBACName() = %q (%d characters), want at most %d ending in the ServiceAccount name", long, len(long), maxObjectNameLength)
+	}
+	if other := ClusterRBACName(strings.Repeat("m", 250), "facets-action-abc"); other == long {
+		t.Errorf("expected distinct names for distinct namespaces, got %q", other)
+	}
+}
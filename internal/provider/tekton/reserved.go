@@ -0,0 +1,56 @@
+package tekton
+
+// Names injected into every rendered Task by the provider. User-defined steps,
+// params and env vars must not reuse them, otherwise they silently collide with
+// the credential plumbing added by buildTask / buildAWSTask.
+const (
+	// SetupCredentialsStepName is the name of the step that references the
+	// credential StepAction. It is always the first step of the Task.
+	SetupCredentialsStepName = "setup-credentials"
+
+	// ParamFacetsUserEmail carries the email of the user who triggered the action
+	ParamFacetsUserEmail = "FACETS_USER_EMAIL"
+	// ParamFacetsUserKubeconfig carries the base64-encoded, RBAC-scoped kubeconfig
+	ParamFacetsUserKubeconfig = "FACETS_USER_KUBECONFIG"
+
+	// EnvKubeconfig points kubectl at the kubeconfig written by the credential step
+	EnvKubeconfig = "KUBECONFIG"
+	// EnvAWSConfigFile points the AWS SDK/CLI at the config written by the credential step
+	EnvAWSConfigFile = "AWS_CONFIG_FILE"
+
+	// WorkspaceFacetsUserKubeconfig is the workspace the Facets runner binds to
+	// a short-lived Secret holding the user kubeconfig, when the kubeconfig is
+	// delivered with KubeconfigDeliverySecretWorkspace
+	WorkspaceFacetsUserKubeconfig = "facets-user-kubeconfig"
+	// KubeconfigWorkspaceKey is the key of the kubeconfig in that Secret
+	KubeconfigWorkspaceKey = "kubeconfig"
+
+	// KubeconfigPath is where the Kubernetes credential step writes the kubeconfig
+	KubeconfigPath = "/workspace/.kube/config"
+	// AWSConfigPath is where the AWS credential step writes the AWS config file
+	AWSConfigPath = "/workspace/.aws/config"
+)
+
+// ReservedNames lists the step, param and env var names a resource type injects
+type ReservedNames struct {
+	Steps  []string
+	Params []string
+	Env    []string
+}
+
+// KubernetesReservedNames returns the names injected by facets_tekton_action_kubernetes
+func KubernetesReservedNames() ReservedNames {
+	return ReservedNames{
+		Steps:  []string{SetupCredentialsStepName},
+		Params: []string{ParamFacetsUserEmail, ParamFacetsUserKubeconfig},
+		Env:    []string{EnvKubeconfig},
+	}
+}
+
+// AWSReservedNames returns the names injected by facets_tekton_action_aws
+func AWSReservedNames() ReservedNames {
+	return ReservedNames{
+		Steps: []string{SetupCredentialsStepName},
+		Env:   []string{EnvAWSConfigFile},
+	}
+}
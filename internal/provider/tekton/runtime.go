@@ -0,0 +1,101 @@
+package tekton
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultRunnerServiceAccount is the ServiceAccount the Facets runner starts
+// TaskRuns as, unless the action has its own rbac
+const DefaultRunnerServiceAccount = "facets-workflows-sa"
+
+// Tekton's feature-flags ConfigMap, in the namespace Tekton Pipelines is
+// installed in
+const (
+	FeatureFlagsConfigMap = "feature-flags"
+	// FeatureFlagEnableStepActions enables StepActions, which every action's
+	// setup-credentials step references
+	FeatureFlagEnableStepActions = "enable-step-actions"
+)
+
+// GVRs of the objects managed by the facets_tekton_runtime resource
+var (
+	NamespaceGVR = k8sschema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	ConfigMapGVR = k8sschema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+// BuildRuntimeNamespace creates the namespace for actions
+func BuildRuntimeNamespace(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+		},
+	}
+}
+
+// BuildRunnerServiceAccount creates the ServiceAccount the Facets runner
+// starts TaskRuns as, with cloud identity annotations such as
+// eks.amazonaws.com/role-arn and the image pull Secrets of private step images
+func BuildRunnerServiceAccount(name, namespace string, annotations map[string]string, imagePullSecrets []string) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name":      name,
+		"namespace": namespace,
+	}
+	if len(annotations) > 0 {
+		a := make(map[string]interface{}, len(annotations))
+		for k, v := range annotations {
+			a[k] = v
+		}
+		metadata["annotations"] = a
+	}
+
+	serviceAccount := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ServiceAccount",
+			"metadata":   metadata,
+		},
+	}
+	if len(imagePullSecrets) > 0 {
+		secrets := make([]interface{}, 0, len(imagePullSecrets))
+		for _, name := range imagePullSecrets {
+			secrets = append(secrets, map[string]interface{}{"name": name})
+		}
+		serviceAccount.Object["imagePullSecrets"] = secrets
+	}
+	return serviceAccount
+}
+
+// ImagePullSecrets returns the names of a ServiceAccount's image pull Secrets
+func ImagePullSecrets(serviceAccount *unstructured.Unstructured) []string {
+	secrets, _, _ := unstructured.NestedSlice(serviceAccount.Object, "imagePullSecrets")
+	names := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		if m, ok := s.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// FeatureFlag returns a flag of Tekton's feature-flags ConfigMap
+func FeatureFlag(featureFlags *unstructured.Unstructured, flag string) (string, bool) {
+	value, found, _ := unstructured.NestedString(featureFlags.Object, "data", flag)
+	return value, found
+}
+
+// SetFeatureFlag sets a flag of Tekton's feature-flags ConfigMap, keeping the others
+func SetFeatureFlag(featureFlags *unstructured.Unstructured, flag, value string) {
+	data, _, _ := unstructured.NestedStringMap(featureFlags.Object, "data")
+	if data == nil {
+		data = map[string]string{}
+	}
+	data[flag] = value
+	_ = unstructured.SetNestedStringMap(featureFlags.Object, data, "data")
+}
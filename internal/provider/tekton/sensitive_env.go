@@ -0,0 +1,99 @@
+package tekton
+
+import (
+	"encoding/base64"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// EnvSecretName returns the name of the Secret that holds the sensitive env
+// values of a Task. It is derived from the Task name, which carries the
+// action's hash.
+func EnvSecretName(taskName string) string {
+	return taskName + "-env"
+}
+
+// SensitiveEnvKey returns the Secret key of a step's sensitive env var. Step
+// names are DNS labels and env var names are identifiers, so the key is
+// unique and valid.
+func SensitiveEnvKey(stepName, envName string) string {
+	return stepName + "." + envName
+}
+
+// SensitiveEnvData collects the sensitive_env of every step, keyed by
+// SensitiveEnvKey. Unknown values are skipped.
+func SensitiveEnvData(steps []StepModel) map[string]string {
+	data := map[string]string{}
+	for _, step := range steps {
+		for name, value := range knownStrings(step.SensitiveEnv) {
+			data[SensitiveEnvKey(step.Name.ValueString(), name)] = value
+		}
+	}
+	return data
+}
+
+// AddSensitiveEnv adds an env var reading each of the step's sensitive_env
+// values from the Secret secretName, in name order
+func AddSensitiveEnv(tektonStep map[string]interface{}, step StepModel, secretName string) {
+	values := knownStrings(step.SensitiveEnv)
+	if len(values) == 0 {
+		return
+	}
+
+	var envList []interface{}
+	if existingEnv, ok := tektonStep["env"].([]interface{}); ok {
+		envList = existingEnv
+	}
+	for _, name := range sortedStringKeys(values) {
+		envList = append(envList, map[string]interface{}{
+			"name": name,
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{
+					"name": secretName,
+					"key":  SensitiveEnvKey(step.Name.ValueString(), name),
+				},
+			},
+		})
+	}
+	tektonStep["env"] = envList
+}
+
+// BuildEnvSecret creates the Opaque Secret holding sensitive env values.
+// Values are set through data rather than stringData, so an update replaces
+// the keys instead of merging them.
+func BuildEnvSecret(name, namespace string, labels, annotations map[string]interface{}, values map[string]string) *unstructured.Unstructured {
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":        name,
+				"namespace":   namespace,
+				"labels":      labels,
+				"annotations": annotations,
+			},
+			"type": "Opaque",
+			"data": data,
+		},
+	}
+}
+
+// SecretData returns the decoded data of a Secret. Keys whose values cannot
+// be decoded are left out.
+func SecretData(secret *unstructured.Unstructured) map[string]string {
+	encoded, _, _ := unstructured.NestedStringMap(secret.Object, "data")
+	data := make(map[string]string, len(encoded))
+	for key, value := range encoded {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		data[key] = string(decoded)
+	}
+	return data
+}
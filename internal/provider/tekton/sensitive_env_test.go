@@ -0,0 +1,86 @@
+package tekton
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAddSensitiveEnv(t *testing.T) {
+	step := StepModel{
+		Name: types.StringValue("deploy"),
+		SensitiveEnv: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"DB_PASSWORD": types.StringValue("p"),
+			"API_TOKEN":   types.StringValue("t"),
+		}),
+	}
+	tektonStep := map[string]interface{}{
+		"env": []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"}},
+	}
+
+	AddSensitiveEnv(tektonStep, step, "task-1-env")
+
+	secretRef := func(name, key string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name,
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{"name": "task-1-env", "key": key},
+			},
+		}
+	}
+	want := []interface{}{
+		map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+		secretRef("API_TOKEN", "deploy.API_TOKEN"),
+		secretRef("DB_PASSWORD", "deploy.DB_PASSWORD"),
+	}
+	if !reflect.DeepEqual(tektonStep["env"], want) {
+		t.Errorf("env = %v, want %v", tektonStep["env"], want)
+	}
+}
+
+func TestBuildEnvSecret_RoundTrip(t *testing.T) {
+	values := map[string]string{"deploy.API_TOKEN": "t0ken", "deploy.EMPTY": ""}
+	secret := BuildEnvSecret("task-1-env", "tekton-pipelines", nil, nil, values)
+
+	if secret.GetKind() != "Secret" || secret.Object["type"] != "Opaque" {
+		t.Errorf("unexpected Secret: %v", secret.Object)
+	}
+	if _, found := secret.Object["stringData"]; found {
+		t.Error("values must be set through data so updates replace removed keys")
+	}
+	if got := SecretData(secret); !reflect.DeepEqual(got, values) {
+		t.Errorf("SecretData() = %v, want %v", got, values)
+	}
+}
+
+func TestValidateActionConfig_SensitiveEnv(t *testing.T) {
+	step := types.ObjectValueMust(StepObjectType.AttrTypes, map[string]attr.Value{
+		"name":      types.StringValue("deploy"),
+		"image":     types.StringValue("busybox:1.36"),
+		"script":    types.StringValue("echo hi"),
+		"resources": types.ObjectNull(ComputeResourcesObjectType.AttrTypes),
+		"env": types.ListValueMust(EnvVarObjectType, []attr.Value{
+			types.ObjectValueMust(EnvVarObjectType.AttrTypes, map[string]attr.Value{
+				"name":  types.StringValue("API_TOKEN"),
+				"value": types.StringValue("v"),
+			}),
+		}),
+		"sensitive_env": types.MapValueMust(types.StringType, map[string]attr.Value{
+			"API_TOKEN":  types.StringValue("s"),
+			"KUBECONFIG": types.StringValue("s"),
+		}),
+	})
+
+	diags := ValidateActionConfig(context.Background(), testSteps(step), testParams(), KubernetesReservedNames())
+	sensitiveEnv := path.Root("steps").AtListIndex(0).AtName("sensitive_env")
+	if errorAt(diags, "Duplicate Environment Variable", sensitiveEnv.AtMapKey("API_TOKEN")) == nil {
+		t.Errorf("expected duplicate error for API_TOKEN, got %v", diags)
+	}
+	if errorAt(diags, "Reserved Environment Variable", sensitiveEnv.AtMapKey("KUBECONFIG")) == nil {
+		t.Errorf("expected reserved error for KUBECONFIG, got %v", diags)
+	}
+}
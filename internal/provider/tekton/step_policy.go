@@ -0,0 +1,225 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// dockerHubRegistry is the registry of image references without a registry host
+const dockerHubRegistry = "docker.io"
+
+// ResourceList is a step's compute resources as quantity strings
+type ResourceList struct {
+	Requests map[string]string
+	Limits   map[string]string
+}
+
+// IsEmpty reports whether neither requests nor limits are set
+func (l ResourceList) IsEmpty() bool {
+	return len(l.Requests) == 0 && len(l.Limits) == 0
+}
+
+// StepPolicy holds the provider-wide rules applied to every user-defined step.
+// The zero value applies no defaults and allows everything.
+type StepPolicy struct {
+	// DefaultResources is used for steps that omit resources
+	DefaultResources ResourceList
+	// MaxResources caps each step's requests and limits, per resource name
+	MaxResources ResourceList
+	// AllowedImageRegistries restricts step images to these registries or
+	// repository prefixes. Empty allows every image.
+	AllowedImageRegistries []string
+}
+
+// ValidateStepPolicy checks every step against the policy: images must come
+// from an allowed registry, and requests and limits, including defaults applied
+// to steps without resources, must not exceed the maximums. Unknown values are
+// skipped.
+func ValidateStepPolicy(ctx context.Context, steps types.List, policy StepPolicy) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, elem := range knownObjects(steps) {
+		var step StepModel
+		if d := elem.obj.As(ctx, &step, basetypes.ObjectAsOptions{}); d.HasError() {
+			diags.Append(d...)
+			return diags
+		}
+		stepPath := path.Root("steps").AtListIndex(elem.index)
+
+		if len(policy.AllowedImageRegistries) > 0 && !step.Image.IsNull() && !step.Image.IsUnknown() {
+			image := step.Image.ValueString()
+			if !ImageAllowed(image, policy.AllowedImageRegistries) {
+				diags.AddAttributeError(stepPath.AtName("image"), "Image Registry Not Allowed",
+					fmt.Sprintf("Image %q is not from a registry allowed by the provider's allowed_image_registries (%s). "+
+						"Images referencing Tekton params cannot be checked and are rejected as well.",
+						image, strings.Join(policy.AllowedImageRegistries, ", ")))
+			}
+		}
+
+		if policy.MaxResources.IsEmpty() || step.Resources.IsUnknown() {
+			continue
+		}
+		if step.Resources.IsNull() {
+			resourcesPath := stepPath.AtName("resources")
+			for _, msg := range ExceededMaximums(policy.DefaultResources, policy.MaxResources) {
+				diags.AddAttributeError(resourcesPath, "Step Resources Exceed Maximum",
+					fmt.Sprintf("The provider's default_step_resources apply to this step, but %s.", msg))
+			}
+			continue
+		}
+
+		var computeRes ComputeResourcesModel
+		if d := step.Resources.As(ctx, &computeRes, basetypes.ObjectAsOptions{}); d.HasError() {
+			diags.Append(d...)
+			return diags
+		}
+		resourcesPath := stepPath.AtName("resources")
+		checkMaximums(knownStrings(computeRes.Requests), policy.MaxResources.Requests, "requests", resourcesPath.AtName("requests"), &diags)
+		checkMaximums(knownStrings(computeRes.Limits), policy.MaxResources.Limits, "limits", resourcesPath.AtName("limits"), &diags)
+	}
+
+	return diags
+}
+
+// checkMaximums adds an error for every entry of values that exceeds its
+// maximum. Entries that do not parse are left to ValidateComputeResources.
+func checkMaximums(values, maximums map[string]string, kind string, mapPath path.Path, diags *diag.Diagnostics) {
+	for _, name := range sortedStringKeys(values) {
+		if msg := exceedsMaximum(name, values[name], maximums, kind); msg != "" {
+			diags.AddAttributeError(mapPath.AtMapKey(name), "Step Resources Exceed Maximum", msg+".")
+		}
+	}
+}
+
+// ExceededMaximums returns a message for every request or limit in l that
+// exceeds its maximum
+func ExceededMaximums(l, maximums ResourceList) []string {
+	var msgs []string
+	for _, name := range sortedStringKeys(l.Requests) {
+		if msg := exceedsMaximum(name, l.Requests[name], maximums.Requests, "requests"); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	for _, name := range sortedStringKeys(l.Limits) {
+		if msg := exceedsMaximum(name, l.Limits[name], maximums.Limits, "limits"); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// exceedsMaximum returns a message if value exceeds maximums[name], or "" if
+// it does not, has no maximum, or either quantity does not parse
+func exceedsMaximum(name, value string, maximums map[string]string, kind string) string {
+	maxValue, ok := maximums[name]
+	if !ok {
+		return ""
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return ""
+	}
+	maximum, err := resource.ParseQuantity(maxValue)
+	if err != nil {
+		return ""
+	}
+	if quantity.Cmp(maximum) <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %q (%s) exceeds the provider's max_step_resources.%s %q (%s)",
+		kind, name, quantity.String(), kind, name, maximum.String())
+}
+
+// ImageAllowed reports whether image comes from one of the allowed registries.
+// An entry without a path (e.g. "ghcr.io") matches the registry host; an entry
+// with a path (e.g. "ghcr.io/facets-cloud") matches that repository and
+// everything below it. Images without a registry host are resolved to
+// docker.io, as the container runtime does. Images containing Tekton
+// substitutions ($(params.x)) are never allowed, because the final reference is
+// only known when the TaskRun starts.
+func ImageAllowed(image string, allowed []string) bool {
+	if strings.Contains(image, "$(") {
+		return false
+	}
+	registry, repository := splitImage(image)
+	for _, entry := range allowed {
+		entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+		entryRegistry, entryPath, hasPath := strings.Cut(entry, "/")
+		if !strings.EqualFold(entryRegistry, registry) {
+			continue
+		}
+		if !hasPath || repository == entryPath || strings.HasPrefix(repository, entryPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitImage returns the registry host and repository path of an image
+// reference, without tag or digest. The first path component is a registry
+// host if it contains "." or ":" or is "localhost", matching the container
+// runtime's rules; otherwise the image is on Docker Hub.
+func splitImage(image string) (registry, repository string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return strings.ToLower(first), rest
+	}
+	if !found {
+		return dockerHubRegistry, "library/" + image
+	}
+	return dockerHubRegistry, image
+}
+
+// resourcesFor returns the resources to render for a step: the step's
+// own resources, or the policy defaults when the step omits them
+func (p StepPolicy) resourcesFor(ctx context.Context, step StepModel) ResourceList {
+	if step.Resources.IsNull() || step.Resources.IsUnknown() {
+		return p.DefaultResources
+	}
+	var computeRes ComputeResourcesModel
+	if diags := step.Resources.As(ctx, &computeRes, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return ResourceList{}
+	}
+	return ResourceList{
+		Requests: knownStrings(computeRes.Requests),
+		Limits:   knownStrings(computeRes.Limits),
+	}
+}
+
+// knownStrings returns the known, non-null entries of a map of strings
+func knownStrings(m types.Map) map[string]string {
+	result := make(map[string]string)
+	if m.IsNull() || m.IsUnknown() {
+		return result
+	}
+	for k, elem := range m.Elements() {
+		if v, ok := elem.(types.String); ok && !v.IsNull() && !v.IsUnknown() {
+			result[k] = v.ValueString()
+		}
+	}
+	return result
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
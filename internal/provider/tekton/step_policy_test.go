@@ -0,0 +1,146 @@
+package tekton
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// testStepWithImage is like testStep but with the given image.
+func testStepWithImage(name, image string) attr.Value {
+	return types.ObjectValueMust(StepObjectType.AttrTypes, map[string]attr.Value{
+		"name":          types.StringValue(name),
+		"image":         types.StringValue(image),
+		"script":        types.StringValue("echo hi"),
+		"resources":     types.ObjectNull(ComputeResourcesObjectType.AttrTypes),
+		"env":           types.ListNull(EnvVarObjectType),
+		"sensitive_env": types.MapNull(types.StringType),
+	})
+}
+
+func TestImageAllowed(t *testing.T) {
+	allowed := []string{"ghcr.io/facets-cloud", "123456789012.dkr.ecr.us-east-1.amazonaws.com", "docker.io/library", "localhost:5000/"}
+
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{"ghcr.io/facets-cloud/kubectl:1.30", true},
+		{"ghcr.io/facets-cloud/tools/aws@sha256:0123", true},
+		{"ghcr.io/facets-cloud", true},
+		{"ghcr.io/facets-cloud-evil/kubectl:1.30", false},
+		{"ghcr.io/other/kubectl", false},
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com/team/app:v1", true},
+		{"123456789012.DKR.ECR.us-east-1.amazonaws.com/team/app:v1", true},
+		{"busybox:1.36", true},
+		{"docker.io/library/alpine", true},
+		{"bitnami/kubectl:latest", false},
+		{"localhost:5000/app:dev", true},
+		{"$(params.image)", false},
+		{"ghcr.io/facets-cloud/$(params.tool)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := ImageAllowed(tt.image, allowed); got != tt.want {
+				t.Errorf("ImageAllowed(%q) = %v, want %v", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStepPolicy_Images(t *testing.T) {
+	steps := testSteps(
+		testStepWithImage("build", "ghcr.io/facets-cloud/builder:1"),
+		testStepWithImage("deploy", "quay.io/someone/deployer:1"),
+	)
+	policy := StepPolicy{AllowedImageRegistries: []string{"ghcr.io"}}
+
+	diags := ValidateStepPolicy(context.Background(), steps, policy)
+	if len(diags.Errors()) != 1 {
+		t.Fatalf("expected exactly one error, got %v", diags)
+	}
+	if errorAt(diags, "Image Registry Not Allowed", path.Root("steps").AtListIndex(1).AtName("image")) == nil {
+		t.Errorf("expected image error at steps[1].image, got %v", diags)
+	}
+
+	if diags := ValidateStepPolicy(context.Background(), steps, StepPolicy{}); diags.HasError() {
+		t.Errorf("empty policy must allow every image, got %v", diags)
+	}
+}
+
+func TestValidateStepPolicy_MaxResources(t *testing.T) {
+	steps := testSteps(
+		testStepWithResources("small", testResources(
+			map[string]string{"cpu": "500m", "memory": "256Mi"},
+			map[string]string{"cpu": "1", "memory": "1Gi"},
+		)),
+		testStepWithResources("large", testResources(
+			map[string]string{"cpu": "3"},
+			map[string]string{"cpu": "4", "memory": "16Gi"},
+		)),
+		testStep("defaults"),
+	)
+	policy := StepPolicy{
+		DefaultResources: ResourceList{Limits: map[string]string{"memory": "8Gi"}},
+		MaxResources: ResourceList{
+			Requests: map[string]string{"cpu": "2"},
+			Limits:   map[string]string{"cpu": "2", "memory": "4Gi"},
+		},
+	}
+
+	diags := ValidateStepPolicy(context.Background(), steps, policy)
+
+	resourcesPath := func(step int) path.Path {
+		return path.Root("steps").AtListIndex(step).AtName("resources")
+	}
+	for _, p := range []path.Path{
+		resourcesPath(1).AtName("requests").AtMapKey("cpu"),
+		resourcesPath(1).AtName("limits").AtMapKey("cpu"),
+		resourcesPath(1).AtName("limits").AtMapKey("memory"),
+		resourcesPath(2),
+	} {
+		if errorAt(diags, "Step Resources Exceed Maximum", p) == nil {
+			t.Errorf("expected error at %s, got %v", p, diags)
+		}
+	}
+	if len(diags.Errors()) != 4 {
+		t.Errorf("expected 4 errors, got %d: %v", len(diags.Errors()), diags)
+	}
+}
+
+func TestBuildStepWithResources_DefaultResources(t *testing.T) {
+	policy := StepPolicy{
+		DefaultResources: ResourceList{
+			Requests: map[string]string{"cpu": "100m", "memory": "128Mi"},
+			Limits:   map[string]string{"memory": "512Mi"},
+		},
+	}
+
+	var step StepModel
+	if diags := testStep("defaults").(types.Object).As(context.Background(), &step, basetypes.ObjectAsOptions{}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	got := BuildStepWithResources(context.Background(), step, policy)["computeResources"]
+	want := map[string]interface{}{
+		"requests": policy.DefaultResources.Requests,
+		"limits":   policy.DefaultResources.Limits,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeResources = %v, want defaults %v", got, want)
+	}
+
+	if diags := testStepWithResources("own", testResources(map[string]string{"cpu": "1"}, nil)).(types.Object).As(context.Background(), &step, basetypes.ObjectAsOptions{}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	got = BuildStepWithResources(context.Background(), step, policy)["computeResources"]
+	want = map[string]interface{}{"requests": map[string]string{"cpu": "1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeResources = %v, want the step's own resources %v", got, want)
+	}
+}
@@ -1,10 +1,7 @@
 package tekton
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
-	"os"
 
 	"github.com/facets-cloud/terraform-provider-facets/internal/aws"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -12,21 +9,23 @@ import (
 
 // BuildAWSStepAction creates a StepAction for AWS credential setup using IRSA
 // This StepAction configures AWS credentials using IRSA (pod's IAM role) to assume a target role
-func BuildAWSStepAction(stepActionName, namespace string, labels map[string]interface{}, awsConfig *aws.AWSAuthConfig) (*unstructured.Unstructured, error) {
+// The image must provide AWSCredentialStepTools
+func BuildAWSStepAction(stepActionName, taskName, namespace string, labels, annotations map[string]interface{}, awsConfig *aws.AWSAuthConfig, credentialStep CredentialStep) (*unstructured.Unstructured, error) {
 	// Generate script using IRSA + source_profile for role assumption
-	script := GenerateAssumeRoleScript(awsConfig)
+	script := withToolCheck(GenerateAssumeRoleScript(awsConfig, taskName), AWSCredentialStepTools)
 
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "tekton.dev/v1beta1",
 			"kind":       "StepAction",
 			"metadata": map[string]interface{}{
-				"name":      stepActionName,
-				"namespace": namespace,
-				"labels":    labels,
+				"name":        stepActionName,
+				"namespace":   namespace,
+				"labels":      labels,
+				"annotations": annotations,
 			},
 			"spec": map[string]interface{}{
-				"image":  "facetscloud/actions-base-image:v1.0.0",
+				"image":  credentialStep.ImageOrDefault(),
 				"script": script,
 				// No params needed - AWS SDK uses IRSA from pod automatically
 				// No env vars needed - IRSA injected by EKS webhook
@@ -38,17 +37,18 @@ func BuildAWSStepAction(stepActionName, namespace string, labels map[string]inte
 // GenerateAssumeRoleScript creates an AWS config file with source_profile
 // Uses IRSA (pod's IAM role) via source_profile to automatically assume the target role
 // The AWS SDK handles the role assumption automatically - no manual STS calls needed
-func GenerateAssumeRoleScript(config *aws.AWSAuthConfig) string {
+// Without a configured session name, DefaultSessionName(taskName) is used
+func GenerateAssumeRoleScript(config *aws.AWSAuthConfig, taskName string) string {
 	if config.AssumeRoleConfig == nil {
 		return ""
 	}
 
 	assumeRole := config.AssumeRoleConfig
 
-	// Generate session name if not provided
+	// Derive session name from the task if not provided
 	sessionName := assumeRole.SessionName
 	if sessionName == "" {
-		sessionName = generateRandomSessionName()
+		sessionName = DefaultSessionName(taskName)
 	}
 
 	// Build the config file with source_profile for role chaining
@@ -94,13 +94,17 @@ chmod 600 /workspace/.aws/config
 	)
 }
 
-// generateRandomSessionName creates a random session name using crypto/rand
-// Returns a string in format "terraform-XXXXXXXX" where X is a random hex character
-func generateRandomSessionName() string {
-	bytes := make([]byte, 8)
-	if _, err := rand.Read(bytes); err != nil {
-		// Fallback to a simpler approach if crypto/rand fails
-		return fmt.Sprintf("terraform-session-%d", os.Getpid())
+// maxSessionNameLength is the longest role session name STS accepts
+const maxSessionNameLength = 64
+
+// DefaultSessionName returns the role session name of an action without a
+// configured session_name: "terraform-<taskName>". It is deterministic, so the
+// StepAction renders the same in plan and apply, and CloudTrail entries can be
+// traced back to the action.
+func DefaultSessionName(taskName string) string {
+	sessionName := "terraform-" + taskName
+	if len(sessionName) > maxSessionNameLength {
+		sessionName = sessionName[:maxSessionNameLength]
 	}
-	return fmt.Sprintf("terraform-%s", hex.EncodeToString(bytes))
+	return sessionName
 }
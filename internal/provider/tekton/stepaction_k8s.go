@@ -4,39 +4,106 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// How the Facets runner hands the user kubeconfig to the credential step
+const (
+	// KubeconfigDeliveryParam passes the base64-encoded kubeconfig in the
+	// FACETS_USER_KUBECONFIG param, so it is visible in the TaskRun spec
+	KubeconfigDeliveryParam = "param"
+	// KubeconfigDeliverySecretWorkspace mounts a short-lived Secret holding
+	// the kubeconfig at the WorkspaceFacetsUserKubeconfig workspace
+	KubeconfigDeliverySecretWorkspace = "secret_workspace"
+)
+
+// ParamFacetsUserKubeconfigPath is the StepAction param that carries the path of
+// the kubeconfig in the workspace, with KubeconfigDeliverySecretWorkspace
+const ParamFacetsUserKubeconfigPath = "FACETS_USER_KUBECONFIG_PATH"
+
+// KubernetesSecretWorkspaceCredentialStepTools are the tools an override
+// credential step image must provide with KubeconfigDeliverySecretWorkspace
+var KubernetesSecretWorkspaceCredentialStepTools = []string{"bash", "mkdir", "cp", "chmod"}
+
 // BuildKubernetesStepAction creates a StepAction for Kubernetes credential setup
 // This StepAction decodes the base64-encoded FACETS_USER_KUBECONFIG and writes it to /workspace/.kube/config
-func BuildKubernetesStepAction(stepActionName, namespace string, labels map[string]interface{}) *unstructured.Unstructured {
+// With KubeconfigDeliverySecretWorkspace it copies the kubeconfig from the workspace Secret instead
+// The image must provide KubernetesCredentialStepTools, or KubernetesSecretWorkspaceCredentialStepTools
+func BuildKubernetesStepAction(stepActionName, namespace string, labels, annotations map[string]interface{}, credentialStep CredentialStep, kubeconfigDelivery string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"image": credentialStep.ImageOrDefault(),
+		"script": withToolCheck(`#!/bin/bash
+set -e
+mkdir -p /workspace/.kube
+echo -n "$FACETS_USER_KUBECONFIG" | base64 -d > /workspace/.kube/config
+export KUBECONFIG=/workspace/.kube/config
+`, KubernetesCredentialStepTools),
+		"params": []interface{}{
+			map[string]interface{}{
+				"name": "FACETS_USER_KUBECONFIG",
+				"type": "string",
+			},
+		},
+		"env": []interface{}{
+			map[string]interface{}{
+				"name":  "FACETS_USER_KUBECONFIG",
+				"value": "$(params.FACETS_USER_KUBECONFIG)",
+			},
+		},
+	}
+
+	if kubeconfigDelivery == KubeconfigDeliverySecretWorkspace {
+		spec["script"] = withToolCheck(`#!/bin/bash
+set -e
+mkdir -p /workspace/.kube
+cp "$FACETS_USER_KUBECONFIG_PATH" /workspace/.kube/config
+chmod 600 /workspace/.kube/config
+`, KubernetesSecretWorkspaceCredentialStepTools)
+		spec["params"] = []interface{}{
+			map[string]interface{}{
+				"name": ParamFacetsUserKubeconfigPath,
+				"type": "string",
+			},
+		}
+		spec["env"] = []interface{}{
+			map[string]interface{}{
+				"name":  ParamFacetsUserKubeconfigPath,
+				"value": "$(params." + ParamFacetsUserKubeconfigPath + ")",
+			},
+		}
+	}
+
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "tekton.dev/v1beta1",
 			"kind":       "StepAction",
 			"metadata": map[string]interface{}{
-				"name":      stepActionName,
-				"namespace": namespace,
-				"labels":    labels,
-			},
-			"spec": map[string]interface{}{
-				"image": "facetscloud/actions-base-image:v1.0.0",
-				"script": `#!/bin/bash
-set -e
-mkdir -p /workspace/.kube
-echo -n "$FACETS_USER_KUBECONFIG" | base64 -d > /workspace/.kube/config
-export KUBECONFIG=/workspace/.kube/config
-`,
-				"params": []interface{}{
-					map[string]interface{}{
-						"name": "FACETS_USER_KUBECONFIG",
-						"type": "string",
-					},
-				},
-				"env": []interface{}{
-					map[string]interface{}{
-						"name":  "FACETS_USER_KUBECONFIG",
-						"value": "$(params.FACETS_USER_KUBECONFIG)",
-					},
-				},
+				"name":        stepActionName,
+				"namespace":   namespace,
+				"labels":      labels,
+				"annotations": annotations,
 			},
+			"spec": spec,
+		},
+	}
+}
+
+// KubeconfigWorkspaces returns the workspaces a Kubernetes action's Task
+// declares for kubeconfigDelivery: none for the param, or the read-only
+// WorkspaceFacetsUserKubeconfig for the secret workspace
+func KubeconfigWorkspaces(kubeconfigDelivery string) []interface{} {
+	if kubeconfigDelivery != KubeconfigDeliverySecretWorkspace {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"name": WorkspaceFacetsUserKubeconfig,
+			"description": "Short-lived Secret holding the RBAC-scoped kubeconfig of the user who triggered the action, " +
+				"under the key " + KubeconfigWorkspaceKey + ". Bound by the Facets runner.",
+			"readOnly": true,
 		},
 	}
 }
+
+// KubeconfigWorkspacePath is the path of the kubeconfig in the workspace,
+// as a Tekton substitution
+func KubeconfigWorkspacePath() string {
+	return "$(workspaces." + WorkspaceFacetsUserKubeconfig + ".path)/" + KubeconfigWorkspaceKey
+}
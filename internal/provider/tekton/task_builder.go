@@ -3,7 +3,6 @@ package tekton
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -13,10 +12,15 @@ type TaskSpec struct {
 	Namespace   string
 	Description string
 	Labels      map[string]interface{}
+	Annotations map[string]interface{}
+	// Workspaces are declared on the Task when not empty
+	Workspaces []interface{}
 }
 
-// BuildStepWithResources builds a Tekton step with environment variables and compute resources
-func BuildStepWithResources(ctx context.Context, step StepModel) map[string]interface{} {
+// BuildStepWithResources builds a Tekton step with environment variables and
+// compute resources. Steps without resources get policy.DefaultResources; the
+// rest of the policy is enforced at plan time by ValidateStepPolicy.
+func BuildStepWithResources(ctx context.Context, step StepModel, policy StepPolicy) map[string]interface{} {
 	tektonStep := map[string]interface{}{
 		"name":   step.Name.ValueString(),
 		"image":  step.Image.ValueString(),
@@ -38,33 +42,17 @@ func BuildStepWithResources(ctx context.Context, step StepModel) map[string]inte
 		tektonStep["env"] = envList
 	}
 
-	// Add compute resources if present
-	if !step.Resources.IsNull() {
-		var computeRes ComputeResourcesModel
-		diags := step.Resources.As(ctx, &computeRes, basetypes.ObjectAsOptions{})
-		if !diags.HasError() {
-			computeResources := make(map[string]interface{})
-
-			if !computeRes.Requests.IsNull() {
-				requestsMap := make(map[string]string)
-				computeRes.Requests.ElementsAs(ctx, &requestsMap, false)
-				if len(requestsMap) > 0 {
-					computeResources["requests"] = requestsMap
-				}
-			}
-
-			if !computeRes.Limits.IsNull() {
-				limitsMap := make(map[string]string)
-				computeRes.Limits.ElementsAs(ctx, &limitsMap, false)
-				if len(limitsMap) > 0 {
-					computeResources["limits"] = limitsMap
-				}
-			}
-
-			if len(computeResources) > 0 {
-				tektonStep["computeResources"] = computeResources
-			}
-		}
+	// Add compute resources, falling back to the policy defaults when the step omits them
+	res := policy.resourcesFor(ctx, step)
+	computeResources := make(map[string]interface{})
+	if len(res.Requests) > 0 {
+		computeResources["requests"] = res.Requests
+	}
+	if len(res.Limits) > 0 {
+		computeResources["limits"] = res.Limits
+	}
+	if len(computeResources) > 0 {
+		tektonStep["computeResources"] = computeResources
 	}
 
 	return tektonStep
@@ -92,20 +80,26 @@ func BuildTask(spec TaskSpec, steps []interface{}, params []interface{}) *unstru
 		description = spec.Description
 	}
 
+	taskSpec := map[string]interface{}{
+		"description": description,
+		"steps":       steps,
+		"params":      params,
+	}
+	if len(spec.Workspaces) > 0 {
+		taskSpec["workspaces"] = spec.Workspaces
+	}
+
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "tekton.dev/v1beta1",
 			"kind":       "Task",
 			"metadata": map[string]interface{}{
-				"name":      spec.TaskName,
-				"namespace": spec.Namespace,
-				"labels":    spec.Labels,
-			},
-			"spec": map[string]interface{}{
-				"description": description,
-				"steps":       steps,
-				"params":      params,
+				"name":        spec.TaskName,
+				"namespace":   spec.Namespace,
+				"labels":      spec.Labels,
+				"annotations": spec.Annotations,
 			},
+			"spec": taskSpec,
 		},
 	}
 }
@@ -0,0 +1,149 @@
+package tekton
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ParsedTask is the user-defined part of a Task created by this provider:
+// what remains after removing the credential step, params and env vars the
+// provider injects
+type ParsedTask struct {
+	// Description is empty when the Task carries the default, its name
+	Description string
+	Steps       []ParsedStep
+	Params      []ParsedParam
+}
+
+// ParsedStep is a user-defined step of a Task
+type ParsedStep struct {
+	Name   string
+	Image  string
+	Script string
+	// Env holds the plain env vars, in order
+	Env []ParsedEnvVar
+	// SensitiveEnv maps the names of the env vars read from the env Secret to
+	// their keys in it
+	SensitiveEnv map[string]string
+	// Requests and Limits are the step's compute resources, including those
+	// the provider's default_step_resources filled in
+	Requests map[string]string
+	Limits   map[string]string
+}
+
+// ParsedEnvVar is a plain env var of a step
+type ParsedEnvVar struct {
+	Name  string
+	Value string
+}
+
+// ParsedParam is a user-defined param of a Task
+type ParsedParam struct {
+	Name string
+	Type string
+}
+
+// ParseTask reads the user-defined description, steps and params of a Task,
+// leaving out the names in reserved. Steps and params that are not maps are
+// skipped.
+func ParseTask(task *unstructured.Unstructured, reserved ReservedNames) ParsedTask {
+	var parsed ParsedTask
+
+	if description, _, _ := unstructured.NestedString(task.Object, "spec", "description"); description != task.GetName() {
+		parsed.Description = description
+	}
+
+	steps := nestedSliceNoCopy(task.Object, "spec", "steps")
+	for _, s := range steps {
+		step, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := step["name"].(string)
+		if containsName(reserved.Steps, name) {
+			continue
+		}
+		parsed.Steps = append(parsed.Steps, parseStep(step, reserved))
+	}
+
+	params := nestedSliceNoCopy(task.Object, "spec", "params")
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := param["name"].(string)
+		if containsName(reserved.Params, name) {
+			continue
+		}
+		paramType, _ := param["type"].(string)
+		parsed.Params = append(parsed.Params, ParsedParam{Name: name, Type: paramType})
+	}
+
+	return parsed
+}
+
+// parseStep reads a user-defined step
+func parseStep(step map[string]interface{}, reserved ReservedNames) ParsedStep {
+	var parsed ParsedStep
+	parsed.Name, _ = step["name"].(string)
+	parsed.Image, _ = step["image"].(string)
+	parsed.Script, _ = step["script"].(string)
+
+	env := nestedSliceNoCopy(step, "env")
+	for _, e := range env {
+		envVar, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := envVar["name"].(string)
+		if containsName(reserved.Env, name) {
+			continue
+		}
+		if key, found, _ := unstructured.NestedString(envVar, "valueFrom", "secretKeyRef", "key"); found {
+			if parsed.SensitiveEnv == nil {
+				parsed.SensitiveEnv = map[string]string{}
+			}
+			parsed.SensitiveEnv[name] = key
+			continue
+		}
+		value, _ := envVar["value"].(string)
+		parsed.Env = append(parsed.Env, ParsedEnvVar{Name: name, Value: value})
+	}
+
+	parsed.Requests = resourceQuantities(step, "requests")
+	parsed.Limits = resourceQuantities(step, "limits")
+	return parsed
+}
+
+// resourceQuantities reads computeResources.<kind> of a step, e.g. requests.
+// Rendered steps hold map[string]string; steps read from the apiserver hold
+// map[string]interface{}.
+func resourceQuantities(step map[string]interface{}, kind string) map[string]string {
+	computeResources, _ := step["computeResources"].(map[string]interface{})
+	switch quantities := computeResources[kind].(type) {
+	case map[string]string:
+		if len(quantities) > 0 {
+			return quantities
+		}
+	case map[string]interface{}:
+		result := make(map[string]string, len(quantities))
+		for name, q := range quantities {
+			if value, ok := q.(string); ok {
+				result[name] = value
+			}
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+	return nil
+}
+
+// nestedSliceNoCopy returns a slice field without the deep copy of
+// unstructured.NestedSlice, which panics on the map[string]string values of
+// rendered objects
+func nestedSliceNoCopy(obj map[string]interface{}, fields ...string) []interface{} {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	slice, _ := value.([]interface{})
+	return slice
+}
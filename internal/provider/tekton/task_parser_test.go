@@ -0,0 +1,84 @@
+package tekton
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// renderedTask renders a Kubernetes action's Task the way the resource does:
+// the setup-credentials step, then the user step with its sensitive env and
+// KUBECONFIG appended
+func renderedTask(description string) *unstructured.Unstructured {
+	ctx := context.Background()
+	step := StepModel{
+		Name:   types.StringValue("deploy"),
+		Image:  types.StringValue("bitnami/kubectl:1.30"),
+		Script: types.StringValue("kubectl rollout restart deployment/api"),
+		Env: types.ListValueMust(EnvVarObjectType, []attr.Value{types.ObjectValueMust(EnvVarObjectType.AttrTypes, map[string]attr.Value{
+			"name":  types.StringValue("LOG_LEVEL"),
+			"value": types.StringValue("debug"),
+		})}),
+		SensitiveEnv: types.MapValueMust(types.StringType, map[string]attr.Value{"API_TOKEN": types.StringValue("s3cret")}),
+		Resources:    types.ObjectNull(ComputeResourcesObjectType.AttrTypes),
+	}
+	policy := StepPolicy{DefaultResources: ResourceList{Requests: map[string]string{"cpu": "100m"}}}
+
+	tektonStep := BuildStepWithResources(ctx, step, policy)
+	AddSensitiveEnv(tektonStep, step, EnvSecretName("abc"))
+	AddEnvVar(tektonStep, EnvKubeconfig, KubeconfigPath)
+	steps := []interface{}{
+		map[string]interface{}{"name": SetupCredentialsStepName, "ref": map[string]interface{}{"name": "setup-credentials-abc"}},
+		tektonStep,
+	}
+	params := []interface{}{
+		map[string]interface{}{"name": ParamFacetsUserEmail, "type": "string"},
+		map[string]interface{}{"name": ParamFacetsUserKubeconfig, "type": "string"},
+		map[string]interface{}{"name": "REPLICAS", "type": "string"},
+	}
+	return BuildTask(TaskSpec{TaskName: "abc", Namespace: "tekton-pipelines", Description: description}, steps, params)
+}
+
+func TestParseTask(t *testing.T) {
+	want := ParsedTask{
+		Description: "Restart the API",
+		Steps: []ParsedStep{{
+			Name:         "deploy",
+			Image:        "bitnami/kubectl:1.30",
+			Script:       "kubectl rollout restart deployment/api",
+			Env:          []ParsedEnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+			SensitiveEnv: map[string]string{"API_TOKEN": SensitiveEnvKey("deploy", "API_TOKEN")},
+			Requests:     map[string]string{"cpu": "100m"},
+		}},
+		Params: []ParsedParam{{Name: "REPLICAS", Type: "string"}},
+	}
+
+	task := renderedTask("Restart the API")
+	if got := ParseTask(task, KubernetesReservedNames()); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTask() rendered = %+v, want %+v", got, want)
+	}
+
+	// Tasks read from the apiserver hold JSON values only
+	data, err := json.Marshal(task.Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromServer := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &fromServer.Object); err != nil {
+		t.Fatal(err)
+	}
+	if got := ParseTask(fromServer, KubernetesReservedNames()); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTask() from server = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTask_DefaultDescription(t *testing.T) {
+	if got := ParseTask(renderedTask(""), KubernetesReservedNames()); got.Description != "" {
+		t.Errorf("Description = %q, want empty for the default description", got.Description)
+	}
+}
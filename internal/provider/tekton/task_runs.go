@@ -0,0 +1,103 @@
+package tekton
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// LabelTask is the label Tekton sets on a TaskRun to the name of the Task it
+// references
+const LabelTask = "tekton.dev/task"
+
+// TaskRunGVR is the GVR of the TaskRuns the Facets runner creates for an action
+var TaskRunGVR = k8sschema.GroupVersionResource{Group: Group, Version: "v1beta1", Resource: "taskruns"}
+
+// TaskRun is the status of a run of an action
+type TaskRun struct {
+	Name string
+	// StartTime and CompletionTime are RFC 3339 timestamps, empty until the
+	// run starts or completes
+	StartTime      string
+	CompletionTime string
+	// Succeeded is the status of the Succeeded condition: "True", "False", or
+	// "Unknown" while the run is in progress. Empty before Tekton reports it.
+	Succeeded string
+	Reason    string
+	Message   string
+	// Results maps the names of the results the steps wrote to their values
+	Results map[string]string
+	// UserEmail is the FACETS_USER_EMAIL param, the user who ran the action
+	UserEmail string
+}
+
+// ListTaskRuns lists the TaskRuns of a Task, newest first. limit caps the
+// number returned; 0 returns all.
+func ListTaskRuns(ctx context.Context, client dynamic.Interface, namespace, taskName string, limit int) ([]TaskRun, error) {
+	items, err := ListAll(ctx, client, TaskRunGVR, namespace, LabelTask+"="+taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		ti, tj := items[i].GetCreationTimestamp(), items[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		return items[i].GetName() > items[j].GetName()
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	runs := make([]TaskRun, 0, len(items))
+	for i := range items {
+		runs = append(runs, ParseTaskRun(&items[i]))
+	}
+	return runs, nil
+}
+
+// ParseTaskRun reads the status of a TaskRun. Results are read from
+// status.results (tekton.dev/v1) or status.taskResults (v1beta1).
+func ParseTaskRun(taskRun *unstructured.Unstructured) TaskRun {
+	run := TaskRun{Name: taskRun.GetName()}
+	run.StartTime, _, _ = unstructured.NestedString(taskRun.Object, "status", "startTime")
+	run.CompletionTime, _, _ = unstructured.NestedString(taskRun.Object, "status", "completionTime")
+
+	for _, c := range nestedSliceNoCopy(taskRun.Object, "status", "conditions") {
+		condition, _ := c.(map[string]interface{})
+		if condition["type"] != "Succeeded" {
+			continue
+		}
+		run.Succeeded, _ = condition["status"].(string)
+		run.Reason, _ = condition["reason"].(string)
+		run.Message, _ = condition["message"].(string)
+	}
+
+	for _, field := range []string{"results", "taskResults"} {
+		for _, r := range nestedSliceNoCopy(taskRun.Object, "status", field) {
+			result, _ := r.(map[string]interface{})
+			name, _ := result["name"].(string)
+			if name == "" {
+				continue
+			}
+			if run.Results == nil {
+				run.Results = map[string]string{}
+			}
+			// Array and object results, which actions do not declare, are left empty
+			value, _ := result["value"].(string)
+			run.Results[name] = value
+		}
+	}
+
+	for _, p := range nestedSliceNoCopy(taskRun.Object, "spec", "params") {
+		param, _ := p.(map[string]interface{})
+		if param["name"] == ParamFacetsUserEmail {
+			run.UserEmail, _ = param["value"].(string)
+		}
+	}
+	return run
+}
@@ -0,0 +1,80 @@
+package tekton
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/facets-cloud/terraform-provider-facets/internal/provider/tekton/testfake"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseTaskRun(t *testing.T) {
+	taskRun := testfake.TaskRun("ns", "migrate-x7k2p", "migrate", "2026-05-01T10:00:00Z", map[string]any{
+		"startTime":      "2026-05-01T10:00:01Z",
+		"completionTime": "2026-05-01T10:02:30Z",
+		"conditions": []any{
+			map[string]any{"type": "Ready", "status": "True"},
+			map[string]any{"type": "Succeeded", "status": "False", "reason": "Failed", "message": "step migrate exited with 1"},
+		},
+		"taskResults": []any{
+			map[string]any{"name": "applied", "value": "3"},
+			map[string]any{"name": "tables", "value": []any{"a", "b"}},
+		},
+	})
+	_ = unstructured.SetNestedSlice(taskRun.Object, []any{
+		map[string]any{"name": ParamFacetsUserEmail, "value": "dev@example.com"},
+		map[string]any{"name": ParamFacetsUserKubeconfig, "value": "secret"},
+	}, "spec", "params")
+
+	want := TaskRun{
+		Name:           "migrate-x7k2p",
+		StartTime:      "2026-05-01T10:00:01Z",
+		CompletionTime: "2026-05-01T10:02:30Z",
+		Succeeded:      "False",
+		Reason:         "Failed",
+		Message:        "step migrate exited with 1",
+		Results:        map[string]string{"applied": "3", "tables": ""},
+		UserEmail:      "dev@example.com",
+	}
+	if got := ParseTaskRun(taskRun); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTaskRun() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTaskRun_Pending(t *testing.T) {
+	got := ParseTaskRun(testfake.TaskRun("ns", "migrate-pending", "migrate", "2026-05-01T10:00:00Z", nil))
+	if !reflect.DeepEqual(got, TaskRun{Name: "migrate-pending"}) {
+		t.Errorf("ParseTaskRun() = %+v, want only the name", got)
+	}
+}
+
+func TestListTaskRuns_NewestFirstWithLimit(t *testing.T) {
+	client := testfake.NewClient(
+		testfake.TaskRun("ns", "migrate-1", "migrate", "2026-05-01T10:00:00Z", nil),
+		testfake.TaskRun("ns", "migrate-3", "migrate", "2026-05-03T10:00:00Z", nil),
+		testfake.TaskRun("ns", "migrate-2", "migrate", "2026-05-02T10:00:00Z", nil),
+		testfake.TaskRun("ns", "other-1", "other", "2026-05-04T10:00:00Z", nil),
+	)
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{limit: 0, want: []string{"migrate-3", "migrate-2", "migrate-1"}},
+		{limit: 2, want: []string{"migrate-3", "migrate-2"}},
+	}
+	for _, tt := range tests {
+		runs, err := ListTaskRuns(context.Background(), client, "ns", "migrate", tt.limit)
+		if err != nil {
+			t.Fatalf("ListTaskRuns() error = %v", err)
+		}
+		var got []string
+		for _, run := range runs {
+			got = append(got, run.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ListTaskRuns(limit %d) = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+}
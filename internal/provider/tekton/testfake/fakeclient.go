@@ -33,13 +33,70 @@ var (
 		Version:  "v1beta1",
 		Resource: "stepactions",
 	}
+	TaskRunGVR = schema.GroupVersionResource{
+		Group:    "tekton.dev",
+		Version:  "v1beta1",
+		Resource: "taskruns",
+	}
+	NamespaceGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "namespaces",
+	}
+	ConfigMapGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "configmaps",
+	}
+	DeploymentGVR = schema.GroupVersionResource{
+		Group:    "apps",
+		Version:  "v1",
+		Resource: "deployments",
+	}
+	SecretGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "secrets",
+	}
+	ServiceAccountGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "serviceaccounts",
+	}
+	RoleGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "roles",
+	}
+	RoleBindingGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "rolebindings",
+	}
+	ClusterRoleGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterroles",
+	}
+	ClusterRoleBindingGVR = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterrolebindings",
+	}
 )
 
-// gvrToListKind maps the Tekton GVRs to their list kind. NewSimpleDynamicClient
+// gvrToListKind maps the Tekton, Namespace, ConfigMap, Deployment, Secret and RBAC GVRs to their list kind. NewSimpleDynamicClient
 // requires this for unstructured types not registered in any scheme.
 var gvrToListKind = map[schema.GroupVersionResource]string{
 	TaskGVR:       "TaskList",
 	StepActionGVR: "StepActionList",
+	TaskRunGVR:    "TaskRunList",
+	NamespaceGVR:  "NamespaceList",
+	ConfigMapGVR:  "ConfigMapList",
+	DeploymentGVR: "DeploymentList",
+	SecretGVR:     "SecretList",
+
+	ServiceAccountGVR:     "ServiceAccountList",
+	RoleGVR:               "RoleList",
+	RoleBindingGVR:        "RoleBindingList",
+	ClusterRoleGVR:        "ClusterRoleList",
+	ClusterRoleBindingGVR: "ClusterRoleBindingList",
 }
 
 // NewClient returns a fake dynamic.Interface seeded with the given objects.
@@ -1,7 +1,10 @@
 package testfake
 
 import (
+	"encoding/base64"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Task returns an *unstructured.Unstructured representing a minimal Tekton
@@ -45,3 +48,81 @@ func buildTektonObject(kind, namespace, name string, labels map[string]string) *
 
 	return obj
 }
+
+// Namespace returns a core/v1 Namespace with the given UID, e.g. kube-system
+// for tests that derive the cluster ID from its UID.
+func Namespace(name, uid string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]any{
+				"name": name,
+			},
+		},
+	}
+	obj.SetUID(types.UID(uid))
+	return obj
+}
+
+// Secret returns a core/v1 Opaque Secret whose data holds the given values,
+// base64-encoded like the apiserver returns them
+func Secret(namespace, name string, values map[string]string) *unstructured.Unstructured {
+	data := make(map[string]any, len(values))
+	for k, v := range values {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"namespace": namespace,
+				"name":      name,
+			},
+			"type": "Opaque",
+			"data": data,
+		},
+	}
+}
+
+// ConfigMap returns a core/v1 ConfigMap whose data holds the given values,
+// e.g. Tekton's feature-flags
+func ConfigMap(namespace, name string, values map[string]string) *unstructured.Unstructured {
+	data := make(map[string]any, len(values))
+	for k, v := range values {
+		data[k] = v
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"namespace": namespace,
+				"name":      name,
+			},
+			"data": data,
+		},
+	}
+}
+
+// TaskRun returns a TaskRun of the given Task, created at created (RFC 3339),
+// with the tekton.dev/task label Tekton sets and the given status
+func TaskRun(namespace, name, taskName, created string, status map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "tekton.dev/v1beta1",
+			"kind":       "TaskRun",
+			"metadata": map[string]any{
+				"namespace":         namespace,
+				"name":              name,
+				"creationTimestamp": created,
+				"labels":            map[string]any{"tekton.dev/task": taskName},
+			},
+			"spec": map[string]any{
+				"taskRef": map[string]any{"name": taskName},
+			},
+			"status": status,
+		},
+	}
+}
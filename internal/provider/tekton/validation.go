@@ -0,0 +1,302 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// dnsLabelRegex matches an RFC 1123 label, which Tekton requires for step names
+var dnsLabelRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// indexedObject is a known object element of a list together with its index
+type indexedObject struct {
+	index int
+	obj   types.Object
+}
+
+// knownObjects returns the known, non-null object elements of a list.
+// Unknown elements are skipped so validation never fails on values that are
+// only resolved during apply.
+func knownObjects(list types.List) []indexedObject {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	var result []indexedObject
+	for i, elem := range list.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsNull() || obj.IsUnknown() {
+			continue
+		}
+		result = append(result, indexedObject{index: i, obj: obj})
+	}
+	return result
+}
+
+// ValidateActionConfig checks user-defined steps, params and env vars for
+// collisions with the names injected by the provider, for duplicates, and
+// step names for Tekton's DNS-label rule. Unknown values are skipped.
+func ValidateActionConfig(ctx context.Context, steps, params types.List, reserved ReservedNames) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	stepsPath := path.Root("steps")
+	seenSteps := make(map[string]int)
+	for _, elem := range knownObjects(steps) {
+		var step StepModel
+		if d := elem.obj.As(ctx, &step, basetypes.ObjectAsOptions{}); d.HasError() {
+			diags.Append(d...)
+			return diags
+		}
+		stepPath := stepsPath.AtListIndex(elem.index)
+
+		if !step.Name.IsNull() && !step.Name.IsUnknown() {
+			name := step.Name.ValueString()
+			namePath := stepPath.AtName("name")
+			switch {
+			case containsName(reserved.Steps, name):
+				diags.AddAttributeError(namePath, "Reserved Step Name",
+					fmt.Sprintf("Step name %q is reserved: the provider injects a step with this name to set up credentials. Choose a different name.", name))
+			case len(name) > 63 || !dnsLabelRegex.MatchString(name):
+				diags.AddAttributeError(namePath, "Invalid Step Name",
+					fmt.Sprintf("Step name %q must be a valid DNS label: at most 63 lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character.", name))
+			}
+			if first, dup := seenSteps[name]; dup {
+				diags.AddAttributeError(namePath, "Duplicate Step Name",
+					fmt.Sprintf("Step name %q is already used by steps[%d]. Step names must be unique within a Task.", name, first))
+			} else {
+				seenSteps[name] = elem.index
+			}
+		}
+
+		seenEnv := make(map[string]int)
+		for _, envElem := range knownObjects(step.Env) {
+			var env EnvVarModel
+			if d := envElem.obj.As(ctx, &env, basetypes.ObjectAsOptions{}); d.HasError() {
+				diags.Append(d...)
+				return diags
+			}
+			if env.Name.IsNull() || env.Name.IsUnknown() {
+				continue
+			}
+			name := env.Name.ValueString()
+			envPath := stepPath.AtName("env").AtListIndex(envElem.index).AtName("name")
+			if containsName(reserved.Env, name) {
+				diags.AddAttributeError(envPath, "Reserved Environment Variable",
+					fmt.Sprintf("Environment variable %q is reserved: the provider sets it on every step to point at the injected credentials.", name))
+			}
+			if first, dup := seenEnv[name]; dup {
+				diags.AddAttributeError(envPath, "Duplicate Environment Variable",
+					fmt.Sprintf("Environment variable %q is already defined at env[%d] of this step.", name, first))
+			} else {
+				seenEnv[name] = envElem.index
+			}
+		}
+
+		if !step.SensitiveEnv.IsNull() && !step.SensitiveEnv.IsUnknown() {
+			names := make([]string, 0, len(step.SensitiveEnv.Elements()))
+			for name := range step.SensitiveEnv.Elements() {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				envPath := stepPath.AtName("sensitive_env").AtMapKey(name)
+				if containsName(reserved.Env, name) {
+					diags.AddAttributeError(envPath, "Reserved Environment Variable",
+						fmt.Sprintf("Environment variable %q is reserved: the provider sets it on every step to point at the injected credentials.", name))
+				}
+				if first, dup := seenEnv[name]; dup {
+					diags.AddAttributeError(envPath, "Duplicate Environment Variable",
+						fmt.Sprintf("Environment variable %q is already defined at env[%d] of this step. Set it in either env or sensitive_env.", name, first))
+				}
+			}
+		}
+	}
+
+	paramsPath := path.Root("params")
+	seenParams := make(map[string]int)
+	for _, elem := range knownObjects(params) {
+		var param ParamModel
+		if d := elem.obj.As(ctx, &param, basetypes.ObjectAsOptions{}); d.HasError() {
+			diags.Append(d...)
+			return diags
+		}
+		if param.Name.IsNull() || param.Name.IsUnknown() {
+			continue
+		}
+		name := param.Name.ValueString()
+		namePath := paramsPath.AtListIndex(elem.index).AtName("name")
+		if containsName(reserved.Params, name) {
+			diags.AddAttributeError(namePath, "Reserved Parameter Name",
+				fmt.Sprintf("Parameter %q is reserved: the provider declares it on every Task and the Facets UI populates it.", name))
+		}
+		if first, dup := seenParams[name]; dup {
+			diags.AddAttributeError(namePath, "Duplicate Parameter Name",
+				fmt.Sprintf("Parameter %q is already declared at params[%d]. Parameter names must be unique within a Task.", name, first))
+		} else {
+			seenParams[name] = elem.index
+		}
+	}
+
+	return diags
+}
+
+// ValidateComputeResources checks every step's resources block: resource names
+// must be cpu, memory, ephemeral-storage, hugepages-<size> or a domain-qualified
+// extended resource, quantities must parse with resource.ParseQuantity, and
+// requests must not exceed limits. Unknown values are skipped.
+func ValidateComputeResources(ctx context.Context, steps types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, elem := range knownObjects(steps) {
+		var step StepModel
+		if d := elem.obj.As(ctx, &step, basetypes.ObjectAsOptions{}); d.HasError() {
+			diags.Append(d...)
+			return diags
+		}
+		resourcesPath := path.Root("steps").AtListIndex(elem.index).AtName("resources")
+		diags.Append(ValidateComputeResourcesObject(ctx, step.Resources, resourcesPath)...)
+	}
+
+	return diags
+}
+
+// ValidateComputeResourcesObject applies the checks of ValidateComputeResources
+// to a single requests/limits object, reporting errors under resourcesPath.
+// Null and unknown objects are skipped.
+func ValidateComputeResourcesObject(ctx context.Context, resources types.Object, resourcesPath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if resources.IsNull() || resources.IsUnknown() {
+		return diags
+	}
+
+	var computeRes ComputeResourcesModel
+	if d := resources.As(ctx, &computeRes, basetypes.ObjectAsOptions{}); d.HasError() {
+		diags.Append(d...)
+		return diags
+	}
+
+	requests := parseQuantities(computeRes.Requests, resourcesPath.AtName("requests"), &diags)
+	limits := parseQuantities(computeRes.Limits, resourcesPath.AtName("limits"), &diags)
+
+	for _, name := range sortedKeys(requests) {
+		request := requests[name]
+		limit, hasLimit := limits[name]
+		if !hasLimit {
+			continue
+		}
+		requestPath := resourcesPath.AtName("requests").AtMapKey(name)
+		if isExtendedResource(name) && request.Cmp(limit) != 0 {
+			diags.AddAttributeError(requestPath, "Invalid Compute Resources",
+				fmt.Sprintf("Extended resource %q must have equal requests and limits, got requests=%s, limits=%s.", name, request.String(), limit.String()))
+			continue
+		}
+		if request.Cmp(limit) > 0 {
+			diags.AddAttributeError(requestPath, "Invalid Compute Resources",
+				fmt.Sprintf("Request for %q (%s) must be less than or equal to its limit (%s).", name, request.String(), limit.String()))
+		}
+	}
+
+	return diags
+}
+
+// parseQuantities validates the names and values of a requests/limits map and
+// returns the parsed quantities of the entries that are valid.
+func parseQuantities(m types.Map, mapPath path.Path, diags *diag.Diagnostics) map[string]resource.Quantity {
+	result := make(map[string]resource.Quantity)
+	if m.IsNull() || m.IsUnknown() {
+		return result
+	}
+
+	for name, elem := range m.Elements() {
+		value, ok := elem.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		entryPath := mapPath.AtMapKey(name)
+
+		if msg := validateResourceName(name); msg != "" {
+			diags.AddAttributeError(entryPath, "Invalid Compute Resource Name", msg)
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(entryPath, "Invalid Compute Resource Quantity",
+				fmt.Sprintf("Value %q for %q is not a valid Kubernetes quantity (e.g. \"500m\", \"2\", \"128Mi\", \"1Gi\"): %s", value.ValueString(), name, err.Error()))
+			continue
+		}
+		if quantity.Sign() < 0 {
+			diags.AddAttributeError(entryPath, "Invalid Compute Resource Quantity",
+				fmt.Sprintf("Value %q for %q must not be negative.", value.ValueString(), name))
+			continue
+		}
+		if isExtendedResource(name) && quantity.MilliValue()%1000 != 0 {
+			diags.AddAttributeError(entryPath, "Invalid Compute Resource Quantity",
+				fmt.Sprintf("Extended resource %q must be a whole number, got %q.", name, value.ValueString()))
+			continue
+		}
+
+		result[name] = quantity
+	}
+
+	return result
+}
+
+// validateResourceName returns an error message if name is not a compute
+// resource Kubernetes accepts on a container, or "" if it is valid
+func validateResourceName(name string) string {
+	switch {
+	case name == "cpu", name == "memory", name == "ephemeral-storage":
+		return ""
+	case strings.HasPrefix(name, "hugepages-"):
+		if _, err := resource.ParseQuantity(strings.TrimPrefix(name, "hugepages-")); err != nil {
+			return fmt.Sprintf("Resource name %q must specify a page size, e.g. \"hugepages-2Mi\".", name)
+		}
+		return ""
+	case isExtendedResource(name):
+		if errs := k8svalidation.IsQualifiedName(name); len(errs) > 0 {
+			return fmt.Sprintf("Extended resource name %q is invalid: %s", name, strings.Join(errs, "; "))
+		}
+		return ""
+	default:
+		return fmt.Sprintf("Resource name %q is not supported. Use cpu, memory, ephemeral-storage, hugepages-<size>, "+
+			"or a domain-qualified extended resource such as \"nvidia.com/gpu\".", name)
+	}
+}
+
+// isExtendedResource reports whether name is a domain-qualified resource
+// outside the kubernetes.io namespace, e.g. nvidia.com/gpu
+func isExtendedResource(name string) bool {
+	domain, _, found := strings.Cut(name, "/")
+	if !found {
+		return false
+	}
+	return domain != "kubernetes.io" && !strings.HasSuffix(domain, ".kubernetes.io")
+}
+
+func sortedKeys(m map[string]resource.Quantity) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
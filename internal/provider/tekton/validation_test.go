@@ -0,0 +1,345 @@
+package tekton
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testStep builds a steps list element with the given name and env var names.
+func testStep(name string, envNames ...string) attr.Value {
+	return testStepWithResources(name, types.ObjectNull(ComputeResourcesObjectType.AttrTypes), envNames...)
+}
+
+// testStepWithResources is like testStep but also sets the step's resources object.
+func testStepWithResources(name string, resources types.Object, envNames ...string) attr.Value {
+	env := types.ListNull(EnvVarObjectType)
+	if len(envNames) > 0 {
+		elems := make([]attr.Value, 0, len(envNames))
+		for _, n := range envNames {
+			elems = append(elems, types.ObjectValueMust(EnvVarObjectType.AttrTypes, map[string]attr.Value{
+				"name":  types.StringValue(n),
+				"value": types.StringValue("v"),
+			}))
+		}
+		env = types.ListValueMust(EnvVarObjectType, elems)
+	}
+	return types.ObjectValueMust(StepObjectType.AttrTypes, map[string]attr.Value{
+		"name":          types.StringValue(name),
+		"image":         types.StringValue("busybox:1.36"),
+		"script":        types.StringValue("echo hi"),
+		"resources":     resources,
+		"env":           env,
+		"sensitive_env": types.MapNull(types.StringType),
+	})
+}
+
+func testSteps(steps ...attr.Value) types.List {
+	return types.ListValueMust(StepObjectType, steps)
+}
+
+func testParams(names ...string) types.List {
+	elems := make([]attr.Value, 0, len(names))
+	for _, n := range names {
+		elems = append(elems, types.ObjectValueMust(ParamObjectType.AttrTypes, map[string]attr.Value{
+			"name": types.StringValue(n),
+			"type": types.StringValue("string"),
+		}))
+	}
+	return types.ListValueMust(ParamObjectType, elems)
+}
+
+// errorAt returns the first error diagnostic whose summary matches and whose
+// path equals p, or nil.
+func errorAt(diags diag.Diagnostics, summary string, p path.Path) diag.Diagnostic {
+	for _, d := range diags.Errors() {
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		if ok && d.Summary() == summary && withPath.Path().Equal(p) {
+			return d
+		}
+	}
+	return nil
+}
+
+func TestValidateActionConfig_Valid(t *testing.T) {
+	steps := testSteps(testStep("build", "LOG_LEVEL"), testStep("deploy", "LOG_LEVEL"))
+	params := testParams("REPLICAS", "DEPLOYMENT_NAME")
+
+	diags := ValidateActionConfig(context.Background(), steps, params, KubernetesReservedNames())
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+}
+
+func TestValidateActionConfig_ReservedNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    types.List
+		params   types.List
+		reserved ReservedNames
+		summary  string
+		path     path.Path
+	}{
+		{
+			name:     "setup-credentials step",
+			steps:    testSteps(testStep("build"), testStep("setup-credentials")),
+			params:   types.ListNull(ParamObjectType),
+			reserved: KubernetesReservedNames(),
+			summary:  "Reserved Step Name",
+			path:     path.Root("steps").AtListIndex(1).AtName("name"),
+		},
+		{
+			name:     "kubeconfig param",
+			steps:    testSteps(testStep("build")),
+			params:   testParams("REPLICAS", "FACETS_USER_KUBECONFIG"),
+			reserved: KubernetesReservedNames(),
+			summary:  "Reserved Parameter Name",
+			path:     path.Root("params").AtListIndex(1).AtName("name"),
+		},
+		{
+			name:     "KUBECONFIG env",
+			steps:    testSteps(testStep("build", "LOG_LEVEL", "KUBECONFIG")),
+			params:   types.ListNull(ParamObjectType),
+			reserved: KubernetesReservedNames(),
+			summary:  "Reserved Environment Variable",
+			path:     path.Root("steps").AtListIndex(0).AtName("env").AtListIndex(1).AtName("name"),
+		},
+		{
+			name:     "AWS_CONFIG_FILE env",
+			steps:    testSteps(testStep("build"), testStep("sync", "AWS_CONFIG_FILE")),
+			params:   types.ListNull(ParamObjectType),
+			reserved: AWSReservedNames(),
+			summary:  "Reserved Environment Variable",
+			path:     path.Root("steps").AtListIndex(1).AtName("env").AtListIndex(0).AtName("name"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := ValidateActionConfig(context.Background(), tt.steps, tt.params, tt.reserved)
+			if errorAt(diags, tt.summary, tt.path) == nil {
+				t.Errorf("expected %q error at %s, got %v", tt.summary, tt.path, diags)
+			}
+		})
+	}
+}
+
+func TestValidateActionConfig_AWSAllowsKubernetesNames(t *testing.T) {
+	// The AWS Task injects neither the kubeconfig param nor KUBECONFIG, so
+	// users may declare them.
+	steps := testSteps(testStep("build", "KUBECONFIG"))
+	params := testParams("FACETS_USER_KUBECONFIG")
+
+	diags := ValidateActionConfig(context.Background(), steps, params, AWSReservedNames())
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+}
+
+func TestValidateActionConfig_Duplicates(t *testing.T) {
+	steps := testSteps(testStep("build", "A", "B", "A"), testStep("deploy"), testStep("build"))
+	params := testParams("X", "Y", "X")
+
+	diags := ValidateActionConfig(context.Background(), steps, params, KubernetesReservedNames())
+
+	if d := errorAt(diags, "Duplicate Step Name", path.Root("steps").AtListIndex(2).AtName("name")); d == nil {
+		t.Errorf("expected duplicate step error at steps[2], got %v", diags)
+	} else if !strings.Contains(d.Detail(), "steps[0]") {
+		t.Errorf("duplicate step detail should reference the first occurrence, got %q", d.Detail())
+	}
+	if errorAt(diags, "Duplicate Environment Variable", path.Root("steps").AtListIndex(0).AtName("env").AtListIndex(2).AtName("name")) == nil {
+		t.Errorf("expected duplicate env error at steps[0].env[2], got %v", diags)
+	}
+	if errorAt(diags, "Duplicate Parameter Name", path.Root("params").AtListIndex(2).AtName("name")) == nil {
+		t.Errorf("expected duplicate param error at params[2], got %v", diags)
+	}
+	if len(diags.Errors()) != 3 {
+		t.Errorf("expected exactly 3 errors, got %d: %v", len(diags.Errors()), diags)
+	}
+}
+
+func TestValidateActionConfig_StepNameDNSLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"build", true},
+		{"run-migrations-2", true},
+		{"a", true},
+		{strings.Repeat("a", 63), true},
+		{strings.Repeat("a", 64), false},
+		{"Build", false},
+		{"run_migrations", false},
+		{"-build", false},
+		{"build-", false},
+		{"run migrations", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := ValidateActionConfig(context.Background(), testSteps(testStep(tt.name)), types.ListNull(ParamObjectType), KubernetesReservedNames())
+			got := errorAt(diags, "Invalid Step Name", path.Root("steps").AtListIndex(0).AtName("name")) == nil
+			if got != tt.valid {
+				t.Errorf("step name %q: valid=%v, want %v (diags: %v)", tt.name, got, tt.valid, diags)
+			}
+		})
+	}
+}
+
+func TestValidateActionConfig_UnknownValuesSkipped(t *testing.T) {
+	steps := types.ListValueMust(StepObjectType, []attr.Value{
+		testStep("build"),
+		types.ObjectUnknown(StepObjectType.AttrTypes),
+	})
+
+	diags := ValidateActionConfig(context.Background(), steps, types.ListUnknown(ParamObjectType), KubernetesReservedNames())
+	if diags.HasError() {
+		t.Fatalf("unknown values must not fail validation, got %v", diags)
+	}
+}
+
+// testResources builds a step resources object from requests/limits maps; a
+// nil map is rendered as null.
+func testResources(requests, limits map[string]string) types.Object {
+	toMap := func(m map[string]string) types.Map {
+		if m == nil {
+			return types.MapNull(types.StringType)
+		}
+		elems := make(map[string]attr.Value, len(m))
+		for k, v := range m {
+			elems[k] = types.StringValue(v)
+		}
+		return types.MapValueMust(types.StringType, elems)
+	}
+	return types.ObjectValueMust(ComputeResourcesObjectType.AttrTypes, map[string]attr.Value{
+		"requests": toMap(requests),
+		"limits":   toMap(limits),
+	})
+}
+
+func TestValidateComputeResources_Valid(t *testing.T) {
+	steps := testSteps(
+		testStepWithResources("build", testResources(
+			map[string]string{"cpu": "100m", "memory": "128Mi", "ephemeral-storage": "1Gi"},
+			map[string]string{"cpu": "500m", "memory": "512Mi", "ephemeral-storage": "1Gi"},
+		)),
+		testStepWithResources("train", testResources(
+			map[string]string{"nvidia.com/gpu": "1", "hugepages-2Mi": "64Mi"},
+			map[string]string{"nvidia.com/gpu": "1", "hugepages-2Mi": "64Mi"},
+		)),
+		testStepWithResources("requests-only", testResources(map[string]string{"memory": "1Gi"}, nil)),
+		testStep("no-resources"),
+	)
+
+	diags := ValidateComputeResources(context.Background(), steps)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+}
+
+func TestValidateComputeResources_Invalid(t *testing.T) {
+	requestsPath := func(step int, name string) path.Path {
+		return path.Root("steps").AtListIndex(step).AtName("resources").AtName("requests").AtMapKey(name)
+	}
+	limitsPath := func(step int, name string) path.Path {
+		return path.Root("steps").AtListIndex(step).AtName("resources").AtName("limits").AtMapKey(name)
+	}
+
+	tests := []struct {
+		name      string
+		resources types.Object
+		summary   string
+		path      path.Path
+	}{
+		{
+			name:      "memory with gb suffix",
+			resources: testResources(map[string]string{"memory": "1gb"}, nil),
+			summary:   "Invalid Compute Resource Quantity",
+			path:      requestsPath(1, "memory"),
+		},
+		{
+			name:      "cpu as word",
+			resources: testResources(nil, map[string]string{"cpu": "two"}),
+			summary:   "Invalid Compute Resource Quantity",
+			path:      limitsPath(1, "cpu"),
+		},
+		{
+			name:      "negative quantity",
+			resources: testResources(map[string]string{"cpu": "-1"}, nil),
+			summary:   "Invalid Compute Resource Quantity",
+			path:      requestsPath(1, "cpu"),
+		},
+		{
+			name:      "unknown resource name",
+			resources: testResources(map[string]string{"gpu": "1"}, nil),
+			summary:   "Invalid Compute Resource Name",
+			path:      requestsPath(1, "gpu"),
+		},
+		{
+			name:      "hugepages without size",
+			resources: testResources(map[string]string{"hugepages-": "1Gi"}, nil),
+			summary:   "Invalid Compute Resource Name",
+			path:      requestsPath(1, "hugepages-"),
+		},
+		{
+			name:      "kubernetes.io domain",
+			resources: testResources(nil, map[string]string{"kubernetes.io/foo": "1"}),
+			summary:   "Invalid Compute Resource Name",
+			path:      limitsPath(1, "kubernetes.io/foo"),
+		},
+		{
+			name:      "fractional extended resource",
+			resources: testResources(map[string]string{"nvidia.com/gpu": "500m"}, nil),
+			summary:   "Invalid Compute Resource Quantity",
+			path:      requestsPath(1, "nvidia.com/gpu"),
+		},
+		{
+			name: "requests exceed limits",
+			resources: testResources(
+				map[string]string{"memory": "1Gi", "cpu": "100m"},
+				map[string]string{"memory": "512Mi", "cpu": "1"},
+			),
+			summary: "Invalid Compute Resources",
+			path:    requestsPath(1, "memory"),
+		},
+		{
+			name: "extended resource requests differ from limits",
+			resources: testResources(
+				map[string]string{"nvidia.com/gpu": "1"},
+				map[string]string{"nvidia.com/gpu": "2"},
+			),
+			summary: "Invalid Compute Resources",
+			path:    requestsPath(1, "nvidia.com/gpu"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := testSteps(testStep("build"), testStepWithResources("deploy", tt.resources))
+			diags := ValidateComputeResources(context.Background(), steps)
+			if errorAt(diags, tt.summary, tt.path) == nil {
+				t.Errorf("expected %q error at %s, got %v", tt.summary, tt.path, diags)
+			}
+			if len(diags.Errors()) != 1 {
+				t.Errorf("expected exactly 1 error, got %d: %v", len(diags.Errors()), diags)
+			}
+		})
+	}
+}
+
+func TestValidateComputeResources_EqualQuantitiesInDifferentUnits(t *testing.T) {
+	steps := testSteps(testStepWithResources("build", testResources(
+		map[string]string{"memory": "1024Mi", "cpu": "1000m"},
+		map[string]string{"memory": "1Gi", "cpu": "1"},
+	)))
+
+	diags := ValidateComputeResources(context.Background(), steps)
+	if diags.HasError() {
+		t.Fatalf("equal quantities in different units must pass, got %v", diags)
+	}
+}